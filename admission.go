@@ -0,0 +1,175 @@
+package gocb
+
+import (
+	"sync"
+	"time"
+)
+
+// AdmissionControlConfig specifies the maximum number of operations which can be in flight at once,
+// so that a spike in traffic is pushed back to the application rather than queued within the SDK.
+// A limit of zero is unlimited.  Query, analytics, search and view requests are in flight until their
+// rows have been fully read or their result closed, so results must always be closed.
+// UNCOMMITTED: This API may change in the future.
+type AdmissionControlConfig struct {
+	// MaxInFlight limits the operations in flight across the cluster.
+	MaxInFlight int
+
+	// MaxInFlightPerBucket limits the key-value and view operations in flight against each bucket.
+	MaxInFlightPerBucket int
+
+	// MaxInFlightPerService limits the operations in flight against each service, e.g. ServiceTypeKeyValue,
+	// ServiceTypeQuery, ServiceTypeSearch or ServiceTypeAnalytics.
+	MaxInFlightPerService map[ServiceType]int
+
+	// QueueTimeout is how long an operation waits for a limit to allow it before failing with an error
+	// which satisfies errors.Is(err, ErrConcurrencyLimitReached).  If zero then operations which exceed a
+	// limit fail immediately.  Time spent waiting counts towards the timeout of the operation, so an
+	// operation never waits beyond its own timeout and is dispatched with only the time that remains.
+	QueueTimeout time.Duration
+}
+
+type admissionController struct {
+	cluster      *inFlightLimit
+	services     map[ServiceType]*inFlightLimit
+	perBucket    int
+	queueTimeout time.Duration
+
+	lock    sync.Mutex
+	buckets map[string]*inFlightLimit
+}
+
+type inFlightLimit struct {
+	slots      chan struct{}
+	limit      string
+	service    string
+	bucketName string
+}
+
+func newInFlightLimit(max int, limit, service, bucketName string) *inFlightLimit {
+	return &inFlightLimit{
+		slots:      make(chan struct{}, max),
+		limit:      limit,
+		service:    service,
+		bucketName: bucketName,
+	}
+}
+
+func (l *inFlightLimit) release() {
+	<-l.slots
+}
+
+func newAdmissionController(config AdmissionControlConfig) *admissionController {
+	controller := &admissionController{
+		services:     make(map[ServiceType]*inFlightLimit),
+		perBucket:    config.MaxInFlightPerBucket,
+		queueTimeout: config.QueueTimeout,
+		buckets:      make(map[string]*inFlightLimit),
+	}
+
+	if config.MaxInFlight > 0 {
+		controller.cluster = newInFlightLimit(config.MaxInFlight, "cluster", "", "")
+	}
+	for service, max := range config.MaxInFlightPerService {
+		if max > 0 {
+			controller.services[service] = newInFlightLimit(max, "service", serviceTypeToString(service), "")
+		}
+	}
+
+	if controller.cluster == nil && len(controller.services) == 0 && controller.perBucket <= 0 {
+		return nil
+	}
+
+	return controller
+}
+
+func (a *admissionController) limitsFor(service ServiceType, bucketName string) []*inFlightLimit {
+	limits := make([]*inFlightLimit, 0, 3)
+	if a.cluster != nil {
+		limits = append(limits, a.cluster)
+	}
+
+	if a.perBucket > 0 && bucketName != "" {
+		a.lock.Lock()
+		bucket, ok := a.buckets[bucketName]
+		if !ok {
+			bucket = newInFlightLimit(a.perBucket, "bucket", "", bucketName)
+			a.buckets[bucketName] = bucket
+		}
+		a.lock.Unlock()
+		limits = append(limits, bucket)
+	}
+
+	if limit, ok := a.services[service]; ok {
+		limits = append(limits, limit)
+	}
+
+	return limits
+}
+
+// admit waits for every limit which applies to an operation to allow it, returning a function which
+// must be called once the operation is no longer in flight.  The wait ends at the queue timeout or
+// at deadline, the deadline of the operation, whichever is sooner.  Limits are always taken in the
+// same order so that operations waiting on each other cannot deadlock.
+func (a *admissionController) admit(service ServiceType, bucketName string, deadline time.Time) (func(), error) {
+	if a == nil {
+		return func() {}, nil
+	}
+
+	limits := a.limitsFor(service, bucketName)
+
+	var expired <-chan time.Time
+	for i, limit := range limits {
+		select {
+		case limit.slots <- struct{}{}:
+			continue
+		default:
+		}
+
+		if a.queueTimeout > 0 {
+			if expired == nil {
+				wait := a.queueTimeout
+				if remaining := time.Until(deadline); remaining < wait {
+					wait = remaining
+				}
+				timer := time.NewTimer(wait)
+				defer timer.Stop()
+				expired = timer.C
+			}
+
+			select {
+			case limit.slots <- struct{}{}:
+				continue
+			case <-expired:
+			}
+		}
+
+		for _, acquired := range limits[:i] {
+			acquired.release()
+		}
+
+		return nil, &ConcurrencyLimitError{
+			InnerError:  ErrConcurrencyLimitReached,
+			Limit:       limit.limit,
+			Service:     limit.service,
+			BucketName:  limit.bucketName,
+			MaxInFlight: cap(limit.slots),
+		}
+	}
+
+	return func() {
+		for _, limit := range limits {
+			limit.release()
+		}
+	}, nil
+}
+
+// releaseOnComplete wraps reader so that release is called with the error of the stream, if any, once the
+// results have been fully read or closed.
+func releaseOnComplete(reader streamingRowReader, release func(streamErr error)) *completionRowReader {
+	return &completionRowReader{
+		reader: reader,
+		onComplete: func(bool) {
+			release(reader.Err())
+		},
+	}
+}
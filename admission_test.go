@@ -0,0 +1,168 @@
+package gocb
+
+import (
+	"errors"
+	"time"
+)
+
+func (suite *UnitTestSuite) TestAdmissionControlRejectsFast() {
+	admission := newAdmissionController(AdmissionControlConfig{MaxInFlight: 1})
+
+	release, err := admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+
+	_, err = admission.admit(ServiceTypeQuery, "", time.Now().Add(time.Minute))
+	suite.Require().True(errors.Is(err, ErrConcurrencyLimitReached))
+
+	var limitErr *ConcurrencyLimitError
+	suite.Require().True(errors.As(err, &limitErr))
+	suite.Assert().Equal("cluster", limitErr.Limit)
+	suite.Assert().Equal(1, limitErr.MaxInFlight)
+
+	release()
+	release, err = admission.admit(ServiceTypeQuery, "", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+	release()
+}
+
+func (suite *UnitTestSuite) TestAdmissionControlQueues() {
+	admission := newAdmissionController(AdmissionControlConfig{
+		MaxInFlightPerService: map[ServiceType]int{ServiceTypeKeyValue: 1},
+		QueueTimeout:          time.Second,
+	})
+
+	release, err := admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release()
+	}()
+
+	release, err = admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+	release()
+
+	// Services without a limit are not limited.
+	for i := 0; i < 5; i++ {
+		_, err = admission.admit(ServiceTypeSearch, "", time.Now().Add(time.Minute))
+		suite.Require().Nil(err)
+	}
+}
+
+func (suite *UnitTestSuite) TestAdmissionControlQueueTimeout() {
+	admission := newAdmissionController(AdmissionControlConfig{
+		MaxInFlight:           2,
+		MaxInFlightPerBucket:  1,
+		MaxInFlightPerService: map[ServiceType]int{ServiceTypeKeyValue: 5},
+		QueueTimeout:          5 * time.Millisecond,
+	})
+
+	release, err := admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+
+	_, err = admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	var limitErr *ConcurrencyLimitError
+	suite.Require().True(errors.As(err, &limitErr))
+	suite.Assert().Equal("bucket", limitErr.Limit)
+	suite.Assert().Equal("default", limitErr.BucketName)
+
+	// The cluster slot taken by the rejected operation must have been released.
+	otherRelease, err := admission.admit(ServiceTypeKeyValue, "other", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+
+	_, err = admission.admit(ServiceTypeQuery, "", time.Now().Add(time.Minute))
+	suite.Require().True(errors.As(err, &limitErr))
+	suite.Assert().Equal("cluster", limitErr.Limit)
+
+	otherRelease()
+	release()
+}
+
+func (suite *UnitTestSuite) TestAdmissionControlWaitsUntilDeadline() {
+	admission := newAdmissionController(AdmissionControlConfig{
+		MaxInFlight:  1,
+		QueueTimeout: time.Minute,
+	})
+
+	release, err := admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+	defer release()
+
+	// The operation timeout ends the wait long before the queue timeout.
+	start := time.Now()
+	_, err = admission.admit(ServiceTypeKeyValue, "default", start.Add(10*time.Millisecond))
+	suite.Require().True(errors.Is(err, ErrConcurrencyLimitReached))
+	suite.Assert().Less(int64(time.Since(start)), int64(time.Second))
+}
+
+func (suite *UnitTestSuite) TestKVOperationTimedFromStart() {
+	col := &Collection{
+		timeoutsConfig: kvTimeoutsConfig{
+			KVTimeout:        2500 * time.Millisecond,
+			KVDurableTimeout: 10 * time.Second,
+		},
+	}
+
+	suite.Assert().Equal(2500*time.Millisecond, col.operationTimeout("Get", nil, &GetOptions{}))
+	suite.Assert().Equal(time.Second, col.operationTimeout("Get", nil, &GetOptions{Timeout: time.Second}))
+	suite.Assert().Equal(10*time.Second, col.operationTimeout("Upsert", "v", &UpsertOptions{PersistTo: 1}))
+	suite.Assert().Equal(5*time.Second, col.operationTimeout("Do", make([]BulkOp, 2), &BulkOpOptions{}))
+
+	// Time spent before dispatch, such as waiting for admission, is taken from the timeout.
+	col.operationStart = time.Now().Add(-time.Second)
+	opm := &kvOpManager{parent: col, timeout: 2 * time.Second}
+	suite.Assert().WithinDuration(col.operationStart.Add(2*time.Second), opm.Deadline(), time.Millisecond)
+}
+
+func (suite *UnitTestSuite) TestAdmissionControlDisabled() {
+	suite.Assert().Nil(newAdmissionController(AdmissionControlConfig{QueueTimeout: time.Second}))
+
+	var admission *admissionController
+	release, err := admission.admit(ServiceTypeKeyValue, "default", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+	release()
+}
+
+func (suite *UnitTestSuite) TestQueryRejectedByConcurrencyLimit() {
+	c := &Cluster{
+		admission: newAdmissionController(AdmissionControlConfig{
+			MaxInFlightPerService: map[ServiceType]int{ServiceTypeQuery: 1},
+		}),
+	}
+
+	release, err := c.admission.admit(ServiceTypeQuery, "", time.Now().Add(time.Minute))
+	suite.Require().Nil(err)
+	defer release()
+
+	_, err = c.Query("SELECT 1=1", nil)
+	suite.Require().True(errors.Is(err, ErrConcurrencyLimitReached))
+
+	var limitErr *ConcurrencyLimitError
+	suite.Require().True(errors.As(err, &limitErr))
+	suite.Assert().Equal("service", limitErr.Limit)
+	suite.Assert().Equal("query", limitErr.Service)
+}
+
+func (suite *UnitTestSuite) TestQueryHoldsAdmissionUntilClosed() {
+	reader := &mockQueryRowReaderRows{
+		Rows:                   [][]byte{[]byte(`1`), []byte(`2`)},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Meta: []byte(`{}`)},
+	}
+	cluster := suite.queryCluster(false, reader, nil)
+	cluster.admission = newAdmissionController(AdmissionControlConfig{
+		MaxInFlightPerService: map[ServiceType]int{ServiceTypeQuery: 1},
+	})
+
+	result, err := cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	_, err = cluster.admission.admit(ServiceTypeQuery, "", time.Now().Add(time.Minute))
+	suite.Require().True(errors.Is(err, ErrConcurrencyLimitReached))
+
+	suite.Require().Nil(result.Close())
+
+	release, err := cluster.admission.admit(ServiceTypeQuery, "", time.Now().Add(time.Minute))
+	suite.Require().Nil(err, err)
+	release()
+}
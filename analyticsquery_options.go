@@ -1,7 +1,6 @@
 package gocb
 
 import (
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,8 +26,15 @@ type AnalyticsOptions struct {
 	Priority             bool
 	PositionalParameters []interface{}
 	NamedParameters      map[string]interface{}
-	Readonly             bool
-	ScanConsistency      AnalyticsScanConsistency
+
+	// NamedParametersStruct is a struct, or pointer to a struct, whose fields are used as named
+	// parameters, named according to their json tags.  It may be combined with NamedParameters, in
+	// which case NamedParameters takes precedence.
+	// UNCOMMITTED: This API may change in the future.
+	NamedParametersStruct interface{}
+
+	Readonly        bool
+	ScanConsistency AnalyticsScanConsistency
 
 	// Raw provides a way to provide extra parameters in the request body for the query.
 	Raw map[string]interface{}
@@ -36,7 +42,7 @@ type AnalyticsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 
-	parentSpan requestSpanContext
+	ParentSpan RequestSpanContext
 }
 
 func (opts *AnalyticsOptions) toMap() (map[string]interface{}, error) {
@@ -58,7 +64,7 @@ func (opts *AnalyticsOptions) toMap() (map[string]interface{}, error) {
 		}
 	}
 
-	if opts.PositionalParameters != nil && opts.NamedParameters != nil {
+	if opts.PositionalParameters != nil && (opts.NamedParameters != nil || opts.NamedParametersStruct != nil) {
 		return nil, makeInvalidArgumentsError("positional and named parameters must be used exclusively")
 	}
 
@@ -66,12 +72,19 @@ func (opts *AnalyticsOptions) toMap() (map[string]interface{}, error) {
 		execOpts["args"] = opts.PositionalParameters
 	}
 
+	if opts.NamedParametersStruct != nil {
+		params, err := namedParametersFromStruct(opts.NamedParametersStruct)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range params {
+			execOpts[namedParameterKey(key)] = value
+		}
+	}
+
 	if opts.NamedParameters != nil {
 		for key, value := range opts.NamedParameters {
-			if !strings.HasPrefix(key, "$") {
-				key = "$" + key
-			}
-			execOpts[key] = value
+			execOpts[namedParameterKey(key)] = value
 		}
 	}
 
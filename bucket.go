@@ -14,7 +14,13 @@ type Bucket struct {
 
 	transcoder           Transcoder
 	retryStrategyWrapper *retryStrategyWrapper
-	tracer               requestTracer
+	tracer               RequestTracer
+	meter                Meter
+	retryBudget          *RetryBudget
+	admission            *admissionController
+	runQuery             queryRunner
+	kvInterceptors       []KVInterceptor
+	serviceInterceptors  []ServiceInterceptor
 
 	useServerDurations bool
 	useMutationTokens  bool
@@ -33,7 +39,13 @@ func newBucket(c *Cluster, bucketName string) *Bucket {
 
 		retryStrategyWrapper: c.retryStrategyWrapper,
 
-		tracer: c.tracer,
+		tracer:              c.tracer,
+		meter:               c.meter,
+		retryBudget:         c.retryBudget,
+		admission:           c.admission,
+		runQuery:            c.Query,
+		kvInterceptors:      c.kvInterceptors,
+		serviceInterceptors: c.serviceInterceptors,
 
 		useServerDurations: c.useServerDurations,
 		useMutationTokens:  c.useMutationTokens,
@@ -47,13 +47,13 @@ type jsonManifestCollection struct {
 type CollectionManager struct {
 	mgmtProvider mgmtProvider
 	bucketName   string
-	tracer       requestTracer
+	tracer       RequestTracer
 }
 
 func (cm *CollectionManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logDebugf("failed to read http body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "failed to read http body: %s", err)
 		return nil
 	}
 
@@ -80,6 +80,7 @@ func (cm *CollectionManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtRe
 type GetAllScopesOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllScopes gets all scopes from the bucket.
@@ -88,7 +89,7 @@ func (cm *CollectionManager) GetAllScopes(opts *GetAllScopesOptions) ([]ScopeSpe
 		opts = &GetAllScopesOptions{}
 	}
 
-	span := cm.tracer.StartSpan("GetAllScopes", nil).
+	span := cm.tracer.StartSpan("GetAllScopes", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -166,6 +167,7 @@ func (cm *CollectionManager) GetAllScopes(opts *GetAllScopesOptions) ([]ScopeSpe
 type CreateCollectionOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateCollection creates a new collection on the bucket.
@@ -182,7 +184,7 @@ func (cm *CollectionManager) CreateCollection(spec CollectionSpec, opts *CreateC
 		opts = &CreateCollectionOptions{}
 	}
 
-	span := cm.tracer.StartSpan("CreateCollection", nil).
+	span := cm.tracer.StartSpan("CreateCollection", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -221,7 +223,7 @@ func (cm *CollectionManager) CreateCollection(spec CollectionSpec, opts *CreateC
 
 	err = resp.Body.Close()
 	if err != nil {
-		logDebugf("Failed to close socket (%s)", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to close socket (%s)", err)
 	}
 
 	return nil
@@ -231,6 +233,7 @@ func (cm *CollectionManager) CreateCollection(spec CollectionSpec, opts *CreateC
 type DropCollectionOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropCollection removes a collection.
@@ -247,7 +250,7 @@ func (cm *CollectionManager) DropCollection(spec CollectionSpec, opts *DropColle
 		opts = &DropCollectionOptions{}
 	}
 
-	span := cm.tracer.StartSpan("DropCollection", nil).
+	span := cm.tracer.StartSpan("DropCollection", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -277,7 +280,7 @@ func (cm *CollectionManager) DropCollection(spec CollectionSpec, opts *DropColle
 
 	err = resp.Body.Close()
 	if err != nil {
-		logDebugf("Failed to close socket (%s)", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to close socket (%s)", err)
 	}
 
 	return nil
@@ -287,6 +290,7 @@ func (cm *CollectionManager) DropCollection(spec CollectionSpec, opts *DropColle
 type CreateScopeOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateScope creates a new scope on the bucket.
@@ -299,7 +303,7 @@ func (cm *CollectionManager) CreateScope(scopeName string, opts *CreateScopeOpti
 		opts = &CreateScopeOptions{}
 	}
 
-	span := cm.tracer.StartSpan("CreateScope", nil).
+	span := cm.tracer.StartSpan("CreateScope", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -334,7 +338,7 @@ func (cm *CollectionManager) CreateScope(scopeName string, opts *CreateScopeOpti
 
 	err = resp.Body.Close()
 	if err != nil {
-		logDebugf("Failed to close socket (%s)", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to close socket (%s)", err)
 	}
 
 	return nil
@@ -344,6 +348,7 @@ func (cm *CollectionManager) CreateScope(scopeName string, opts *CreateScopeOpti
 type DropScopeOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropScope removes a scope.
@@ -352,7 +357,7 @@ func (cm *CollectionManager) DropScope(scopeName string, opts *DropScopeOptions)
 		opts = &DropScopeOptions{}
 	}
 
-	span := cm.tracer.StartSpan("DropScope", nil).
+	span := cm.tracer.StartSpan("DropScope", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -382,7 +387,7 @@ func (cm *CollectionManager) DropScope(scopeName string, opts *DropScopeOptions)
 
 	err = resp.Body.Close()
 	if err != nil {
-		logDebugf("Failed to close socket (%s)", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to close socket (%s)", err)
 	}
 
 	return nil
@@ -100,13 +100,13 @@ type ViewIndexManager struct {
 	mgmtProvider mgmtProvider
 	bucketName   string
 
-	tracer requestTracer
+	tracer RequestTracer
 }
 
 func (vm *ViewIndexManager) tryParseErrorMessage(req mgmtRequest, resp *mgmtResponse) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logDebugf("Failed to read view index manager response body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to read view index manager response body: %s", err)
 		return nil
 	}
 
@@ -121,7 +121,7 @@ func (vm *ViewIndexManager) tryParseErrorMessage(req mgmtRequest, resp *mgmtResp
 	var mgrErr bucketMgrErrorResp
 	err = json.Unmarshal(b, &mgrErr)
 	if err != nil {
-		logDebugf("Failed to unmarshal error body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to unmarshal error body: %s", err)
 		return makeGenericMgmtError(errors.New(string(b)), &req, resp)
 	}
 
@@ -154,6 +154,7 @@ func (vm *ViewIndexManager) doMgmtRequest(req mgmtRequest) (*mgmtResponse, error
 type GetDesignDocumentOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 func (vm *ViewIndexManager) ddocName(name string, namespace DesignDocumentNamespace) string {
@@ -176,13 +177,13 @@ func (vm *ViewIndexManager) GetDesignDocument(name string, namespace DesignDocum
 		opts = &GetDesignDocumentOptions{}
 	}
 
-	span := vm.tracer.StartSpan("GetDesignDocument", nil).SetTag("couchbase.service", "view")
+	span := vm.tracer.StartSpan("GetDesignDocument", opts.ParentSpan).SetTag("couchbase.service", "view")
 	defer span.Finish()
 
 	return vm.getDesignDocument(span.Context(), name, namespace, time.Now(), opts)
 }
 
-func (vm *ViewIndexManager) getDesignDocument(tracectx requestSpanContext, name string, namespace DesignDocumentNamespace,
+func (vm *ViewIndexManager) getDesignDocument(tracectx RequestSpanContext, name string, namespace DesignDocumentNamespace,
 	startTime time.Time, opts *GetDesignDocumentOptions) (*DesignDocument, error) {
 
 	name = vm.ddocName(name, namespace)
@@ -233,6 +234,7 @@ func (vm *ViewIndexManager) getDesignDocument(tracectx requestSpanContext, name
 type GetAllDesignDocumentsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllDesignDocuments will retrieve all design documents for the given bucket.
@@ -241,7 +243,7 @@ func (vm *ViewIndexManager) GetAllDesignDocuments(namespace DesignDocumentNamesp
 		opts = &GetAllDesignDocumentsOptions{}
 	}
 
-	span := vm.tracer.StartSpan("GetAllDesignDocuments", nil).SetTag("couchbase.service", "view")
+	span := vm.tracer.StartSpan("GetAllDesignDocuments", opts.ParentSpan).SetTag("couchbase.service", "view")
 	defer span.Finish()
 
 	req := mgmtRequest{
@@ -301,6 +303,7 @@ func (vm *ViewIndexManager) GetAllDesignDocuments(namespace DesignDocumentNamesp
 type UpsertDesignDocumentOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // UpsertDesignDocument will insert a design document to the given bucket, or update
@@ -310,14 +313,14 @@ func (vm *ViewIndexManager) UpsertDesignDocument(ddoc DesignDocument, namespace
 		opts = &UpsertDesignDocumentOptions{}
 	}
 
-	span := vm.tracer.StartSpan("UpsertDesignDocument", nil).SetTag("couchbase.service", "view")
+	span := vm.tracer.StartSpan("UpsertDesignDocument", opts.ParentSpan).SetTag("couchbase.service", "view")
 	defer span.Finish()
 
 	return vm.upsertDesignDocument(span.Context(), ddoc, namespace, time.Now(), opts)
 }
 
 func (vm *ViewIndexManager) upsertDesignDocument(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	ddoc DesignDocument,
 	namespace DesignDocumentNamespace,
 	startTime time.Time,
@@ -368,6 +371,7 @@ func (vm *ViewIndexManager) upsertDesignDocument(
 type DropDesignDocumentOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropDesignDocument will remove a design document from the given bucket.
@@ -376,13 +380,13 @@ func (vm *ViewIndexManager) DropDesignDocument(name string, namespace DesignDocu
 		opts = &DropDesignDocumentOptions{}
 	}
 
-	span := vm.tracer.StartSpan("DropDesignDocument", nil).SetTag("couchbase.service", "view")
+	span := vm.tracer.StartSpan("DropDesignDocument", opts.ParentSpan).SetTag("couchbase.service", "view")
 	defer span.Finish()
 
 	return vm.dropDesignDocument(span.Context(), name, namespace, time.Now(), opts)
 }
 
-func (vm *ViewIndexManager) dropDesignDocument(tracectx requestSpanContext, name string, namespace DesignDocumentNamespace,
+func (vm *ViewIndexManager) dropDesignDocument(tracectx RequestSpanContext, name string, namespace DesignDocumentNamespace,
 	startTime time.Time, opts *DropDesignDocumentOptions) error {
 
 	name = vm.ddocName(name, namespace)
@@ -417,6 +421,7 @@ func (vm *ViewIndexManager) dropDesignDocument(tracectx requestSpanContext, name
 type PublishDesignDocumentOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // PublishDesignDocument publishes a design document to the given bucket.
@@ -426,7 +431,7 @@ func (vm *ViewIndexManager) PublishDesignDocument(name string, opts *PublishDesi
 		opts = &PublishDesignDocumentOptions{}
 	}
 
-	span := vm.tracer.StartSpan("PublishDesignDocument", nil).
+	span := vm.tracer.StartSpan("PublishDesignDocument", opts.ParentSpan).
 		SetTag("couchbase.service", "view")
 	defer span.Finish()
 
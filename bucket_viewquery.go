@@ -140,12 +140,78 @@ func (r *ViewResult) MetaData() (*ViewMetaData, error) {
 }
 
 // ViewQuery performs a view query and returns a list of rows or an error.
-func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions) (*ViewResult, error) {
+func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions) (resOut *ViewResult, errOut error) {
 	if opts == nil {
 		opts = &ViewOptions{}
 	}
 
-	span := b.tracer.StartSpan("ViewQuery", opts.parentSpan).
+	start := time.Now()
+	history := &retryHistory{}
+	defer func() {
+		errOut = b.retryBudget.maybeMarkExhausted(errOut)
+		b.retryBudget.recordOutcome(errOut)
+		if errOut != nil {
+			recordOperationMetric(b.meter, ServiceTypeViews, "ViewQuery", start, errOut, history)
+		}
+	}()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = b.timeoutsConfig.ViewTimeout
+	}
+	release, err := b.admission.admit(ServiceTypeViews, b.Name(), start.Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errOut != nil {
+			release()
+			return
+		}
+
+		// The request remains in flight, and is timed, until its rows have been read or the results closed.
+		complete := func(streamErr error) {
+			release()
+			recordOperationMetric(b.meter, ServiceTypeViews, "ViewQuery", start, streamErr, history)
+		}
+		resOut.reader = releaseOnComplete(resOut.reader, complete)
+	}()
+
+	if len(b.serviceInterceptors) == 0 {
+		return b.viewQuery(designDoc, viewName, opts, start, history)
+	}
+
+	interceptedOpts := *opts
+	req := &ServiceInterceptorRequest{
+		Service:            ServiceTypeViews,
+		BucketName:         b.Name(),
+		DesignDocumentName: designDoc,
+		ViewName:           viewName,
+		Options:            &interceptedOpts,
+	}
+	interception, err := interceptServiceRequest(b.serviceInterceptors, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.viewQuery(req.DesignDocumentName, req.ViewName, &interceptedOpts, start, history)
+	if err != nil {
+		interception.complete(nil, err)
+		return nil, err
+	}
+
+	res.reader = interception.completionReader(res.reader, func() (interface{}, error) {
+		return res.MetaData()
+	})
+
+	return res, nil
+}
+
+// viewQuery executes the view query, timing out relative to start so that any time spent waiting for
+// admission counts towards the timeout.
+func (b *Bucket) viewQuery(designDoc string, viewName string, opts *ViewOptions, start time.Time,
+	history *retryHistory) (*ViewResult, error) {
+	span := b.tracer.StartSpan("ViewQuery", opts.ParentSpan).
 		SetTag("couchbase.service", "view")
 	defer span.Finish()
 
@@ -155,12 +221,9 @@ func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions)
 	if timeout == 0 {
 		timeout = b.timeoutsConfig.ViewTimeout
 	}
-	deadline := time.Now().Add(timeout)
+	deadline := start.Add(timeout)
 
-	retryWrapper := b.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryWrapper = newRetryStrategyWrapper(opts.RetryStrategy)
-	}
+	retryWrapper := b.retryStrategyWrapper.withHistory(history).forOperation(opts.RetryStrategy)
 
 	urlValues, err := opts.toURLValues()
 	if err != nil {
@@ -171,7 +234,7 @@ func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions)
 }
 
 func (b *Bucket) execViewQuery(
-	span requestSpanContext,
+	span RequestSpanContext,
 	viewType, ddoc, viewName string,
 	options url.Values,
 	deadline time.Time,
@@ -196,7 +259,7 @@ func (b *Bucket) execViewQuery(
 		TraceContext:       span,
 	})
 	if err != nil {
-		return nil, maybeEnhanceViewError(err)
+		return nil, wrapper.attachHistory(maybeEnhanceViewError(err))
 	}
 
 	return newViewResult(res), nil
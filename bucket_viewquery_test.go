@@ -182,7 +182,7 @@ func (suite *UnitTestSuite) TestViewQuery() {
 	var bucket *Bucket
 	bucket = suite.viewsBucket(reader, func(args mock.Arguments) {
 		opts := args.Get(0).(gocbcore.ViewQueryOptions)
-		suite.Assert().Equal(bucket.retryStrategyWrapper, opts.RetryStrategy)
+		suite.Assert().Equal(bucket.retryStrategyWrapper.wrapped, opts.RetryStrategy.(*retryStrategyWrapper).wrapped)
 		now := time.Now()
 		if opts.Deadline.Before(now.Add(70*time.Second)) || opts.Deadline.After(now.Add(75*time.Second)) {
 			suite.Fail("Deadline should have been <75s and >70s but was %s", opts.Deadline)
@@ -1,6 +1,10 @@
 package gocb
 
-import "time"
+import (
+	"errors"
+	"sync"
+	"time"
+)
 
 // CircuitBreakerCallback is the callback used by the circuit breaker to determine if an error should count toward
 // the circuit breaker failure count.
@@ -15,4 +19,236 @@ type CircuitBreakerConfig struct {
 	RollingWindow            time.Duration
 	CompletionCallback       CircuitBreakerCallback
 	CanaryTimeout            time.Duration
+
+	// StateChangeCallback is invoked whenever the estimated state of the key-value circuit breakers
+	// changes, see CircuitBreakerState for how the state is estimated.  It is invoked synchronously
+	// whilst processing responses so must not block.
+	// UNCOMMITTED: This API may change in the future.
+	StateChangeCallback func(change CircuitBreakerStateChange)
+}
+
+// CircuitBreakerState specifies the estimated state of the key-value circuit breakers.
+//
+// The underlying client keeps a circuit breaker for each key-value connection but does not expose
+// their state, so the SDK estimates it by applying the same configuration to every response and
+// cancellation that the underlying client passes to its CompletionCallback.  The callback does not
+// identify the connection, so the estimate combines the responses of every connection: the
+// breaker of a single failing connection may open whilst the estimate remains closed.  The canary
+// requests of the underlying client are not visible to the SDK either, so the estimate may briefly
+// disagree about when a breaker closes.
+// UNCOMMITTED: This API may change in the future.
+type CircuitBreakerState uint
+
+const (
+	// CircuitBreakerStateDisabled indicates that circuit breakers are disabled.
+	CircuitBreakerStateDisabled CircuitBreakerState = iota + 1
+
+	// CircuitBreakerStateClosed indicates that the circuit breakers are estimated to be allowing
+	// operations.
+	CircuitBreakerStateClosed
+
+	// CircuitBreakerStateHalfOpen indicates that the circuit breakers are estimated to be rejecting
+	// operations whilst a canary request checks whether the endpoint has recovered.
+	CircuitBreakerStateHalfOpen
+
+	// CircuitBreakerStateOpen indicates that the circuit breakers are estimated to be rejecting
+	// operations.
+	CircuitBreakerStateOpen
+)
+
+// CircuitBreakerStateChange describes the estimated state of the circuit breakers moving from one
+// state to another.
+// UNCOMMITTED: This API may change in the future.
+type CircuitBreakerStateChange struct {
+	From CircuitBreakerState
+	To   CircuitBreakerState
+
+	// ErrorPercentage is the percentage of responses within the rolling window which failed, at
+	// the time of the change.
+	ErrorPercentage float64
+	Time            time.Time
+}
+
+// circuitBreakerMonitor estimates the state of the circuit breakers of the underlying client, see
+// CircuitBreakerState.
+type circuitBreakerMonitor struct {
+	volumeThreshold          int64
+	errorThresholdPercentage float64
+	sleepWindow              time.Duration
+	rollingWindow            time.Duration
+	completionCallback       CircuitBreakerCallback
+	stateChangeCallback      func(change CircuitBreakerStateChange)
+
+	lock        sync.Mutex
+	current     CircuitBreakerState
+	windowStart time.Time
+	total       int64
+	failed      int64
+	openedAt    time.Time
+}
+
+func newCircuitBreakerMonitor(config CircuitBreakerConfig) *circuitBreakerMonitor {
+	if config.Disabled {
+		return nil
+	}
+
+	// These match the defaults of the underlying client.
+	if config.VolumeThreshold == 0 {
+		config.VolumeThreshold = 20
+	}
+	if config.ErrorThresholdPercentage == 0 {
+		config.ErrorThresholdPercentage = 50
+	}
+	if config.SleepWindow == 0 {
+		config.SleepWindow = 5 * time.Second
+	}
+	if config.RollingWindow == 0 {
+		config.RollingWindow = 1 * time.Minute
+	}
+	if config.CompletionCallback == nil {
+		config.CompletionCallback = func(err error) bool {
+			return !errors.Is(err, ErrTimeout)
+		}
+	}
+
+	m := &circuitBreakerMonitor{
+		volumeThreshold:          config.VolumeThreshold,
+		errorThresholdPercentage: config.ErrorThresholdPercentage,
+		sleepWindow:              config.SleepWindow,
+		rollingWindow:            config.RollingWindow,
+		completionCallback:       config.CompletionCallback,
+		stateChangeCallback:      config.StateChangeCallback,
+	}
+	m.reset(time.Now())
+
+	return m
+}
+
+// state returns the estimated state of the circuit breakers.
+func (m *circuitBreakerMonitor) state() CircuitBreakerState {
+	if m == nil {
+		return CircuitBreakerStateDisabled
+	}
+
+	m.lock.Lock()
+	change := m.maybeHalfOpen(time.Now())
+	state := m.current
+	m.lock.Unlock()
+
+	m.notify(change)
+	return state
+}
+
+// complete is the CompletionCallback given to the underlying client, which invokes it for every
+// response and cancellation that its circuit breakers count.  The outcome is counted by the
+// estimate before being returned to the underlying client.
+func (m *circuitBreakerMonitor) complete(err error) bool {
+	successful := m.completionCallback(maybeEnhanceKVErr(err, "", "", "", ""))
+	if successful {
+		m.markSuccessful()
+	} else {
+		m.markFailure()
+	}
+
+	return successful
+}
+
+func (m *circuitBreakerMonitor) markSuccessful() {
+	now := time.Now()
+	m.lock.Lock()
+	changes := []*CircuitBreakerStateChange{m.maybeHalfOpen(now)}
+	if m.current == CircuitBreakerStateHalfOpen {
+		changes = append(changes, m.transition(CircuitBreakerStateClosed, now))
+		m.reset(now)
+	} else {
+		m.maybeResetRollingWindow(now)
+		m.total++
+	}
+	m.lock.Unlock()
+
+	m.notify(changes...)
+}
+
+func (m *circuitBreakerMonitor) markFailure() {
+	now := time.Now()
+	m.lock.Lock()
+	changes := []*CircuitBreakerStateChange{m.maybeHalfOpen(now)}
+	switch m.current {
+	case CircuitBreakerStateHalfOpen:
+		changes = append(changes, m.transition(CircuitBreakerStateOpen, now))
+		m.openedAt = now
+	case CircuitBreakerStateClosed:
+		m.maybeResetRollingWindow(now)
+		m.total++
+		m.failed++
+		if m.total >= m.volumeThreshold && m.errorPercentage() >= m.errorThresholdPercentage {
+			changes = append(changes, m.transition(CircuitBreakerStateOpen, now))
+			m.openedAt = now
+		}
+	}
+	m.lock.Unlock()
+
+	m.notify(changes...)
+}
+
+func (m *circuitBreakerMonitor) maybeHalfOpen(now time.Time) *CircuitBreakerStateChange {
+	if m.current != CircuitBreakerStateOpen || now.Sub(m.openedAt) <= m.sleepWindow {
+		return nil
+	}
+
+	return m.transition(CircuitBreakerStateHalfOpen, now)
+}
+
+func (m *circuitBreakerMonitor) maybeResetRollingWindow(now time.Time) {
+	if now.Sub(m.windowStart) <= m.rollingWindow {
+		return
+	}
+
+	m.windowStart = now
+	m.total = 0
+	m.failed = 0
+}
+
+func (m *circuitBreakerMonitor) transition(to CircuitBreakerState, now time.Time) *CircuitBreakerStateChange {
+	change := &CircuitBreakerStateChange{
+		From:            m.current,
+		To:              to,
+		ErrorPercentage: m.errorPercentage(),
+		Time:            now,
+	}
+	m.current = to
+
+	return change
+}
+
+func (m *circuitBreakerMonitor) notify(changes ...*CircuitBreakerStateChange) {
+	for _, change := range changes {
+		if change == nil {
+			continue
+		}
+
+		logFieldsf(LogSubsystemKeyValue, LogDebug, nil,
+			"Estimated circuit breaker state moved from %s to %s at %.1f%% errors", circuitBreakerStateToString(change.From),
+			circuitBreakerStateToString(change.To), change.ErrorPercentage)
+
+		if m.stateChangeCallback != nil {
+			m.stateChangeCallback(*change)
+		}
+	}
+}
+
+func (m *circuitBreakerMonitor) reset(now time.Time) {
+	m.current = CircuitBreakerStateClosed
+	m.windowStart = now
+	m.total = 0
+	m.failed = 0
+	m.openedAt = time.Time{}
+}
+
+func (m *circuitBreakerMonitor) errorPercentage() float64 {
+	if m.total == 0 {
+		return 0
+	}
+
+	return float64(m.failed) / float64(m.total) * 100
 }
@@ -0,0 +1,109 @@
+package gocb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestCircuitBreakerMonitorTransitions() {
+	var changes []CircuitBreakerStateChange
+	monitor := newCircuitBreakerMonitor(CircuitBreakerConfig{
+		VolumeThreshold:          4,
+		ErrorThresholdPercentage: 50,
+		SleepWindow:              time.Millisecond,
+		StateChangeCallback: func(change CircuitBreakerStateChange) {
+			changes = append(changes, change)
+		},
+	})
+
+	suite.Assert().Equal(CircuitBreakerStateClosed, monitor.state())
+
+	suite.Assert().True(monitor.complete(nil))
+	suite.Assert().True(monitor.complete(nil))
+	suite.Assert().False(monitor.complete(gocbcore.ErrTimeout))
+	suite.Assert().Empty(changes)
+
+	suite.Assert().False(monitor.complete(gocbcore.ErrTimeout))
+	suite.Require().Len(changes, 1)
+	suite.Assert().Equal(CircuitBreakerStateClosed, changes[0].From)
+	suite.Assert().Equal(CircuitBreakerStateOpen, changes[0].To)
+	suite.Assert().Equal(float64(50), changes[0].ErrorPercentage)
+	suite.Assert().Equal(CircuitBreakerStateOpen, monitor.state())
+
+	time.Sleep(5 * time.Millisecond)
+	suite.Assert().Equal(CircuitBreakerStateHalfOpen, monitor.state())
+	suite.Require().Len(changes, 2)
+	suite.Assert().Equal(CircuitBreakerStateOpen, changes[1].From)
+	suite.Assert().Equal(CircuitBreakerStateHalfOpen, changes[1].To)
+
+	monitor.complete(nil)
+	suite.Require().Len(changes, 3)
+	suite.Assert().Equal(CircuitBreakerStateHalfOpen, changes[2].From)
+	suite.Assert().Equal(CircuitBreakerStateClosed, changes[2].To)
+	suite.Assert().Equal(CircuitBreakerStateClosed, monitor.state())
+
+	// Errors which the completion callback counts as successful do not open the breaker.
+	for i := 0; i < 10; i++ {
+		suite.Assert().True(monitor.complete(gocbcore.ErrDocumentNotFound))
+	}
+	suite.Assert().Len(changes, 3)
+}
+
+func (suite *UnitTestSuite) TestCircuitBreakerMonitorCompletionCallback() {
+	var seen []error
+	monitor := newCircuitBreakerMonitor(CircuitBreakerConfig{
+		VolumeThreshold: 1,
+		CompletionCallback: func(err error) bool {
+			seen = append(seen, err)
+			return !errors.Is(err, ErrTemporaryFailure)
+		},
+	})
+
+	// The callback receives the errors of the SDK and its answer is returned to the underlying client.
+	suite.Assert().False(monitor.complete(&gocbcore.KeyValueError{InnerError: gocbcore.ErrTemporaryFailure}))
+	suite.Require().Len(seen, 1)
+	suite.Assert().IsType(&KeyValueError{}, seen[0])
+	suite.Assert().Equal(CircuitBreakerStateOpen, monitor.state())
+}
+
+func (suite *UnitTestSuite) TestCircuitBreakerMonitorDisabled() {
+	monitor := newCircuitBreakerMonitor(CircuitBreakerConfig{Disabled: true})
+	suite.Assert().Nil(monitor)
+	suite.Assert().Equal(CircuitBreakerStateDisabled, monitor.state())
+}
+
+func (suite *UnitTestSuite) TestDiagnosticsCircuitBreakerState() {
+	info := &gocbcore.DiagnosticInfo{
+		MemdConns: []gocbcore.MemdConnInfo{
+			{
+				RemoteAddr: "10.112.191.102:11210",
+				State:      gocbcore.EndpointStateConnected,
+			},
+		},
+	}
+
+	provider := new(mockDiagnosticsProvider)
+	provider.
+		On("Diagnostics", mock.AnythingOfType("gocbcore.DiagnosticsOptions")).
+		Return(info, nil)
+
+	cli := new(mockConnectionManager)
+	cli.On("getDiagnosticsProvider", "").Return(provider, nil)
+
+	c := &Cluster{
+		connectionManager: cli,
+		breakers:          newCircuitBreakerMonitor(CircuitBreakerConfig{VolumeThreshold: 1}),
+	}
+	c.breakers.complete(gocbcore.ErrTimeout)
+
+	report, err := c.Diagnostics(nil)
+	suite.Require().Nil(err)
+	suite.Assert().Equal(CircuitBreakerStateOpen, report.CircuitBreakerState)
+
+	data, err := report.MarshalJSON()
+	suite.Require().Nil(err)
+	suite.Assert().Contains(string(data), `"circuit_breaker":"open"`)
+}
@@ -26,9 +26,10 @@ type connectionManager interface {
 }
 
 type stdConnectionMgr struct {
-	lock       sync.Mutex
-	agentgroup *gocbcore.AgentGroup
-	config     *gocbcore.AgentGroupConfig
+	lock           sync.Mutex
+	agentgroup     *gocbcore.AgentGroup
+	config         *gocbcore.AgentGroupConfig
+	orphanReporter *orphanReporter
 }
 
 func newConnectionMgr() *stdConnectionMgr {
@@ -43,11 +44,8 @@ func (c *stdConnectionMgr) buildConfig(cluster *Cluster) error {
 	breakerCfg := cluster.circuitBreakerConfig
 
 	var completionCallback func(err error) bool
-	if breakerCfg.CompletionCallback != nil {
-		completionCallback = func(err error) bool {
-			wrappedErr := maybeEnhanceKVErr(err, "", "", "", "")
-			return breakerCfg.CompletionCallback(wrappedErr)
-		}
+	if cluster.breakers != nil {
+		completionCallback = cluster.breakers.complete
 	}
 
 	var tlsRootCAProvider func() *x509.CertPool
@@ -106,6 +104,7 @@ func (c *stdConnectionMgr) buildConfig(cluster *Cluster) error {
 	}
 
 	c.config = config
+	c.orphanReporter = cluster.orphanReporter
 	return nil
 }
 
@@ -126,7 +125,15 @@ func (c *stdConnectionMgr) openBucket(bucketName string) error {
 		return errors.New("cluster not yet connected")
 	}
 
-	return c.agentgroup.OpenBucket(bucketName)
+	if err := c.agentgroup.OpenBucket(bucketName); err != nil {
+		return err
+	}
+
+	if agent := c.agentgroup.GetAgent(bucketName); agent != nil {
+		c.orphanReporter.addClientID(agent.ClientID())
+	}
+
+	return nil
 }
 
 func (c *stdConnectionMgr) getKvProvider(bucketName string) (kvProvider, error) {
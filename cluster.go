@@ -29,12 +29,21 @@ type Cluster struct {
 	orphanLoggerEnabled    bool
 	orphanLoggerInterval   time.Duration
 	orphanLoggerSampleSize uint32
+	orphanReporter         *orphanReporter
 
-	tracer requestTracer
+	tracer      RequestTracer
+	meter       Meter
+	retryBudget *RetryBudget
+
+	kvInterceptors      []KVInterceptor
+	serviceInterceptors []ServiceInterceptor
 
 	circuitBreakerConfig CircuitBreakerConfig
+	breakers             *circuitBreakerMonitor
+	admission            *admissionController
 	securityConfig       SecurityConfig
 	internalConfig       InternalConfig
+	recordReplayConfig   RecordReplayConfig
 }
 
 // IoConfig specifies IO related configuration options.
@@ -59,10 +68,33 @@ type TimeoutsConfig struct {
 // OrphanReporterConfig specifies options for controlling the orphan
 // reporter which records when the SDK receives responses for requests
 // that are no longer in the system (usually due to being timed out).
+//
+// The reporter logs at most SampleSize of the slowest orphaned responses
+// of each bucket each ReportInterval, only these responses are delivered to
+// Callback and Channel and included in Cluster.OrphanStats.  Each cluster
+// only receives the orphaned responses of its own connections.
+//
+// The underlying client only reports orphaned responses by logging them, so
+// the SDK reads them from its log messages through a logger which is
+// installed on the underlying client when this package is initialised.
+// Setting a logger with gocb.SetLogger or gocb.SetStructuredLogger keeps
+// reporting working, but calling gocbcore.SetLogger directly replaces that
+// logger and turns Callback, Channel and Cluster.OrphanStats off.  Reporting
+// also depends on the format of the underlying client's log message, which
+// a new version of the underlying client could change.
 type OrphanReporterConfig struct {
 	Disabled       bool
 	ReportInterval time.Duration
 	SampleSize     uint32
+
+	// Callback is invoked for every reported orphaned response.
+	// UNCOMMITTED: This API may change in the future.
+	Callback func(response OrphanedResponse)
+
+	// Channel receives every reported orphaned response, responses are
+	// dropped rather than blocking if the channel is not ready.
+	// UNCOMMITTED: This API may change in the future.
+	Channel chan<- OrphanedResponse
 }
 
 // SecurityConfig specifies options for controlling security related
@@ -105,22 +137,56 @@ type ClusterOptions struct {
 	// RetryStrategy is used to automatically retry operations if they fail.
 	RetryStrategy RetryStrategy
 
+	// RetryBudget limits the retries made by the RetryStrategy, and by any per-operation retry strategy,
+	// to a fraction of recent successful operations.
+	// UNCOMMITTED: This API may change in the future.
+	RetryBudget *RetryBudget
+
+	// RetryObserver is invoked for every decision made by the retry strategy of any operation.
+	// The decisions made for an operation are also attached to the KeyValueError, QueryError or
+	// TimeoutError returned if the operation fails.
+	// UNCOMMITTED: This API may change in the future.
+	RetryObserver RetryObserver
+
 	// Tracer specifies the tracer to use for requests.
 	// VOLATILE: This API is subject to change at any time.
-	Tracer requestTracer
+	Tracer RequestTracer
+
+	// Meter specifies the meter which records the outcome of every key-value, query, analytics, search
+	// and view operation.  No metrics are recorded if no meter is specified.
+	// UNCOMMITTED: This API may change in the future.
+	Meter Meter
 
 	// OrphanReporterConfig specifies options for the orphan reporter.
 	OrphanReporterConfig OrphanReporterConfig
 
+	// KVInterceptors specifies the interceptors which are invoked, in order, for every key-value and
+	// sub-document operation performed against any collection opened from this cluster.
+	// UNCOMMITTED: This API may change in the future.
+	KVInterceptors []KVInterceptor
+
+	// ServiceInterceptors specifies the interceptors which are invoked, in order, for every query,
+	// analytics, search and view request performed using this cluster.
+	// UNCOMMITTED: This API may change in the future.
+	ServiceInterceptors []ServiceInterceptor
+
 	// CircuitBreakerConfig specifies options for the circuit breakers.
 	CircuitBreakerConfig CircuitBreakerConfig
 
+	// AdmissionControlConfig limits the number of operations which can be in flight at once.
+	// UNCOMMITTED: This API may change in the future.
+	AdmissionControlConfig AdmissionControlConfig
+
 	// IoConfig specifies IO related configuration options.
 	IoConfig IoConfig
 
 	// SecurityConfig specifies security related configuration options.
 	SecurityConfig SecurityConfig
 
+	// RecordReplayConfig specifies options for recording or replaying service responses.
+	// UNCOMMITTED: This API may change in the future.
+	RecordReplayConfig RecordReplayConfig
+
 	// Internal: This should never be used and is not supported.
 	InternalConfig InternalConfig
 }
@@ -186,13 +252,18 @@ func clusterFromOptions(opts ClusterOptions) *Cluster {
 		useServerDurations = false
 	}
 
-	var initialTracer requestTracer
+	var initialTracer RequestTracer
 	if opts.Tracer != nil {
 		initialTracer = opts.Tracer
 	} else {
 		initialTracer = NewThresholdLoggingTracer(nil)
 	}
 	tracerAddRef(initialTracer)
+	meterAddRef(opts.Meter)
+
+	retryStrategyWrapper := newRetryStrategyWrapper(opts.RetryStrategy)
+	retryStrategyWrapper.budget = opts.RetryBudget
+	retryStrategyWrapper.observer = opts.RetryObserver
 
 	return &Cluster{
 		auth: opts.Authenticator,
@@ -208,21 +279,29 @@ func clusterFromOptions(opts ClusterOptions) *Cluster {
 		},
 		transcoder:             opts.Transcoder,
 		useMutationTokens:      useMutationTokens,
-		retryStrategyWrapper:   newRetryStrategyWrapper(opts.RetryStrategy),
+		retryStrategyWrapper:   retryStrategyWrapper,
 		orphanLoggerEnabled:    !opts.OrphanReporterConfig.Disabled,
 		orphanLoggerInterval:   opts.OrphanReporterConfig.ReportInterval,
 		orphanLoggerSampleSize: opts.OrphanReporterConfig.SampleSize,
+		orphanReporter:         newOrphanReporter(opts.OrphanReporterConfig),
 		useServerDurations:     useServerDurations,
 		tracer:                 initialTracer,
+		meter:                  opts.Meter,
+		retryBudget:            opts.RetryBudget,
+		kvInterceptors:         opts.KVInterceptors,
+		serviceInterceptors:    opts.ServiceInterceptors,
 		circuitBreakerConfig:   opts.CircuitBreakerConfig,
+		breakers:               newCircuitBreakerMonitor(opts.CircuitBreakerConfig),
+		admission:              newAdmissionController(opts.AdmissionControlConfig),
 		securityConfig:         opts.SecurityConfig,
 		internalConfig:         opts.InternalConfig,
+		recordReplayConfig:     opts.RecordReplayConfig,
 	}
 }
 
 // Connect creates and returns a Cluster instance created using the
 // provided options and a connection string.
-func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
+func Connect(connStr string, opts ClusterOptions) (clusterOut *Cluster, errOut error) {
 	connSpec, err := gocbconnstr.Parse(connStr)
 	if err != nil {
 		return nil, err
@@ -235,11 +314,29 @@ func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
 	cluster := clusterFromOptions(opts)
 	cluster.cSpec = connSpec
 
+	// The cluster holds references to the tracer and meter which must be released if it is not returned.
+	defer func() {
+		if errOut != nil {
+			tracerDecRef(cluster.tracer)
+			meterDecRef(cluster.meter)
+		}
+	}()
+
 	err = cluster.parseExtraConnStrOptions(connSpec)
 	if err != nil {
 		return nil, err
 	}
 
+	recordReplayCfg := cluster.recordReplayConfig
+	if recordReplayCfg.Mode != RecordReplayModeDisabled && recordReplayCfg.Directory == "" {
+		return nil, makeInvalidArgumentsError("a directory must be specified when recording or replaying responses")
+	}
+
+	if recordReplayCfg.Mode == RecordReplayModeReplay {
+		cluster.connectionManager = newReplayConnectionMgr(recordReplayCfg.Directory)
+		return cluster, nil
+	}
+
 	cli := newConnectionMgr()
 	err = cli.buildConfig(cluster)
 	if err != nil {
@@ -252,6 +349,23 @@ func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
 	}
 	cluster.connectionManager = cli
 
+	if recordReplayCfg.Mode == RecordReplayModeRecord {
+		recordingMgr, err := newRecordingConnectionMgr(cli, recordReplayCfg.Directory)
+		if err != nil {
+			closeErr := cli.close()
+			if closeErr != nil {
+				logFieldsf(LogSubsystemSDK, LogWarn, nil,
+					"Failed to close cluster connectionManager after recording setup failure: %s", closeErr)
+			}
+			return nil, err
+		}
+		cluster.connectionManager = recordingMgr
+	}
+
+	if cluster.orphanLoggerEnabled {
+		registerOrphanReporter(cluster.orphanReporter)
+	}
+
 	return cluster, nil
 }
 
@@ -378,7 +492,8 @@ func (c *Cluster) Close(opts *ClusterCloseOptions) error {
 	if c.connectionManager != nil {
 		err := c.connectionManager.close()
 		if err != nil {
-			logWarnf("Failed to close cluster connectionManager in cluster close: %s", err)
+			logFieldsf(LogSubsystemSDK, LogWarn, nil,
+				"Failed to close cluster connectionManager in cluster close: %s", err)
 			overallErr = err
 		}
 	}
@@ -388,9 +503,29 @@ func (c *Cluster) Close(opts *ClusterCloseOptions) error {
 		c.tracer = nil
 	}
 
+	if c.meter != nil {
+		meterDecRef(c.meter)
+		c.meter = nil
+	}
+
+	if c.orphanReporter != nil {
+		unregisterOrphanReporter(c.orphanReporter)
+	}
+
 	return overallErr
 }
 
+// OrphanStats returns the aggregated statistics of the orphaned responses
+// reported since the cluster was connected.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) OrphanStats() OrphanStats {
+	if c.orphanReporter == nil {
+		return OrphanStats{}
+	}
+
+	return c.orphanReporter.Stats()
+}
+
 func (c *Cluster) getDiagnosticsProvider() (diagnosticsProvider, error) {
 	provider, err := c.connectionManager.getDiagnosticsProvider("")
 	if err != nil {
@@ -466,6 +601,7 @@ func (c *Cluster) AnalyticsIndexes() *AnalyticsIndexManager {
 func (c *Cluster) QueryIndexes() *QueryIndexManager {
 	return &QueryIndexManager{
 		provider:      c,
+		mgmtProvider:  c,
 		globalTimeout: c.timeoutsConfig.ManagementTimeout,
 		tracer:        c.tracer,
 	}
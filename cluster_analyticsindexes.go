@@ -13,7 +13,7 @@ type AnalyticsIndexManager struct {
 	mgmtProvider mgmtProvider
 
 	globalTimeout time.Duration
-	tracer        requestTracer
+	tracer        RequestTracer
 }
 
 type analyticsIndexQueryProvider interface {
@@ -35,7 +35,7 @@ func (am *AnalyticsIndexManager) doAnalyticsQuery(q string, opts *AnalyticsOptio
 		var row json.RawMessage
 		err := result.Row(&row)
 		if err != nil {
-			logWarnf("management operation failed to read row: %s", err)
+			logFieldsf(LogSubsystemManagement, LogWarn, nil, "management operation failed to read row: %s", err)
 		} else {
 			rows = append(rows, row)
 		}
@@ -111,6 +111,7 @@ type CreateAnalyticsDataverseOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateDataverse creates a new analytics dataset.
@@ -125,7 +126,7 @@ func (am *AnalyticsIndexManager) CreateDataverse(dataverseName string, opts *Cre
 		}
 	}
 
-	span := am.tracer.StartSpan("CreateDataverse", nil).
+	span := am.tracer.StartSpan("CreateDataverse", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -138,7 +139,7 @@ func (am *AnalyticsIndexManager) CreateDataverse(dataverseName string, opts *Cre
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -153,6 +154,7 @@ type DropAnalyticsDataverseOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropDataverse drops an analytics dataset.
@@ -161,7 +163,7 @@ func (am *AnalyticsIndexManager) DropDataverse(dataverseName string, opts *DropA
 		opts = &DropAnalyticsDataverseOptions{}
 	}
 
-	span := am.tracer.StartSpan("DropDataverse", nil).
+	span := am.tracer.StartSpan("DropDataverse", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -174,7 +176,7 @@ func (am *AnalyticsIndexManager) DropDataverse(dataverseName string, opts *DropA
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -191,6 +193,7 @@ type CreateAnalyticsDatasetOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateDataset creates a new analytics dataset.
@@ -205,7 +208,7 @@ func (am *AnalyticsIndexManager) CreateDataset(datasetName, bucketName string, o
 		}
 	}
 
-	span := am.tracer.StartSpan("CreateDataset", nil).
+	span := am.tracer.StartSpan("CreateDataset", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -232,7 +235,7 @@ func (am *AnalyticsIndexManager) CreateDataset(datasetName, bucketName string, o
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -248,6 +251,7 @@ type DropAnalyticsDatasetOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropDataset drops an analytics dataset.
@@ -256,7 +260,7 @@ func (am *AnalyticsIndexManager) DropDataset(datasetName string, opts *DropAnaly
 		opts = &DropAnalyticsDatasetOptions{}
 	}
 
-	span := am.tracer.StartSpan("DropDataset", nil).
+	span := am.tracer.StartSpan("DropDataset", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -275,7 +279,7 @@ func (am *AnalyticsIndexManager) DropDataset(datasetName string, opts *DropAnaly
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -288,6 +292,7 @@ func (am *AnalyticsIndexManager) DropDataset(datasetName string, opts *DropAnaly
 type GetAllAnalyticsDatasetsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllDatasets gets all analytics datasets.
@@ -296,7 +301,7 @@ func (am *AnalyticsIndexManager) GetAllDatasets(opts *GetAllAnalyticsDatasetsOpt
 		opts = &GetAllAnalyticsDatasetsOptions{}
 	}
 
-	span := am.tracer.StartSpan("GetAllDatasets", nil).
+	span := am.tracer.StartSpan("GetAllDatasets", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -304,7 +309,7 @@ func (am *AnalyticsIndexManager) GetAllDatasets(opts *GetAllAnalyticsDatasetsOpt
 	rows, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return nil, err
@@ -334,6 +339,7 @@ type CreateAnalyticsIndexOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateIndex creates a new analytics dataset.
@@ -353,7 +359,7 @@ func (am *AnalyticsIndexManager) CreateIndex(datasetName, indexName string, fiel
 		}
 	}
 
-	span := am.tracer.StartSpan("CreateIndex", nil).
+	span := am.tracer.StartSpan("CreateIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -377,7 +383,7 @@ func (am *AnalyticsIndexManager) CreateIndex(datasetName, indexName string, fiel
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -393,6 +399,7 @@ type DropAnalyticsIndexOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropIndex drops an analytics index.
@@ -401,7 +408,7 @@ func (am *AnalyticsIndexManager) DropIndex(datasetName, indexName string, opts *
 		opts = &DropAnalyticsIndexOptions{}
 	}
 
-	span := am.tracer.StartSpan("DropIndex", nil).
+	span := am.tracer.StartSpan("DropIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -420,7 +427,7 @@ func (am *AnalyticsIndexManager) DropIndex(datasetName, indexName string, opts *
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -433,6 +440,7 @@ func (am *AnalyticsIndexManager) DropIndex(datasetName, indexName string, opts *
 type GetAllAnalyticsIndexesOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllIndexes gets all analytics indexes.
@@ -441,7 +449,7 @@ func (am *AnalyticsIndexManager) GetAllIndexes(opts *GetAllAnalyticsIndexesOptio
 		opts = &GetAllAnalyticsIndexesOptions{}
 	}
 
-	span := am.tracer.StartSpan("GetAllIndexes", nil).
+	span := am.tracer.StartSpan("GetAllIndexes", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -449,7 +457,7 @@ func (am *AnalyticsIndexManager) GetAllIndexes(opts *GetAllAnalyticsIndexesOptio
 	rows, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return nil, err
@@ -478,6 +486,7 @@ type ConnectAnalyticsLinkOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // ConnectLink connects an analytics link.
@@ -486,7 +495,7 @@ func (am *AnalyticsIndexManager) ConnectLink(opts *ConnectAnalyticsLinkOptions)
 		opts = &ConnectAnalyticsLinkOptions{}
 	}
 
-	span := am.tracer.StartSpan("ConnectLink", nil).
+	span := am.tracer.StartSpan("ConnectLink", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -498,7 +507,7 @@ func (am *AnalyticsIndexManager) ConnectLink(opts *ConnectAnalyticsLinkOptions)
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -513,6 +522,7 @@ type DisconnectAnalyticsLinkOptions struct {
 
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DisconnectLink disconnects an analytics link.
@@ -521,7 +531,7 @@ func (am *AnalyticsIndexManager) DisconnectLink(opts *DisconnectAnalyticsLinkOpt
 		opts = &DisconnectAnalyticsLinkOptions{}
 	}
 
-	span := am.tracer.StartSpan("DisconnectLink", nil).
+	span := am.tracer.StartSpan("DisconnectLink", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -533,7 +543,7 @@ func (am *AnalyticsIndexManager) DisconnectLink(opts *DisconnectAnalyticsLinkOpt
 	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
-		parentSpan:    span,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return err
@@ -546,6 +556,7 @@ func (am *AnalyticsIndexManager) DisconnectLink(opts *DisconnectAnalyticsLinkOpt
 type GetPendingMutationsAnalyticsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetPendingMutations returns the number of pending mutations for all indexes in the form of dataverse.dataset:mutations.
@@ -554,7 +565,7 @@ func (am *AnalyticsIndexManager) GetPendingMutations(opts *GetPendingMutationsAn
 		opts = &GetPendingMutationsAnalyticsOptions{}
 	}
 
-	span := am.tracer.StartSpan("GetPendingMutations", nil).
+	span := am.tracer.StartSpan("GetPendingMutations", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -590,7 +601,7 @@ func (am *AnalyticsIndexManager) GetPendingMutations(opts *GetPendingMutationsAn
 
 	err = resp.Body.Close()
 	if err != nil {
-		logDebugf("Failed to close socket (%s)", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to close socket (%s)", err)
 	}
 
 	return pending, nil
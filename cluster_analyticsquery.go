@@ -49,12 +49,12 @@ type AnalyticsMetrics struct {
 func (metrics *AnalyticsMetrics) fromData(data jsonAnalyticsMetrics) error {
 	elapsedTime, err := time.ParseDuration(data.ElapsedTime)
 	if err != nil {
-		logDebugf("Failed to parse query metrics elapsed time: %s", err)
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to parse query metrics elapsed time: %s", err)
 	}
 
 	executionTime, err := time.ParseDuration(data.ExecutionTime)
 	if err != nil {
-		logDebugf("Failed to parse query metrics execution time: %s", err)
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to parse query metrics execution time: %s", err)
 	}
 
 	metrics.ElapsedTime = elapsedTime
@@ -214,12 +214,76 @@ func (r *AnalyticsResult) MetaData() (*AnalyticsMetaData, error) {
 }
 
 // AnalyticsQuery executes the analytics query statement on the server.
-func (c *Cluster) AnalyticsQuery(statement string, opts *AnalyticsOptions) (*AnalyticsResult, error) {
+func (c *Cluster) AnalyticsQuery(statement string, opts *AnalyticsOptions) (resOut *AnalyticsResult, errOut error) {
 	if opts == nil {
 		opts = &AnalyticsOptions{}
 	}
 
-	span := c.tracer.StartSpan("Query", opts.parentSpan).
+	start := time.Now()
+	history := &retryHistory{}
+	defer func() {
+		errOut = c.retryBudget.maybeMarkExhausted(errOut)
+		c.retryBudget.recordOutcome(errOut)
+		if errOut != nil {
+			recordOperationMetric(c.meter, ServiceTypeAnalytics, "AnalyticsQuery", start, errOut, history)
+		}
+	}()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = c.timeoutsConfig.AnalyticsTimeout
+	}
+	release, err := c.admission.admit(ServiceTypeAnalytics, "", start.Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errOut != nil {
+			release()
+			return
+		}
+
+		// The request remains in flight, and is timed, until its rows have been read or the results closed.
+		complete := func(streamErr error) {
+			release()
+			recordOperationMetric(c.meter, ServiceTypeAnalytics, "AnalyticsQuery", start, streamErr, history)
+		}
+		resOut.reader = releaseOnComplete(resOut.reader, complete)
+	}()
+
+	if len(c.serviceInterceptors) == 0 {
+		return c.analyticsQuery(statement, opts, start, history)
+	}
+
+	interceptedOpts := *opts
+	req := &ServiceInterceptorRequest{
+		Service:   ServiceTypeAnalytics,
+		Statement: statement,
+		Options:   &interceptedOpts,
+	}
+	interception, err := interceptServiceRequest(c.serviceInterceptors, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.analyticsQuery(req.Statement, &interceptedOpts, start, history)
+	if err != nil {
+		interception.complete(nil, err)
+		return nil, err
+	}
+
+	res.reader = interception.completionReader(res.reader, func() (interface{}, error) {
+		return res.MetaData()
+	})
+
+	return res, nil
+}
+
+// analyticsQuery executes statement, timing out relative to start so that any time spent waiting for
+// admission counts towards the timeout.
+func (c *Cluster) analyticsQuery(statement string, opts *AnalyticsOptions, start time.Time,
+	history *retryHistory) (*AnalyticsResult, error) {
+	span := c.tracer.StartSpan("Query", opts.ParentSpan).
 		SetTag("couchbase.service", "analytics")
 	defer span.Finish()
 
@@ -227,12 +291,9 @@ func (c *Cluster) AnalyticsQuery(statement string, opts *AnalyticsOptions) (*Ana
 	if opts.Timeout == 0 {
 		timeout = c.timeoutsConfig.AnalyticsTimeout
 	}
-	deadline := time.Now().Add(timeout)
+	deadline := start.Add(timeout)
 
-	retryStrategy := c.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryStrategy = newRetryStrategyWrapper(opts.RetryStrategy)
-	}
+	retryStrategy := c.retryStrategyWrapper.withHistory(history).forOperation(opts.RetryStrategy)
 
 	queryOpts, err := opts.toMap()
 	if err != nil {
@@ -261,7 +322,7 @@ func maybeGetAnalyticsOption(options map[string]interface{}, name string) string
 }
 
 func (c *Cluster) execAnalyticsQuery(
-	span requestSpan,
+	span RequestSpan,
 	options map[string]interface{},
 	priority int32,
 	deadline time.Time,
@@ -293,7 +354,7 @@ func (c *Cluster) execAnalyticsQuery(
 		TraceContext:  span.Context(),
 	})
 	if err != nil {
-		return nil, maybeEnhanceAnalyticsError(err)
+		return nil, retryStrategy.attachHistory(maybeEnhanceAnalyticsError(err))
 	}
 
 	return newAnalyticsResult(res), nil
@@ -132,7 +132,7 @@ type bucketMgrErrorResp struct {
 func (bm *BucketManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logDebugf("Failed to read bucket manager response body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to read bucket manager response body: %s", err)
 		return nil
 	}
 
@@ -148,7 +148,7 @@ func (bm *BucketManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtRespon
 	var mgrErr bucketMgrErrorResp
 	err = json.Unmarshal(b, &mgrErr)
 	if err != nil {
-		logDebugf("Failed to unmarshal error body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to unmarshal error body: %s", err)
 		return makeGenericMgmtError(errors.New(string(b)), req, resp)
 	}
 
@@ -172,7 +172,7 @@ func (bm *BucketManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtRespon
 func (bm *BucketManager) tryParseFlushErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logDebugf("Failed to read bucket manager response body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to read bucket manager response body: %s", err)
 		return makeMgmtBadStatusError("failed to flush bucket", req, resp)
 	}
 
@@ -195,13 +195,14 @@ func (bm *BucketManager) tryParseFlushErrorMessage(req *mgmtRequest, resp *mgmtR
 // See BucketManager for methods that allow creating and removing buckets themselves.
 type BucketManager struct {
 	provider mgmtProvider
-	tracer   requestTracer
+	tracer   RequestTracer
 }
 
 // GetBucketOptions is the set of options available to the bucket manager GetBucket operation.
 type GetBucketOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetBucket returns settings for a bucket on the cluster.
@@ -210,14 +211,14 @@ func (bm *BucketManager) GetBucket(bucketName string, opts *GetBucketOptions) (*
 		opts = &GetBucketOptions{}
 	}
 
-	span := bm.tracer.StartSpan("GetBucket", nil).
+	span := bm.tracer.StartSpan("GetBucket", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
 	return bm.get(span.Context(), bucketName, opts.RetryStrategy, opts.Timeout)
 }
 
-func (bm *BucketManager) get(tracectx requestSpanContext, bucketName string,
+func (bm *BucketManager) get(tracectx RequestSpanContext, bucketName string,
 	strategy RetryStrategy, timeout time.Duration) (*BucketSettings, error) {
 
 	req := mgmtRequest{
@@ -266,6 +267,7 @@ func (bm *BucketManager) get(tracectx requestSpanContext, bucketName string,
 type GetAllBucketsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllBuckets returns a list of all active buckets on the cluster.
@@ -274,7 +276,7 @@ func (bm *BucketManager) GetAllBuckets(opts *GetAllBucketsOptions) (map[string]B
 		opts = &GetAllBucketsOptions{}
 	}
 
-	span := bm.tracer.StartSpan("GetAllBuckets", nil).
+	span := bm.tracer.StartSpan("GetAllBuckets", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -335,6 +337,7 @@ type CreateBucketSettings struct {
 type CreateBucketOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateBucket creates a bucket on the cluster.
@@ -343,7 +346,7 @@ func (bm *BucketManager) CreateBucket(settings CreateBucketSettings, opts *Creat
 		opts = &CreateBucketOptions{}
 	}
 
-	span := bm.tracer.StartSpan("CreateBucket", nil).
+	span := bm.tracer.StartSpan("CreateBucket", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -390,6 +393,7 @@ func (bm *BucketManager) CreateBucket(settings CreateBucketSettings, opts *Creat
 type UpdateBucketOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // UpdateBucket updates a bucket on the cluster.
@@ -398,7 +402,7 @@ func (bm *BucketManager) UpdateBucket(settings BucketSettings, opts *UpdateBucke
 		opts = &UpdateBucketOptions{}
 	}
 
-	span := bm.tracer.StartSpan("UpdateBucket", nil).
+	span := bm.tracer.StartSpan("UpdateBucket", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -441,6 +445,7 @@ func (bm *BucketManager) UpdateBucket(settings BucketSettings, opts *UpdateBucke
 type DropBucketOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropBucket will delete a bucket from the cluster by name.
@@ -449,7 +454,7 @@ func (bm *BucketManager) DropBucket(name string, opts *DropBucketOptions) error
 		opts = &DropBucketOptions{}
 	}
 
-	span := bm.tracer.StartSpan("DropBucket", nil).
+	span := bm.tracer.StartSpan("DropBucket", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -485,6 +490,7 @@ func (bm *BucketManager) DropBucket(name string, opts *DropBucketOptions) error
 type FlushBucketOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // FlushBucket will delete all the of the data from a bucket.
@@ -494,7 +500,7 @@ func (bm *BucketManager) FlushBucket(name string, opts *FlushBucketOptions) erro
 		opts = &FlushBucketOptions{}
 	}
 
-	span := bm.tracer.StartSpan("FlushBucket", nil).
+	span := bm.tracer.StartSpan("FlushBucket", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -26,6 +26,12 @@ type DiagnosticsResult struct {
 	Services map[string][]EndPointDiagnostics
 	sdk      string
 	State    ClusterState
+
+	// CircuitBreakerState is the state of the key-value circuit breakers as estimated by the SDK from
+	// the responses of every connection, see CircuitBreakerState for the limits of the estimate.  It is
+	// CircuitBreakerStateDisabled if circuit breakers are disabled.
+	// UNCOMMITTED: This API may change in the future.
+	CircuitBreakerState CircuitBreakerState
 }
 
 type jsonDiagnosticEntry struct {
@@ -44,6 +50,8 @@ type jsonDiagnosticReport struct {
 	ID       string                           `json:"id,omitempty"`
 	Services map[string][]jsonDiagnosticEntry `json:"services"`
 	State    string                           `json:"state"`
+
+	CircuitBreaker string `json:"circuit_breaker,omitempty"`
 }
 
 // MarshalJSON generates a JSON representation of this diagnostics report.
@@ -54,6 +62,8 @@ func (report *DiagnosticsResult) MarshalJSON() ([]byte, error) {
 		ID:       report.ID,
 		Services: make(map[string][]jsonDiagnosticEntry),
 		State:    clusterStateToString(report.State),
+
+		CircuitBreaker: circuitBreakerStateToString(report.CircuitBreakerState),
 	}
 
 	for _, serviceType := range report.Services {
@@ -106,6 +116,8 @@ func (c *Cluster) Diagnostics(opts *DiagnosticsOptions) (*DiagnosticsResult, err
 		Services: make(map[string][]EndPointDiagnostics),
 		sdk:      Identifier(),
 		State:    ClusterState(agentReport.State),
+
+		CircuitBreakerState: c.breakers.state(),
 	}
 
 	report.Services["kv"] = make([]EndPointDiagnostics, 0)
@@ -49,12 +49,12 @@ type QueryMetrics struct {
 func (metrics *QueryMetrics) fromData(data *jsonQueryMetrics) error {
 	elapsedTime, err := time.ParseDuration(data.ElapsedTime)
 	if err != nil {
-		logDebugf("Failed to parse query metrics elapsed time: %s", err)
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to parse query metrics elapsed time: %s", err)
 	}
 
 	executionTime, err := time.ParseDuration(data.ExecutionTime)
 	if err != nil {
-		logDebugf("Failed to parse query metrics execution time: %s", err)
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to parse query metrics execution time: %s", err)
 	}
 
 	metrics.ElapsedTime = elapsedTime
@@ -90,7 +90,10 @@ type QueryMetaData struct {
 	Metrics         QueryMetrics
 	Signature       interface{}
 	Warnings        []QueryWarning
-	Profile         interface{}
+
+	// Profile is the profile returned when QueryOptions.Profile is set, ParseProfile returns it in a
+	// typed form.
+	Profile interface{}
 
 	preparedName string
 }
@@ -223,12 +226,82 @@ type queryRowReader interface {
 }
 
 // Query executes the query statement on the server.
-func (c *Cluster) Query(statement string, opts *QueryOptions) (*QueryResult, error) {
+func (c *Cluster) Query(statement string, opts *QueryOptions) (resOut *QueryResult, errOut error) {
 	if opts == nil {
 		opts = &QueryOptions{}
 	}
 
-	span := c.tracer.StartSpan("Query", opts.parentSpan).
+	start := time.Now()
+	history := &retryHistory{}
+	defer func() {
+		errOut = c.retryBudget.maybeMarkExhausted(errOut)
+		c.retryBudget.recordOutcome(errOut)
+		if errOut != nil {
+			recordOperationMetric(c.meter, ServiceTypeQuery, "Query", start, errOut, history)
+		}
+	}()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = c.timeoutsConfig.QueryTimeout
+	}
+	release, err := c.admission.admit(ServiceTypeQuery, "", start.Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errOut != nil {
+			release()
+			return
+		}
+
+		// The request remains in flight, and is timed, until its rows have been read or the results closed.
+		complete := func(streamErr error) {
+			release()
+			recordOperationMetric(c.meter, ServiceTypeQuery, "Query", start, streamErr, history)
+		}
+		resOut.reader = &completionQueryRowReader{
+			completionRowReader: releaseOnComplete(resOut.reader, complete),
+			queryReader:         resOut.reader,
+		}
+	}()
+
+	if len(c.serviceInterceptors) == 0 {
+		return c.query(statement, opts, start, history)
+	}
+
+	interceptedOpts := *opts
+	req := &ServiceInterceptorRequest{
+		Service:   ServiceTypeQuery,
+		Statement: statement,
+		Options:   &interceptedOpts,
+	}
+	interception, err := interceptServiceRequest(c.serviceInterceptors, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.query(req.Statement, &interceptedOpts, start, history)
+	if err != nil {
+		interception.complete(nil, err)
+		return nil, err
+	}
+
+	reader := res.reader
+	res.reader = &completionQueryRowReader{
+		completionRowReader: interception.completionReader(reader, func() (interface{}, error) {
+			return res.MetaData()
+		}),
+		queryReader: reader,
+	}
+
+	return res, nil
+}
+
+// query executes statement, timing out relative to start so that any time spent waiting for admission
+// counts towards the timeout.
+func (c *Cluster) query(statement string, opts *QueryOptions, start time.Time, history *retryHistory) (*QueryResult, error) {
+	span := c.tracer.StartSpan("Query", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -236,12 +309,9 @@ func (c *Cluster) Query(statement string, opts *QueryOptions) (*QueryResult, err
 	if timeout == 0 {
 		timeout = c.timeoutsConfig.QueryTimeout
 	}
-	deadline := time.Now().Add(timeout)
+	deadline := start.Add(timeout)
 
-	retryStrategy := c.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryStrategy = newRetryStrategyWrapper(opts.RetryStrategy)
-	}
+	retryStrategy := c.retryStrategyWrapper.withHistory(history).forOperation(opts.RetryStrategy)
 
 	queryOpts, err := opts.toMap()
 	if err != nil {
@@ -265,7 +335,7 @@ func maybeGetQueryOption(options map[string]interface{}, name string) string {
 }
 
 func (c *Cluster) execN1qlQuery(
-	span requestSpan,
+	span RequestSpan,
 	options map[string]interface{},
 	deadline time.Time,
 	retryStrategy *retryStrategyWrapper,
@@ -309,7 +379,7 @@ func (c *Cluster) execN1qlQuery(
 		})
 	}
 	if qErr != nil {
-		return nil, maybeEnhanceQueryError(qErr)
+		return nil, retryStrategy.attachHistory(maybeEnhanceQueryError(qErr))
 	}
 
 	return newQueryResult(res), nil
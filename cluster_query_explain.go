@@ -0,0 +1,274 @@
+package gocb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ExplainQueryOptions is the set of options available to the ExplainQuery operation.
+// UNCOMMITTED: This API may change in the future.
+type ExplainQueryOptions struct {
+	PositionalParameters []interface{}
+	NamedParameters      map[string]interface{}
+
+	// Raw provides a way to provide extra parameters in the request body for the query.
+	Raw map[string]interface{}
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// ExplainQueryResult is the plan which the query service would use to execute a statement.
+// UNCOMMITTED: This API may change in the future.
+type ExplainQueryResult struct {
+	Plan *QueryPlanOperator
+
+	// Text is the statement which was explained, as understood by the query service.
+	Text string
+}
+
+type jsonExplainQueryRow struct {
+	Plan *QueryPlanOperator `json:"plan"`
+	Text string             `json:"text"`
+}
+
+// ScannedIndexes returns the indexes which the plan would scan.
+func (r *ExplainQueryResult) ScannedIndexes() []QueryScannedIndex {
+	if r.Plan == nil {
+		return nil
+	}
+
+	return r.Plan.ScannedIndexes()
+}
+
+// UsesPrimaryScan returns whether the plan would scan a primary index, which usually indicates that
+// no secondary index is suitable for the statement.
+func (r *ExplainQueryResult) UsesPrimaryScan() bool {
+	for _, index := range r.ScannedIndexes() {
+		if index.IsPrimary {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AdviseQueryOptions is the set of options available to the AdviseQuery operation.
+// UNCOMMITTED: This API may change in the future.
+type AdviseQueryOptions struct {
+	PositionalParameters []interface{}
+	NamedParameters      map[string]interface{}
+
+	// Raw provides a way to provide extra parameters in the request body for the query.
+	Raw map[string]interface{}
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// AdviseQueryResult contains the indexes which the query service recommends for a statement.
+// UNCOMMITTED: This API may change in the future.
+type AdviseQueryResult struct {
+	// Statement is the statement which was advised on, as understood by the query service.
+	Statement string
+
+	CurrentIndexes             []QueryIndexAdvice
+	RecommendedIndexes         []QueryIndexAdvice
+	RecommendedCoveringIndexes []QueryIndexAdvice
+
+	// Message is the explanation given by the query service when it has no recommendations.
+	Message string
+}
+
+// HasRecommendations returns whether the query service recommended any indexes.
+func (r *AdviseQueryResult) HasRecommendations() bool {
+	return len(r.RecommendedIndexes) > 0 || len(r.RecommendedCoveringIndexes) > 0
+}
+
+// QueryIndexAdvice describes an existing or recommended index.
+// UNCOMMITTED: This API may change in the future.
+type QueryIndexAdvice struct {
+	// Statement is the CREATE INDEX statement for the index.
+	Statement     string
+	KeyspaceAlias string
+
+	// Property describes the pushdowns enabled by a covering index.
+	Property string
+
+	// RecommendingRule describes why a secondary index was recommended.
+	RecommendingRule string
+}
+
+type jsonQueryIndexAdvice struct {
+	Statement        string `json:"index_statement"`
+	KeyspaceAlias    string `json:"keyspace_alias"`
+	Property         string `json:"index_property,omitempty"`
+	RecommendingRule string `json:"recommending_rule,omitempty"`
+}
+
+type jsonRecommendedIndexes struct {
+	CoveringIndexes []jsonQueryIndexAdvice `json:"covering_indexes,omitempty"`
+	Indexes         []jsonQueryIndexAdvice `json:"indexes,omitempty"`
+}
+
+type jsonAdviseInfo struct {
+	// The query service returns a message rather than a list when there are no indexes.
+	CurrentIndexes     json.RawMessage `json:"current_indexes,omitempty"`
+	RecommendedIndexes json.RawMessage `json:"recommended_indexes,omitempty"`
+}
+
+type jsonAdvice struct {
+	AdviseInfo jsonAdviseInfo `json:"adviseinfo"`
+}
+
+type jsonAdviseQueryRow struct {
+	Query  string     `json:"query"`
+	Advice jsonAdvice `json:"advice"`
+}
+
+func (r *AdviseQueryResult) fromData(data jsonAdviseQueryRow) error {
+	r.Statement = data.Query
+
+	info := data.Advice.AdviseInfo
+	if len(info.CurrentIndexes) > 0 && info.CurrentIndexes[0] == '[' {
+		var current []jsonQueryIndexAdvice
+		if err := json.Unmarshal(info.CurrentIndexes, &current); err != nil {
+			return err
+		}
+		r.CurrentIndexes = translateQueryIndexAdvice(current)
+	}
+
+	if len(info.RecommendedIndexes) > 0 && info.RecommendedIndexes[0] == '"' {
+		return json.Unmarshal(info.RecommendedIndexes, &r.Message)
+	}
+
+	if len(info.RecommendedIndexes) > 0 {
+		var recommended jsonRecommendedIndexes
+		if err := json.Unmarshal(info.RecommendedIndexes, &recommended); err != nil {
+			return err
+		}
+		r.RecommendedIndexes = translateQueryIndexAdvice(recommended.Indexes)
+		r.RecommendedCoveringIndexes = translateQueryIndexAdvice(recommended.CoveringIndexes)
+	}
+
+	return nil
+}
+
+func translateQueryIndexAdvice(data []jsonQueryIndexAdvice) []QueryIndexAdvice {
+	var advice []QueryIndexAdvice
+	for _, index := range data {
+		advice = append(advice, QueryIndexAdvice{
+			Statement:        index.Statement,
+			KeyspaceAlias:    index.KeyspaceAlias,
+			Property:         index.Property,
+			RecommendingRule: index.RecommendingRule,
+		})
+	}
+
+	return advice
+}
+
+type queryRunner func(statement string, opts *QueryOptions) (*QueryResult, error)
+
+// ExplainQuery returns the plan which the query service would use to execute statement.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) ExplainQuery(statement string, opts *ExplainQueryOptions) (*ExplainQueryResult, error) {
+	return explainQuery(c.Query, "", statement, opts)
+}
+
+// AdviseQuery returns the indexes which the query service recommends for statement.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) AdviseQuery(statement string, opts *AdviseQueryOptions) (*AdviseQueryResult, error) {
+	return adviseQuery(c.Query, "", statement, opts)
+}
+
+// ExplainQuery returns the plan which the query service would use to execute statement, resolving
+// keyspaces in the statement relative to this scope.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) ExplainQuery(statement string, opts *ExplainQueryOptions) (*ExplainQueryResult, error) {
+	return explainQuery(s.runQuery, s.queryContext(), statement, opts)
+}
+
+// AdviseQuery returns the indexes which the query service recommends for statement, resolving
+// keyspaces in the statement relative to this scope.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) AdviseQuery(statement string, opts *AdviseQueryOptions) (*AdviseQueryResult, error) {
+	return adviseQuery(s.runQuery, s.queryContext(), statement, opts)
+}
+
+func (s *Scope) queryContext() string {
+	return fmt.Sprintf("default:`%s`.`%s`", s.BucketName(), s.Name())
+}
+
+func explainQuery(run queryRunner, queryContext, statement string, opts *ExplainQueryOptions) (*ExplainQueryResult, error) {
+	if opts == nil {
+		opts = &ExplainQueryOptions{}
+	}
+
+	var row jsonExplainQueryRow
+	err := runQueryStatementOne(run, queryContext, "EXPLAIN "+statement, &QueryOptions{
+		PositionalParameters: opts.PositionalParameters,
+		NamedParameters:      opts.NamedParameters,
+		Raw:                  opts.Raw,
+		Timeout:              opts.Timeout,
+		RetryStrategy:        opts.RetryStrategy,
+		ParentSpan:           opts.ParentSpan,
+	}, &row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExplainQueryResult{
+		Plan: row.Plan,
+		Text: row.Text,
+	}, nil
+}
+
+func adviseQuery(run queryRunner, queryContext, statement string, opts *AdviseQueryOptions) (*AdviseQueryResult, error) {
+	if opts == nil {
+		opts = &AdviseQueryOptions{}
+	}
+
+	var row jsonAdviseQueryRow
+	err := runQueryStatementOne(run, queryContext, "ADVISE "+statement, &QueryOptions{
+		PositionalParameters: opts.PositionalParameters,
+		NamedParameters:      opts.NamedParameters,
+		Raw:                  opts.Raw,
+		Timeout:              opts.Timeout,
+		RetryStrategy:        opts.RetryStrategy,
+		ParentSpan:           opts.ParentSpan,
+	}, &row)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AdviseQueryResult{}
+	if err := result.fromData(row); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func runQueryStatementOne(run queryRunner, queryContext, statement string, opts *QueryOptions,
+	valuePtr interface{}) error {
+	opts.Adhoc = true
+	if queryContext != "" {
+		raw := make(map[string]interface{}, len(opts.Raw)+1)
+		for k, v := range opts.Raw {
+			raw[k] = v
+		}
+		raw["query_context"] = queryContext
+		opts.Raw = raw
+	}
+
+	result, err := run(statement, opts)
+	if err != nil {
+		return err
+	}
+
+	return result.One(valuePtr)
+}
@@ -0,0 +1,140 @@
+package gocb
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockQueryRowReaderRows struct {
+	Rows [][]byte
+	mockQueryRowReaderBase
+}
+
+func (arr *mockQueryRowReaderRows) NextRow() []byte {
+	if arr.idx == len(arr.Rows) {
+		return nil
+	}
+
+	idx := arr.idx
+	arr.idx++
+
+	return arr.Rows[idx]
+}
+
+func (suite *UnitTestSuite) TestExplainQuery() {
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`{
+			"plan": {
+				"#operator": "Sequence",
+				"~children": [
+					{"#operator": "IndexScan3", "index": "def_type", "keyspace": "travel-sample", "namespace": "default", "using": "gsi"},
+					{"#operator": "Fetch", "keyspace": "travel-sample", "namespace": "default"}
+				]
+			},
+			"text": "SELECT * FROM ` + "`travel-sample`" + ` WHERE type = $type"
+		}`)},
+	}
+
+	var payload map[string]interface{}
+	cluster := suite.queryCluster(false, reader, func(args mock.Arguments) {
+		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+	})
+
+	result, err := cluster.ExplainQuery("SELECT * FROM `travel-sample` WHERE type = $type", &ExplainQueryOptions{
+		NamedParameters: map[string]interface{}{"type": "airline"},
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal("EXPLAIN SELECT * FROM `travel-sample` WHERE type = $type", payload["statement"])
+	suite.Assert().Equal("airline", payload["$type"])
+	suite.Assert().NotContains(payload, "query_context")
+
+	suite.Require().NotNil(result.Plan)
+	suite.Assert().Equal("Sequence", result.Plan.Operator)
+	suite.Assert().Len(result.Plan.Children, 2)
+	suite.Assert().Contains(result.Text, "travel-sample")
+	suite.Require().Len(result.ScannedIndexes(), 1)
+	suite.Assert().Equal("def_type", result.ScannedIndexes()[0].Name)
+	suite.Assert().False(result.UsesPrimaryScan())
+}
+
+func (suite *UnitTestSuite) TestAdviseQuery() {
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`{
+			"#operator": "Advise",
+			"advice": {
+				"#operator": "IndexAdvice",
+				"adviseinfo": {
+					"current_indexes": [
+						{"index_statement": "CREATE PRIMARY INDEX def_primary ON ` + "`travel-sample`" + `", "keyspace_alias": "travel-sample"}
+					],
+					"recommended_indexes": {
+						"covering_indexes": [
+							{"index_property": "FULL GROUPBY & AGGREGATES pushdown", "index_statement": "CREATE INDEX adv_city_name ON ` + "`travel-sample`(`city`,`name`)" + `", "keyspace_alias": "travel-sample"}
+						],
+						"indexes": [
+							{"index_statement": "CREATE INDEX adv_city ON ` + "`travel-sample`(`city`)" + `", "keyspace_alias": "travel-sample", "recommending_rule": "Index keys follow order of predicate types: 2. equality/null/missing."}
+						]
+					}
+				}
+			},
+			"query": "SELECT name FROM ` + "`travel-sample`" + ` WHERE city = 'Paris'"
+		}`)},
+	}
+
+	var payload map[string]interface{}
+	cluster := suite.queryCluster(false, reader, func(args mock.Arguments) {
+		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+	})
+
+	scope := newScope(newBucket(cluster, "travel-sample"), "inventory")
+	raw := map[string]interface{}{"use_cbo": true}
+	result, err := scope.AdviseQuery("SELECT name FROM airport WHERE city = 'Paris'", &AdviseQueryOptions{
+		Raw: raw,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal("ADVISE SELECT name FROM airport WHERE city = 'Paris'", payload["statement"])
+	suite.Assert().Equal("default:`travel-sample`.`inventory`", payload["query_context"])
+	suite.Assert().Equal(true, payload["use_cbo"])
+	suite.Assert().Equal(map[string]interface{}{"use_cbo": true}, raw)
+
+	suite.Assert().True(result.HasRecommendations())
+	suite.Assert().Contains(result.Statement, "Paris")
+	suite.Require().Len(result.CurrentIndexes, 1)
+	suite.Assert().Equal("travel-sample", result.CurrentIndexes[0].KeyspaceAlias)
+	suite.Require().Len(result.RecommendedIndexes, 1)
+	suite.Assert().Equal("CREATE INDEX adv_city ON `travel-sample`(`city`)", result.RecommendedIndexes[0].Statement)
+	suite.Assert().NotEmpty(result.RecommendedIndexes[0].RecommendingRule)
+	suite.Require().Len(result.RecommendedCoveringIndexes, 1)
+	suite.Assert().Equal("FULL GROUPBY & AGGREGATES pushdown", result.RecommendedCoveringIndexes[0].Property)
+	suite.Assert().Empty(result.Message)
+}
+
+func (suite *UnitTestSuite) TestAdviseQueryNoRecommendations() {
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`{
+			"#operator": "Advise",
+			"advice": {
+				"#operator": "IndexAdvice",
+				"adviseinfo": {
+					"recommended_indexes": "No index recommendation at this time."
+				}
+			},
+			"query": "SELECT 1"
+		}`)},
+	}
+
+	cluster := suite.queryCluster(false, reader, func(args mock.Arguments) {})
+
+	result, err := cluster.AdviseQuery("SELECT 1", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().False(result.HasRecommendations())
+	suite.Assert().Equal("No index recommendation at this time.", result.Message)
+	suite.Assert().Empty(result.CurrentIndexes)
+}
@@ -0,0 +1,467 @@
+package gocb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryPaginatorKey is an expression which results are ordered by, the keys of a paginator together
+// identify the position of a row within the results.
+// UNCOMMITTED: This API may change in the future.
+type QueryPaginatorKey struct {
+	// Expression is the N1QL expression which is ordered by, e.g. a.name or META(a).id.
+	Expression string
+
+	// Field is the field of each result row which holds the value of Expression.  Defaults to the last
+	// element of Expression, e.g. name for a.name or id for META(a).id, which is the name that the query
+	// service gives to a projected path.
+	Field string
+
+	Descending bool
+}
+
+// QueryPaginatorOptions is the set of options available when paginating a query.
+// UNCOMMITTED: This API may change in the future.
+type QueryPaginatorOptions struct {
+	// PageSize is the maximum number of rows in each page.  Defaults to 100.
+	PageSize uint32
+
+	// Cursor resumes pagination after the page which returned it.
+	Cursor string
+
+	// NamedParameters are the parameters of the statement, positional parameters are not supported.
+	NamedParameters map[string]interface{}
+
+	ScanConsistency QueryScanConsistency
+	Readonly        bool
+
+	// Timeout, RetryStrategy and ParentSpan apply to the query for each page.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// QueryPaginator pages through the results of a statement using keyset pagination: rather than skipping
+// rows with OFFSET, each page selects the rows which sort after the last row of the previous page, which
+// allows the query service to start each page directly from an index.
+//
+// The keys must identify each row uniquely, so the last key is usually META().id, and must never be
+// NULL or MISSING.
+// UNCOMMITTED: This API may change in the future.
+type QueryPaginator struct {
+	run       queryRunner
+	statement string
+	keys      []QueryPaginatorKey
+	opts      QueryPaginatorOptions
+	hash      string
+
+	lastValues []json.RawMessage
+	done       bool
+}
+
+// QueryPage is a single page of results returned by a QueryPaginator.
+// UNCOMMITTED: This API may change in the future.
+type QueryPage struct {
+	rows []json.RawMessage
+
+	// Cursor can be passed as QueryPaginatorOptions.Cursor to resume pagination after this page.
+	Cursor string
+}
+
+// Len returns the number of rows in the page.
+func (page *QueryPage) Len() int {
+	return len(page.rows)
+}
+
+// Rows decodes the rows of the page into the slice pointed to by slicePtr.
+func (page *QueryPage) Rows(slicePtr interface{}) error {
+	return decodeAllRows(&bufferedQueryRowReader{rows: page.rows}, slicePtr, nil, &ResultAllOptions{
+		MaxRows: uint32(len(page.rows)),
+	})
+}
+
+type jsonQueryPaginatorCursor struct {
+	Hash   string            `json:"h"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// queryPaginatorDisallowedKeywords are the clauses which cannot be used by a paginated statement, as they
+// are either added by the paginator or prevent keyset pagination.
+var queryPaginatorDisallowedKeywords = []string{"ORDER", "LIMIT", "OFFSET", "GROUP", "UNION", "INTERSECT", "EXCEPT"}
+
+// QueryPaginator returns a QueryPaginator which pages through the results of statement in the order of
+// keys.  The statement must be a SELECT without ORDER BY, LIMIT, OFFSET, GROUP BY or set operators, and
+// must project the Field of every key.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) QueryPaginator(statement string, keys []QueryPaginatorKey, opts *QueryPaginatorOptions) (*QueryPaginator, error) {
+	return newQueryPaginator(c.Query, statement, keys, opts)
+}
+
+func newQueryPaginator(run queryRunner, statement string, keys []QueryPaginatorKey,
+	opts *QueryPaginatorOptions) (*QueryPaginator, error) {
+	if opts == nil {
+		opts = &QueryPaginatorOptions{}
+	}
+	if len(keys) == 0 {
+		return nil, makeInvalidArgumentsError("at least one key must be specified")
+	}
+
+	// Clauses are appended to the statement, so a trailing comment would swallow them.
+	statement = queryTrimTrailingComments(statement)
+	keywords := queryTopLevelKeywords(statement)
+	for _, keyword := range queryPaginatorDisallowedKeywords {
+		if keywords[keyword] {
+			return nil, makeInvalidArgumentsError("paginated statements cannot contain " + keyword)
+		}
+	}
+
+	p := &QueryPaginator{
+		run:       run,
+		statement: statement,
+		keys:      make([]QueryPaginatorKey, len(keys)),
+		opts:      *opts,
+	}
+	if p.opts.PageSize == 0 {
+		p.opts.PageSize = 100
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(statement))
+	for i, key := range keys {
+		if key.Expression == "" {
+			return nil, makeInvalidArgumentsError("key expressions cannot be empty")
+		}
+		if key.Field == "" {
+			key.Field = queryImplicitFieldName(key.Expression)
+		}
+		p.keys[i] = key
+
+		_, _ = hash.Write([]byte("\x00" + key.Expression + "\x00" + key.Field + "\x00" + strconv.FormatBool(key.Descending)))
+	}
+	p.hash = strconv.FormatUint(hash.Sum64(), 16)
+
+	if opts.Cursor != "" {
+		if err := p.restore(opts.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// HasNext returns whether there may be another page.  It returns false once a page has been returned
+// which contained the final row.
+func (p *QueryPaginator) HasNext() bool {
+	return !p.done
+}
+
+// Next returns the next page of results, or ErrNoResult if every page has been returned.
+func (p *QueryPaginator) Next() (*QueryPage, error) {
+	if p.done {
+		return nil, ErrNoResult
+	}
+
+	params := make(map[string]interface{}, len(p.opts.NamedParameters)+len(p.lastValues))
+	for name, value := range p.opts.NamedParameters {
+		params[name] = value
+	}
+
+	statement := p.statement
+	if p.lastValues != nil {
+		for i, value := range p.lastValues {
+			params[queryPaginatorParam(i)] = value
+		}
+
+		predicate := "(" + p.keysetPredicate() + ")"
+		if idx := queryTopLevelKeywordIndex(statement, "WHERE"); idx >= 0 {
+			// The existing condition is parenthesized as it may contain a top-level OR, which would
+			// otherwise take precedence over the keyset predicate.
+			condStart := idx + len("WHERE")
+			statement = statement[:condStart] + " (" + strings.TrimSpace(statement[condStart:]) + ") AND " + predicate
+		} else {
+			statement = statement + " WHERE " + predicate
+		}
+	}
+
+	orderBy := make([]string, len(p.keys))
+	for i, key := range p.keys {
+		orderBy[i] = key.Expression
+		if key.Descending {
+			orderBy[i] += " DESC"
+		}
+	}
+	statement += " ORDER BY " + strings.Join(orderBy, ", ") + " LIMIT " + strconv.FormatUint(uint64(p.opts.PageSize)+1, 10)
+
+	result, err := p.run(statement, &QueryOptions{
+		NamedParameters: params,
+		ScanConsistency: p.opts.ScanConsistency,
+		Readonly:        p.opts.Readonly,
+		Timeout:         p.opts.Timeout,
+		RetryStrategy:   p.opts.RetryStrategy,
+		ParentSpan:      p.opts.ParentSpan,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []json.RawMessage
+	if err := result.All(&rows, &ResultAllOptions{MaxRows: p.opts.PageSize + 1}); err != nil {
+		return nil, err
+	}
+
+	if uint32(len(rows)) > p.opts.PageSize {
+		rows = rows[:p.opts.PageSize]
+	} else {
+		p.done = true
+	}
+
+	if len(rows) > 0 {
+		values, err := p.keyValues(rows[len(rows)-1])
+		if err != nil {
+			return nil, err
+		}
+		p.lastValues = values
+	}
+
+	page := &QueryPage{
+		rows: rows,
+	}
+	if !p.done {
+		page.Cursor = p.Cursor()
+	}
+
+	return page, nil
+}
+
+// Cursor returns a token which can be passed as QueryPaginatorOptions.Cursor to resume pagination after
+// the last page returned, or an empty string if no page has been returned.
+func (p *QueryPaginator) Cursor() string {
+	if p.lastValues == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(jsonQueryPaginatorCursor{
+		Hash:   p.hash,
+		Values: p.lastValues,
+	})
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to marshal query paginator cursor: %s", err)
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (p *QueryPaginator) restore(cursor string) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return makeInvalidArgumentsError("invalid cursor")
+	}
+
+	var jsonCursor jsonQueryPaginatorCursor
+	if err := json.Unmarshal(data, &jsonCursor); err != nil {
+		return makeInvalidArgumentsError("invalid cursor")
+	}
+	if jsonCursor.Hash != p.hash || len(jsonCursor.Values) != len(p.keys) {
+		return makeInvalidArgumentsError("cursor was created for a different statement or keys")
+	}
+
+	p.lastValues = jsonCursor.Values
+	return nil
+}
+
+// keysetPredicate returns a condition matching the rows which sort after the last values, e.g. for
+// keys a and b: a > $1 OR (a = $1 AND b > $2).
+func (p *QueryPaginator) keysetPredicate() string {
+	terms := make([]string, len(p.keys))
+	for i, key := range p.keys {
+		var conds []string
+		for j := 0; j < i; j++ {
+			conds = append(conds, p.keys[j].Expression+" = $"+queryPaginatorParam(j))
+		}
+
+		op := " > $"
+		if key.Descending {
+			op = " < $"
+		}
+		conds = append(conds, key.Expression+op+queryPaginatorParam(i))
+
+		terms[i] = "(" + strings.Join(conds, " AND ") + ")"
+	}
+
+	return strings.Join(terms, " OR ")
+}
+
+func (p *QueryPaginator) keyValues(row []byte) ([]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, makeInvalidArgumentsError("paginated statements must return objects containing every key field")
+	}
+
+	values := make([]json.RawMessage, len(p.keys))
+	for i, key := range p.keys {
+		value, ok := fields[key.Field]
+		if !ok || string(value) == "null" {
+			return nil, makeInvalidArgumentsError("result row is missing key field " + key.Field)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+func queryPaginatorParam(idx int) string {
+	return "keyset" + strconv.Itoa(idx)
+}
+
+// queryImplicitFieldName returns the name which the query service gives to a projected expression, which
+// is the last element of a path.
+func queryImplicitFieldName(expr string) string {
+	name := expr
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	return strings.Trim(strings.TrimSpace(name), "`")
+}
+
+// queryTopLevelKeywords returns the upper cased words of statement which are not within a string,
+// escaped identifier, comment or parentheses.
+func queryTopLevelKeywords(statement string) map[string]bool {
+	keywords := make(map[string]bool)
+	forEachQueryTopLevelWord(statement, func(word string, _ int) bool {
+		keywords[strings.ToUpper(word)] = true
+		return true
+	})
+
+	return keywords
+}
+
+// queryTopLevelKeywordIndex returns the byte offset of the first top-level occurrence of keyword within
+// statement, or -1 if there is none.
+func queryTopLevelKeywordIndex(statement, keyword string) int {
+	found := -1
+	forEachQueryTopLevelWord(statement, func(word string, idx int) bool {
+		if strings.EqualFold(word, keyword) {
+			found = idx
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+// forEachQueryTopLevelWord calls fn with each word of statement, and its byte offset, which is not within
+// a string, escaped identifier, comment or parentheses, until fn returns false.
+func forEachQueryTopLevelWord(statement string, fn func(word string, idx int) bool) {
+	var quote rune
+	var depth int
+	skipTo := 0
+	start := -1
+	endWord := func(end int) bool {
+		if start < 0 {
+			return true
+		}
+		word := statement[start:end]
+		wordStart := start
+		start = -1
+		if depth != 0 {
+			return true
+		}
+		return fn(word, wordStart)
+	}
+
+	for i, c := range statement {
+		if i < skipTo {
+			continue
+		}
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case queryCommentStart(statement, i):
+			if !endWord(i) {
+				return
+			}
+			skipTo = queryCommentEnd(statement, i)
+		case c == '\'' || c == '"' || c == '`':
+			if !endWord(i) {
+				return
+			}
+			quote = c
+		case c == '(':
+			if !endWord(i) {
+				return
+			}
+			depth++
+		case c == ')':
+			if !endWord(i) {
+				return
+			}
+			depth--
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			if start < 0 {
+				start = i
+			}
+		default:
+			if !endWord(i) {
+				return
+			}
+		}
+	}
+	endWord(len(statement))
+}
+
+// queryTrimTrailingComments returns statement without the whitespace, semicolons and comments at its end.
+func queryTrimTrailingComments(statement string) string {
+	var quote byte
+	end := 0
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			end = i + 1
+		case queryCommentStart(statement, i):
+			i = queryCommentEnd(statement, i) - 1
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			end = i + 1
+		case c == ';' || c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		default:
+			end = i + 1
+		}
+	}
+
+	return statement[:end]
+}
+
+// queryCommentStart returns whether a line or block comment starts at the byte offset idx of statement.
+func queryCommentStart(statement string, idx int) bool {
+	return strings.HasPrefix(statement[idx:], "--") || strings.HasPrefix(statement[idx:], "/*")
+}
+
+// queryCommentEnd returns the byte offset just after the comment which starts at idx of statement, which
+// is the end of the statement if the comment is not terminated.
+func queryCommentEnd(statement string, idx int) int {
+	terminator := "\n"
+	if strings.HasPrefix(statement[idx:], "/*") {
+		terminator = "*/"
+	}
+
+	end := strings.Index(statement[idx+2:], terminator)
+	if end < 0 {
+		return len(statement)
+	}
+
+	return idx + 2 + end + len(terminator)
+}
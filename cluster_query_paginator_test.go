@@ -0,0 +1,169 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type queryPaginatorRun struct {
+	statement string
+	opts      *QueryOptions
+}
+
+func (suite *UnitTestSuite) queryPaginatorRunner(pages ...[]string) (queryRunner, *[]queryPaginatorRun) {
+	var runs []queryPaginatorRun
+	return func(statement string, opts *QueryOptions) (*QueryResult, error) {
+		page := pages[len(runs)]
+		runs = append(runs, queryPaginatorRun{statement: statement, opts: opts})
+
+		rows := make([][]byte, len(page))
+		for i, row := range page {
+			rows[i] = []byte(row)
+		}
+		return newQueryResult(&mockQueryRowReaderRows{Rows: rows}), nil
+	}, &runs
+}
+
+func (suite *UnitTestSuite) TestQueryPaginator() {
+	run, runs := suite.queryPaginatorRunner(
+		[]string{`{"name":"a","id":"1"}`, `{"name":"a","id":"2"}`, `{"name":"b","id":"3"}`},
+		[]string{`{"name":"b","id":"3"}`, `{"name":"c","id":"4"}`},
+	)
+
+	keys := []QueryPaginatorKey{{Expression: "t.name"}, {Expression: "META(t).id", Descending: true}}
+	p, err := newQueryPaginator(run, "SELECT t.name, META(t).id FROM `travel-sample` AS t WHERE t.type = $type;", keys,
+		&QueryPaginatorOptions{
+			PageSize:        2,
+			NamedParameters: map[string]interface{}{"type": "hotel"},
+		})
+	suite.Require().Nil(err, err)
+
+	page, err := p.Next()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(2, page.Len())
+	suite.Assert().NotEmpty(page.Cursor)
+	suite.Assert().True(p.HasNext())
+
+	var rows []map[string]string
+	suite.Require().Nil(page.Rows(&rows))
+	suite.Assert().Equal([]map[string]string{{"name": "a", "id": "1"}, {"name": "a", "id": "2"}}, rows)
+
+	page, err = p.Next()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(2, page.Len())
+	suite.Assert().Empty(page.Cursor)
+	suite.Assert().False(p.HasNext())
+
+	_, err = p.Next()
+	suite.Assert().True(errors.Is(err, ErrNoResult))
+
+	suite.Require().Len(*runs, 2)
+	suite.Assert().Equal("SELECT t.name, META(t).id FROM `travel-sample` AS t WHERE t.type = $type "+
+		"ORDER BY t.name, META(t).id DESC LIMIT 3", (*runs)[0].statement)
+	suite.Assert().Equal(map[string]interface{}{"type": "hotel"}, (*runs)[0].opts.NamedParameters)
+
+	suite.Assert().Equal("SELECT t.name, META(t).id FROM `travel-sample` AS t WHERE (t.type = $type) "+
+		"AND ((t.name > $keyset0) OR (t.name = $keyset0 AND META(t).id < $keyset1)) "+
+		"ORDER BY t.name, META(t).id DESC LIMIT 3", (*runs)[1].statement)
+	suite.Assert().Equal(map[string]interface{}{
+		"type":    "hotel",
+		"keyset0": json.RawMessage(`"a"`),
+		"keyset1": json.RawMessage(`"2"`),
+	}, (*runs)[1].opts.NamedParameters)
+}
+
+func (suite *UnitTestSuite) TestQueryPaginatorResumeFromCursor() {
+	statement := "SELECT META().id FROM `default`"
+	keys := []QueryPaginatorKey{{Expression: "META().id"}}
+
+	run, _ := suite.queryPaginatorRunner([]string{`{"id":"1"}`, `{"id":"2"}`})
+	p, err := newQueryPaginator(run, statement, keys, &QueryPaginatorOptions{PageSize: 1})
+	suite.Require().Nil(err, err)
+	page, err := p.Next()
+	suite.Require().Nil(err, err)
+
+	run, runs := suite.queryPaginatorRunner([]string{`{"id":"2"}`})
+	p, err = newQueryPaginator(run, statement, keys, &QueryPaginatorOptions{PageSize: 1, Cursor: page.Cursor})
+	suite.Require().Nil(err, err)
+	_, err = p.Next()
+	suite.Require().Nil(err, err)
+	suite.Assert().False(p.HasNext())
+	suite.Assert().Equal("SELECT META().id FROM `default` WHERE ((META().id > $keyset0)) ORDER BY META().id LIMIT 2",
+		(*runs)[0].statement)
+
+	_, err = newQueryPaginator(run, statement, []QueryPaginatorKey{{Expression: "META().id", Descending: true}},
+		&QueryPaginatorOptions{Cursor: page.Cursor})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	_, err = newQueryPaginator(run, statement, keys, &QueryPaginatorOptions{Cursor: "not a cursor"})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestQueryPaginatorWhereWithOr() {
+	run, runs := suite.queryPaginatorRunner(
+		[]string{`{"id":"1"}`, `{"id":"2"}`},
+		[]string{`{"id":"2"}`},
+	)
+
+	p, err := newQueryPaginator(run, "SELECT META().id FROM `default` where type = 'a' OR (type = 'b')",
+		[]QueryPaginatorKey{{Expression: "META().id"}}, &QueryPaginatorOptions{PageSize: 1})
+	suite.Require().Nil(err, err)
+
+	_, err = p.Next()
+	suite.Require().Nil(err, err)
+	_, err = p.Next()
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(*runs, 2)
+	suite.Assert().Equal("SELECT META().id FROM `default` where (type = 'a' OR (type = 'b')) AND ((META().id > $keyset0)) "+
+		"ORDER BY META().id LIMIT 2", (*runs)[1].statement)
+}
+
+func (suite *UnitTestSuite) TestQueryPaginatorComments() {
+	run, runs := suite.queryPaginatorRunner(
+		[]string{`{"id":"1"}`, `{"id":"2"}`},
+		[]string{`{"id":"2"}`},
+	)
+
+	p, err := newQueryPaginator(run, "SELECT META().id FROM `default` /* WHERE x */ -- ORDER BY y\n"+
+		"WHERE type = 'a' -- the type\n; -- trailing",
+		[]QueryPaginatorKey{{Expression: "META().id"}}, &QueryPaginatorOptions{PageSize: 1})
+	suite.Require().Nil(err, err)
+
+	_, err = p.Next()
+	suite.Require().Nil(err, err)
+	_, err = p.Next()
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(*runs, 2)
+	suite.Assert().Equal("SELECT META().id FROM `default` /* WHERE x */ -- ORDER BY y\n"+
+		"WHERE type = 'a' ORDER BY META().id LIMIT 2", (*runs)[0].statement)
+	suite.Assert().Equal("SELECT META().id FROM `default` /* WHERE x */ -- ORDER BY y\n"+
+		"WHERE (type = 'a') AND ((META().id > $keyset0)) ORDER BY META().id LIMIT 2", (*runs)[1].statement)
+}
+
+func (suite *UnitTestSuite) TestQueryPaginatorInvalidStatement() {
+	run, _ := suite.queryPaginatorRunner()
+	keys := []QueryPaginatorKey{{Expression: "name"}}
+
+	_, err := newQueryPaginator(run, "SELECT name FROM `default` ORDER BY name", keys, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	_, err = newQueryPaginator(run, "SELECT name FROM `default` WHERE name IN (SELECT RAW n FROM x LIMIT 1)", keys, nil)
+	suite.Assert().Nil(err, err)
+
+	_, err = newQueryPaginator(run, "SELECT name FROM `default` WHERE name = 'LIMIT'", keys, nil)
+	suite.Assert().Nil(err, err)
+
+	_, err = newQueryPaginator(run, "SELECT name FROM `default`", nil, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestQueryPaginatorMissingKeyField() {
+	run, _ := suite.queryPaginatorRunner([]string{`{"title":"a"}`})
+	p, err := newQueryPaginator(run, "SELECT title FROM `default`", []QueryPaginatorKey{{Expression: "name"}}, nil)
+	suite.Require().Nil(err, err)
+
+	_, err = p.Next()
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
@@ -253,7 +253,7 @@ func (suite *UnitTestSuite) TestQueryAdhoc() {
 	var cluster *Cluster
 	cluster = suite.queryCluster(false, reader, func(args mock.Arguments) {
 		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
-		suite.Assert().Equal(cluster.retryStrategyWrapper, opts.RetryStrategy)
+		suite.Assert().Equal(cluster.retryStrategyWrapper.wrapped, opts.RetryStrategy.(*retryStrategyWrapper).wrapped)
 		now := time.Now()
 		if opts.Deadline.Before(now.Add(70*time.Second)) || opts.Deadline.After(now.Add(75*time.Second)) {
 			suite.Fail("Deadline should have been <75s and >70s but was %s", opts.Deadline)
@@ -295,7 +295,7 @@ func (suite *UnitTestSuite) TestQueryPrepared() {
 	var cluster *Cluster
 	cluster = suite.queryCluster(true, reader, func(args mock.Arguments) {
 		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
-		suite.Assert().Equal(cluster.retryStrategyWrapper, opts.RetryStrategy)
+		suite.Assert().Equal(cluster.retryStrategyWrapper.wrapped, opts.RetryStrategy.(*retryStrategyWrapper).wrapped)
 		now := time.Now()
 		if opts.Deadline.Before(now.Add(70*time.Second)) || opts.Deadline.After(now.Add(75*time.Second)) {
 			suite.Fail("Deadline should have been <75s and >70s but was %s", opts.Deadline)
@@ -447,7 +447,7 @@ func (suite *UnitTestSuite) TestQueryTimeoutOption() {
 		On("N1QLQuery", mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
 		Run(func(args mock.Arguments) {
 			opts := args.Get(0).(gocbcore.N1QLQueryOptions)
-			suite.Assert().Equal(cluster.retryStrategyWrapper, opts.RetryStrategy)
+			suite.Assert().Equal(cluster.retryStrategyWrapper.wrapped, opts.RetryStrategy.(*retryStrategyWrapper).wrapped)
 			now := time.Now()
 			if opts.Deadline.Before(now.Add(20*time.Second)) || opts.Deadline.After(now.Add(25*time.Second)) {
 				suite.Fail("Deadline should have been <75s and >70s but was %s", opts.Deadline)
@@ -567,7 +567,7 @@ func (suite *UnitTestSuite) TestQueryNoMetrics() {
 	var cluster *Cluster
 	cluster = suite.queryCluster(true, reader, func(args mock.Arguments) {
 		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
-		suite.Assert().Equal(cluster.retryStrategyWrapper, opts.RetryStrategy)
+		suite.Assert().Equal(cluster.retryStrategyWrapper.wrapped, opts.RetryStrategy.(*retryStrategyWrapper).wrapped)
 		now := time.Now()
 		if opts.Deadline.Before(now.Add(70*time.Second)) || opts.Deadline.After(now.Add(75*time.Second)) {
 			suite.Fail("Deadline should have been <75s and >70s but was %s", opts.Deadline)
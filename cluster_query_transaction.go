@@ -0,0 +1,512 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryTransactionOptions is the set of options available when beginning a query transaction.
+// UNCOMMITTED: This API may change in the future.
+type QueryTransactionOptions struct {
+	// TransactionTimeout is how long the transaction may remain open before the query service rolls it
+	// back.  Defaults to the query service default, currently 15 seconds.
+	TransactionTimeout time.Duration
+
+	// ScanConsistency is the scan consistency used by every statement in the transaction.
+	ScanConsistency QueryScanConsistency
+
+	// DurabilityLevel is the durability used by every mutation in the transaction.
+	DurabilityLevel DurabilityLevel
+
+	// Timeout, RetryStrategy and ParentSpan apply to beginning the transaction, committing it and rolling
+	// it back.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// QueryTransaction is a server side N1QL transaction.  Every statement within the transaction is sent to
+// the query node which began it.  If a statement fails then the transaction is rolled back and cannot be
+// used any further.
+//
+// Statements are passed to the cluster's ServiceInterceptors, but beginning, committing and rolling back
+// the transaction are not.  Transactions are never recorded and cannot be used in replay mode, as each
+// depends on the state of the query node which began it.
+// UNCOMMITTED: This API may change in the future.
+type QueryTransaction struct {
+	provider     mgmtProvider
+	tracer       RequestTracer
+	meter        Meter
+	admission    *admissionController
+	retryBudget  *RetryBudget
+	interceptors []ServiceInterceptor
+	queryTimeout time.Duration
+
+	txid     string
+	endpoint string
+	opts     QueryTransactionOptions
+
+	lock       sync.Mutex
+	state      queryTransactionState
+	failure    error
+	rolledBack bool
+}
+
+type queryTransactionState uint
+
+const (
+	queryTransactionActive queryTransactionState = iota
+	queryTransactionCommitted
+	queryTransactionRolledBack
+	queryTransactionFailed
+)
+
+type jsonQueryTransactionError struct {
+	Code uint32 `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+type jsonQueryTransactionResponse struct {
+	Results []json.RawMessage           `json:"results"`
+	Errors  []jsonQueryTransactionError `json:"errors"`
+}
+
+type jsonBeginWorkRow struct {
+	TxID string `json:"txid"`
+}
+
+// BeginQueryTransaction begins a server side N1QL transaction.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) BeginQueryTransaction(opts *QueryTransactionOptions) (*QueryTransaction, error) {
+	if opts == nil {
+		opts = &QueryTransactionOptions{}
+	}
+
+	tx := &QueryTransaction{
+		provider:     c,
+		tracer:       c.tracer,
+		meter:        c.meter,
+		admission:    c.admission,
+		retryBudget:  c.retryBudget,
+		interceptors: c.serviceInterceptors,
+		queryTimeout: c.timeoutsConfig.QueryTimeout,
+		opts:         *opts,
+	}
+
+	if err := tx.begin(); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ID returns the ID which the query service assigned to the transaction.
+func (tx *QueryTransaction) ID() string {
+	return tx.txid
+}
+
+func (tx *QueryTransaction) begin() error {
+	span := tx.tracer.StartSpan("BeginQueryTransaction", tx.opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	payload := map[string]interface{}{
+		"statement": "BEGIN WORK",
+	}
+	if tx.opts.TransactionTimeout > 0 {
+		payload["txtimeout"] = tx.opts.TransactionTimeout.String()
+	}
+	if tx.opts.ScanConsistency != 0 {
+		if tx.opts.ScanConsistency == QueryScanConsistencyNotBounded {
+			payload["scan_consistency"] = "not_bounded"
+		} else if tx.opts.ScanConsistency == QueryScanConsistencyRequestPlus {
+			payload["scan_consistency"] = "request_plus"
+		} else {
+			return makeInvalidArgumentsError("Unexpected consistency option")
+		}
+	}
+	if tx.opts.DurabilityLevel != 0 {
+		level, err := queryDurabilityLevel(tx.opts.DurabilityLevel)
+		if err != nil {
+			return err
+		}
+		payload["durability_level"] = level
+	}
+
+	rows, _, endpoint, err := tx.execute(span, payload, tx.opts.Timeout, tx.opts.RetryStrategy)
+	if err != nil {
+		return err
+	}
+
+	var row jsonBeginWorkRow
+	if len(rows) > 0 {
+		if err := json.Unmarshal(rows[0], &row); err != nil {
+			return err
+		}
+	}
+	if row.TxID == "" {
+		return &QueryError{
+			InnerError: errors.New("query service did not return a transaction id"),
+			Statement:  "BEGIN WORK",
+			Endpoint:   endpoint,
+		}
+	}
+
+	tx.txid = row.TxID
+	tx.endpoint = endpoint
+	return nil
+}
+
+// Query executes statement within the transaction.  The results are read in full before Query returns.
+// The ScanConsistency, ConsistentWith and Adhoc options are ignored, every statement uses the scan
+// consistency of the transaction.
+func (tx *QueryTransaction) Query(statement string, opts *QueryOptions) (*QueryResult, error) {
+	if opts == nil {
+		opts = &QueryOptions{}
+	}
+
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if err := tx.checkActive(); err != nil {
+		return nil, err
+	}
+
+	if len(tx.interceptors) == 0 {
+		return tx.query(statement, opts)
+	}
+
+	interceptedOpts := *opts
+	req := &ServiceInterceptorRequest{
+		Service:   ServiceTypeQuery,
+		Statement: statement,
+		Options:   &interceptedOpts,
+	}
+	interception, err := interceptServiceRequest(tx.interceptors, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The results have already been read in full, so the request is complete as soon as it returns.
+	res, err := tx.query(req.Statement, &interceptedOpts)
+	if err != nil {
+		interception.complete(nil, err)
+		return nil, err
+	}
+
+	interception.complete(res.MetaData())
+	return res, nil
+}
+
+func (tx *QueryTransaction) query(statement string, opts *QueryOptions) (*QueryResult, error) {
+	span := tx.tracer.StartSpan("Query", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	txOpts := *opts
+	txOpts.ScanConsistency = 0
+	txOpts.ConsistentWith = nil
+	payload, err := txOpts.toMap()
+	if err != nil {
+		return nil, &QueryError{
+			InnerError:      wrapError(err, "failed to generate query options"),
+			Statement:       statement,
+			ClientContextID: opts.ClientContextID,
+		}
+	}
+	payload["statement"] = statement
+	payload["txid"] = tx.txid
+
+	rows, meta, _, err := tx.execute(span, payload, opts.Timeout, opts.RetryStrategy)
+	if err != nil {
+		tx.fail(span, err)
+		return nil, tx.failedError()
+	}
+
+	return newQueryResult(&bufferedQueryRowReader{
+		rows: rows,
+		meta: meta,
+	}), nil
+}
+
+// Commit commits the transaction.  If the commit fails then the transaction is rolled back.
+func (tx *QueryTransaction) Commit() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if err := tx.checkActive(); err != nil {
+		return err
+	}
+
+	span := tx.tracer.StartSpan("CommitQueryTransaction", tx.opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	_, _, _, err := tx.execute(span, map[string]interface{}{
+		"statement": "COMMIT WORK",
+		"txid":      tx.txid,
+	}, tx.opts.Timeout, tx.opts.RetryStrategy)
+	if err != nil {
+		tx.fail(span, err)
+		return tx.failedError()
+	}
+
+	tx.state = queryTransactionCommitted
+	return nil
+}
+
+// Rollback rolls back the transaction.  It does nothing if the transaction has already been rolled back,
+// so it is safe to defer a call to Rollback once the transaction has begun.
+func (tx *QueryTransaction) Rollback() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	switch tx.state {
+	case queryTransactionCommitted:
+		return tx.makeError(ErrQueryTransactionCompleted, nil)
+	case queryTransactionRolledBack:
+		return nil
+	case queryTransactionFailed:
+		if tx.rolledBack {
+			return nil
+		}
+	}
+
+	span := tx.tracer.StartSpan("RollbackQueryTransaction", tx.opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	if err := tx.rollback(span); err != nil {
+		return err
+	}
+
+	if tx.state == queryTransactionActive {
+		tx.state = queryTransactionRolledBack
+	}
+	return nil
+}
+
+func (tx *QueryTransaction) rollback(span RequestSpan) error {
+	_, _, _, err := tx.execute(span, map[string]interface{}{
+		"statement": "ROLLBACK WORK",
+		"txid":      tx.txid,
+	}, tx.opts.Timeout, tx.opts.RetryStrategy)
+	if err != nil {
+		return err
+	}
+
+	tx.rolledBack = true
+	return nil
+}
+
+// fail marks the transaction as failed by err and rolls it back, so that no part of the transaction is
+// applied.
+func (tx *QueryTransaction) fail(span RequestSpan, err error) {
+	tx.state = queryTransactionFailed
+	tx.failure = err
+
+	if rollbackErr := tx.rollback(span); rollbackErr != nil {
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to roll back failed query transaction: %v", rollbackErr)
+	}
+}
+
+func (tx *QueryTransaction) checkActive() error {
+	switch tx.state {
+	case queryTransactionActive:
+		return nil
+	case queryTransactionFailed:
+		return tx.failedError()
+	default:
+		return tx.makeError(ErrQueryTransactionCompleted, nil)
+	}
+}
+
+func (tx *QueryTransaction) failedError() error {
+	return tx.makeError(ErrQueryTransactionFailed, tx.failure)
+}
+
+func (tx *QueryTransaction) makeError(err, cause error) error {
+	return &QueryTransactionError{
+		InnerError:    err,
+		TransactionID: tx.txid,
+		Endpoint:      tx.endpoint,
+		RolledBack:    tx.rolledBack,
+		Cause:         cause,
+	}
+}
+
+// execute sends a request to the query service, pinned to the node which began the transaction once it
+// has begun, returning the rows, the response without its rows and the node which handled it.
+func (tx *QueryTransaction) execute(span RequestSpan, payload map[string]interface{}, timeout time.Duration,
+	retryStrategy RetryStrategy) (rowsOut []json.RawMessage, metaOut []byte, endpointOut string, errOut error) {
+	statement := maybeGetQueryOption(payload, "statement")
+	start := time.Now()
+	defer func() {
+		errOut = tx.retryBudget.maybeMarkExhausted(errOut)
+		tx.retryBudget.recordOutcome(errOut)
+		recordOperationMetric(tx.meter, ServiceTypeQuery, "QueryTransaction", start, errOut, nil)
+	}()
+
+	if timeout == 0 {
+		timeout = tx.queryTimeout
+	}
+	deadline := start.Add(timeout)
+
+	release, err := tx.admission.admit(ServiceTypeQuery, "", deadline)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer release()
+
+	payload["timeout"] = timeout.String()
+
+	// The client context id doubles as the id of the request, so that retries which the retry budget
+	// denied can be attributed to the error.
+	contextID, ok := payload["client_context_id"].(string)
+	if !ok {
+		contextID = uuid.New().String()
+		payload["client_context_id"] = contextID
+	}
+
+	eSpan := tx.tracer.StartSpan("request_encoding", span.Context())
+	body, err := json.Marshal(payload)
+	eSpan.Finish()
+	if err != nil {
+		return nil, nil, "", &QueryError{
+			InnerError:      wrapError(err, "failed to marshall query body"),
+			Statement:       statement,
+			ClientContextID: contextID,
+		}
+	}
+
+	// Any time spent waiting for admission counts towards the timeout.
+	req := mgmtRequest{
+		Service:       ServiceTypeQuery,
+		Method:        "POST",
+		Path:          "/query/service",
+		Body:          body,
+		ContentType:   "application/json",
+		Endpoint:      tx.endpoint,
+		UniqueID:      contextID,
+		Timeout:       time.Until(deadline),
+		RetryStrategy: retryStrategy,
+		parentSpan:    span.Context(),
+	}
+	resp, err := tx.provider.executeMgmtRequest(req)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var jsonResp jsonQueryTransactionResponse
+	if err := json.Unmarshal(respBody, &jsonResp); err != nil {
+		if resp.StatusCode != 200 {
+			return nil, nil, "", makeMgmtBadStatusError("query transaction request failed", &req, resp)
+		}
+		return nil, nil, "", err
+	}
+
+	if resp.StatusCode != 200 || len(jsonResp.Errors) > 0 {
+		descs := make([]QueryErrorDesc, len(jsonResp.Errors))
+		for i, desc := range jsonResp.Errors {
+			descs[i] = QueryErrorDesc{
+				Code:    desc.Code,
+				Message: desc.Msg,
+			}
+		}
+
+		return nil, nil, "", &QueryError{
+			InnerError:      queryErrorDescsCause(descs),
+			Statement:       statement,
+			ClientContextID: contextID,
+			Errors:          descs,
+			Endpoint:        resp.Endpoint,
+		}
+	}
+
+	return jsonResp.Results, respBody, resp.Endpoint, nil
+}
+
+// queryErrorDescsCause classifies the errors returned by the query service in the same way as the
+// underlying client does for queries which it sends.
+func queryErrorDescsCause(descs []QueryErrorDesc) error {
+	if len(descs) == 0 {
+		return errors.New("query error")
+	}
+
+	code := descs[0].Code
+	switch {
+	case code == 3000:
+		return ErrParsingFailure
+	case code == 12009:
+		return ErrCasMismatch
+	case code == 4040 || code == 4050 || code == 4060 || code == 4070 || code == 4080 || code == 4090:
+		return ErrPreparedStatementFailure
+	case code/1000 == 4:
+		return ErrPlanningFailure
+	case code/1000 == 5:
+		return ErrInternalServerFailure
+	case code/1000 == 10:
+		return ErrAuthenticationFailure
+	case code/1000 == 12 || code/1000 == 14:
+		return ErrIndexFailure
+	default:
+		return errors.New("query error")
+	}
+}
+
+func queryDurabilityLevel(level DurabilityLevel) (string, error) {
+	switch level {
+	case DurabilityLevelMajority:
+		return "majority", nil
+	case DurabilityLevelMajorityAndPersistOnMaster:
+		return "majorityAndPersistActive", nil
+	case DurabilityLevelPersistToMajority:
+		return "persistToMajority", nil
+	default:
+		return "", makeInvalidArgumentsError("Unexpected durability level")
+	}
+}
+
+// bufferedQueryRowReader is a queryRowReader over results which have already been read in full.
+type bufferedQueryRowReader struct {
+	rows []json.RawMessage
+	meta []byte
+	idx  int
+}
+
+func (r *bufferedQueryRowReader) NextRow() []byte {
+	if r.idx == len(r.rows) {
+		return nil
+	}
+
+	row := r.rows[r.idx]
+	r.idx++
+	return row
+}
+
+func (r *bufferedQueryRowReader) Err() error {
+	return nil
+}
+
+func (r *bufferedQueryRowReader) MetaData() ([]byte, error) {
+	return r.meta, nil
+}
+
+func (r *bufferedQueryRowReader) Close() error {
+	return nil
+}
+
+func (r *bufferedQueryRowReader) PreparedName() (string, error) {
+	return "", errors.New("statement was not prepared")
+}
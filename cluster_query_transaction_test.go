@@ -0,0 +1,207 @@
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) queryTransactionProvider(respond func(payload map[string]interface{}) (int, string)) (*mockMgmtProvider, *[]mgmtRequest) {
+	var reqs []mgmtRequest
+	provider := new(mockMgmtProvider)
+	provider.
+		On("executeMgmtRequest", mock.AnythingOfType("mgmtRequest")).
+		Return(func(req mgmtRequest) *mgmtResponse {
+			reqs = append(reqs, req)
+
+			var payload map[string]interface{}
+			suite.Require().Nil(json.Unmarshal(req.Body, &payload))
+
+			status, body := respond(payload)
+			return &mgmtResponse{
+				Endpoint:   "http://10.112.0.2:8093",
+				StatusCode: uint32(status),
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	return provider, &reqs
+}
+
+func (suite *UnitTestSuite) newQueryTransaction(provider mgmtProvider, opts *QueryTransactionOptions) (*QueryTransaction, error) {
+	tx := &QueryTransaction{
+		provider:     provider,
+		tracer:       &noopTracer{},
+		queryTimeout: 75 * time.Second,
+		opts:         *opts,
+	}
+
+	return tx, tx.begin()
+}
+
+func (suite *UnitTestSuite) TestQueryTransactionCommit() {
+	var payloads []map[string]interface{}
+	provider, reqs := suite.queryTransactionProvider(func(payload map[string]interface{}) (int, string) {
+		payloads = append(payloads, payload)
+		switch payload["statement"] {
+		case "BEGIN WORK":
+			return 200, `{"results":[{"txid":"tx-1"}],"status":"success"}`
+		case "COMMIT WORK":
+			return 200, `{"results":[],"status":"success"}`
+		default:
+			return 200, `{"results":[{"id":1},{"id":2}],"status":"success","metrics":{"resultCount":2}}`
+		}
+	})
+
+	tx, err := suite.newQueryTransaction(provider, &QueryTransactionOptions{
+		TransactionTimeout: 30 * time.Second,
+		ScanConsistency:    QueryScanConsistencyRequestPlus,
+		DurabilityLevel:    DurabilityLevelMajority,
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("tx-1", tx.ID())
+
+	result, err := tx.Query("UPDATE default SET a = $1 RETURNING id", &QueryOptions{
+		PositionalParameters: []interface{}{1},
+		ScanConsistency:      QueryScanConsistencyNotBounded,
+	})
+	suite.Require().Nil(err, err)
+
+	var rows []map[string]int
+	suite.Require().Nil(result.All(&rows, nil))
+	suite.Assert().Equal([]map[string]int{{"id": 1}, {"id": 2}}, rows)
+
+	meta, err := result.MetaData()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(QueryStatusSuccess, meta.Status)
+
+	suite.Require().Nil(tx.Commit())
+	suite.Assert().True(errors.Is(tx.Commit(), ErrQueryTransactionCompleted))
+	suite.Assert().True(errors.Is(tx.Rollback(), ErrQueryTransactionCompleted))
+
+	suite.Require().Len(*reqs, 3)
+	suite.Assert().Equal("", (*reqs)[0].Endpoint)
+	for _, req := range (*reqs)[1:] {
+		suite.Assert().Equal("http://10.112.0.2:8093", req.Endpoint)
+		suite.Assert().Equal("/query/service", req.Path)
+		suite.Assert().Equal(ServiceTypeQuery, req.Service)
+	}
+
+	suite.Assert().Equal("30s", payloads[0]["txtimeout"])
+	suite.Assert().Equal("1m15s", payloads[0]["timeout"])
+	suite.Assert().Equal(payloads[0]["client_context_id"], (*reqs)[0].UniqueID)
+	suite.Assert().Equal("request_plus", payloads[0]["scan_consistency"])
+	suite.Assert().Equal("majority", payloads[0]["durability_level"])
+	suite.Assert().Equal("tx-1", payloads[1]["txid"])
+	suite.Assert().NotContains(payloads[1], "scan_consistency")
+	suite.Assert().Equal([]interface{}{float64(1)}, payloads[1]["args"])
+	suite.Assert().Equal("tx-1", payloads[2]["txid"])
+}
+
+func (suite *UnitTestSuite) TestQueryTransactionStatementFailure() {
+	var statements []string
+	provider, _ := suite.queryTransactionProvider(func(payload map[string]interface{}) (int, string) {
+		statements = append(statements, payload["statement"].(string))
+		switch payload["statement"] {
+		case "BEGIN WORK":
+			return 200, `{"results":[{"txid":"tx-1"}],"status":"success"}`
+		case "ROLLBACK WORK":
+			return 200, `{"results":[],"status":"success"}`
+		default:
+			return 400, `{"errors":[{"code":3000,"msg":"syntax error"}],"status":"fatal"}`
+		}
+	})
+
+	tx, err := suite.newQueryTransaction(provider, &QueryTransactionOptions{})
+	suite.Require().Nil(err, err)
+
+	_, err = tx.Query("UPDATE default SET", nil)
+	suite.Require().NotNil(err)
+	suite.Assert().True(errors.Is(err, ErrQueryTransactionFailed))
+	suite.Assert().True(errors.Is(err, ErrParsingFailure))
+
+	var txErr *QueryTransactionError
+	suite.Require().True(errors.As(err, &txErr))
+	suite.Assert().True(txErr.RolledBack)
+	suite.Assert().Equal("tx-1", txErr.TransactionID)
+
+	_, err = tx.Query("SELECT 1", nil)
+	suite.Assert().True(errors.Is(err, ErrQueryTransactionFailed))
+	suite.Assert().True(errors.Is(tx.Commit(), ErrQueryTransactionFailed))
+	suite.Assert().Nil(tx.Rollback())
+
+	suite.Assert().Equal([]string{"BEGIN WORK", "UPDATE default SET", "ROLLBACK WORK"}, statements)
+}
+
+func (suite *UnitTestSuite) TestQueryTransactionRollback() {
+	var statements []string
+	provider, _ := suite.queryTransactionProvider(func(payload map[string]interface{}) (int, string) {
+		statements = append(statements, payload["statement"].(string))
+		if payload["statement"] == "BEGIN WORK" {
+			return 200, `{"results":[{"txid":"tx-1"}],"status":"success"}`
+		}
+		return 200, `{"results":[],"status":"success"}`
+	})
+
+	tx, err := suite.newQueryTransaction(provider, &QueryTransactionOptions{})
+	suite.Require().Nil(err, err)
+
+	suite.Require().Nil(tx.Rollback())
+	suite.Require().Nil(tx.Rollback())
+	suite.Assert().True(errors.Is(tx.Commit(), ErrQueryTransactionCompleted))
+	suite.Assert().Equal([]string{"BEGIN WORK", "ROLLBACK WORK"}, statements)
+}
+
+func (suite *UnitTestSuite) TestQueryTransactionBeginFailure() {
+	provider, _ := suite.queryTransactionProvider(func(payload map[string]interface{}) (int, string) {
+		return 200, `{"results":[],"status":"success"}`
+	})
+
+	_, err := suite.newQueryTransaction(provider, &QueryTransactionOptions{})
+	suite.Require().NotNil(err)
+
+	var qErr *QueryError
+	suite.Require().True(errors.As(err, &qErr))
+	suite.Assert().Equal("BEGIN WORK", qErr.Statement)
+}
+
+func (suite *UnitTestSuite) TestQueryTransactionInterceptors() {
+	var statements []string
+	provider, _ := suite.queryTransactionProvider(func(payload map[string]interface{}) (int, string) {
+		statements = append(statements, payload["statement"].(string))
+		switch payload["statement"] {
+		case "BEGIN WORK":
+			return 200, `{"results":[{"txid":"tx-1"}],"status":"success"}`
+		default:
+			return 200, `{"results":[{"id":1}],"status":"success"}`
+		}
+	})
+
+	var afterMeta interface{}
+	interceptor := &testServiceInterceptor{
+		before: func(req *ServiceInterceptorRequest) error {
+			req.Statement += " LIMIT 1"
+			req.Options.(*QueryOptions).Timeout = 10 * time.Second
+			return nil
+		},
+		after: func(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+			suite.Assert().Nil(err)
+			afterMeta = metaData
+		},
+	}
+
+	tx, err := suite.newQueryTransaction(provider, &QueryTransactionOptions{})
+	suite.Require().Nil(err, err)
+	tx.interceptors = []ServiceInterceptor{interceptor}
+
+	_, err = tx.Query("SELECT id FROM default", &QueryOptions{Timeout: 5 * time.Second})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{"BEGIN WORK", "SELECT id FROM default LIMIT 1"}, statements)
+	suite.Require().IsType(&QueryMetaData{}, afterMeta)
+	suite.Assert().Equal(QueryStatusSuccess, afterMeta.(*QueryMetaData).Status)
+}
@@ -0,0 +1,590 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// QueryFunctionLanguage is the language in which a query function is implemented.
+// UNCOMMITTED: This API may change in the future.
+type QueryFunctionLanguage string
+
+const (
+	// QueryFunctionLanguageInline indicates a function whose body is a N1QL expression.
+	QueryFunctionLanguageInline QueryFunctionLanguage = "inline"
+
+	// QueryFunctionLanguageJavaScript indicates a function implemented by a JavaScript library.
+	QueryFunctionLanguageJavaScript QueryFunctionLanguage = "javascript"
+)
+
+// QueryFunctionManager provides methods for managing N1QL user-defined functions and the JavaScript
+// libraries which implement them, either globally or within a scope.
+// UNCOMMITTED: This API may change in the future.
+type QueryFunctionManager struct {
+	runQuery     queryRunner
+	mgmtProvider mgmtProvider
+
+	bucketName string
+	scopeName  string
+
+	globalTimeout time.Duration
+	tracer        RequestTracer
+}
+
+// QueryFunctions returns a QueryFunctionManager for managing global query functions.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) QueryFunctions() *QueryFunctionManager {
+	return &QueryFunctionManager{
+		runQuery:      c.Query,
+		mgmtProvider:  c,
+		globalTimeout: c.timeoutsConfig.ManagementTimeout,
+		tracer:        c.tracer,
+	}
+}
+
+// QueryFunctions returns a QueryFunctionManager for managing the query functions within this scope.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) QueryFunctions() *QueryFunctionManager {
+	return &QueryFunctionManager{
+		runQuery:      s.runQuery,
+		mgmtProvider:  s.bucket,
+		bucketName:    s.BucketName(),
+		scopeName:     s.Name(),
+		globalTimeout: s.bucket.timeoutsConfig.ManagementTimeout,
+		tracer:        s.tracer,
+	}
+}
+
+// QueryFunction is a N1QL user-defined function.
+// UNCOMMITTED: This API may change in the future.
+type QueryFunction struct {
+	Name string
+
+	// BucketName and ScopeName are the scope which the function belongs to, they are empty for global
+	// functions.  They are ignored when creating a function, which is always created within the scope
+	// of the manager.
+	BucketName string
+	ScopeName  string
+
+	Language QueryFunctionLanguage
+
+	// Parameters are the names of the parameters of the function.  A variadic function accepts any
+	// arguments, which it accesses using args.
+	Parameters []string
+	Variadic   bool
+
+	// Expression is the body of an inline function.
+	Expression string
+
+	// Library is the JavaScript library which implements the function, and LibraryFunction is the name
+	// of the function within the library.  Libraries within a scope are referred to as bucket/scope/name.
+	Library         string
+	LibraryFunction string
+}
+
+type jsonQueryFunctionIdentity struct {
+	Name   string `json:"name"`
+	Bucket string `json:"bucket,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	Type   string `json:"type"`
+}
+
+type jsonQueryFunctionDefinition struct {
+	Language   QueryFunctionLanguage `json:"#language"`
+	Parameters []string              `json:"parameters"`
+	Expression string                `json:"expression,omitempty"`
+	Library    string                `json:"library,omitempty"`
+	Object     string                `json:"object,omitempty"`
+}
+
+type jsonQueryFunction struct {
+	Identity   jsonQueryFunctionIdentity   `json:"identity"`
+	Definition jsonQueryFunctionDefinition `json:"definition"`
+}
+
+func (fn *QueryFunction) fromData(data jsonQueryFunction) error {
+	fn.Name = data.Identity.Name
+	fn.BucketName = data.Identity.Bucket
+	fn.ScopeName = data.Identity.Scope
+	fn.Language = data.Definition.Language
+	fn.Expression = data.Definition.Expression
+	fn.Library = data.Definition.Library
+	fn.LibraryFunction = data.Definition.Object
+
+	if len(data.Definition.Parameters) == 1 && data.Definition.Parameters[0] == "..." {
+		fn.Variadic = true
+	} else {
+		fn.Parameters = data.Definition.Parameters
+	}
+
+	return nil
+}
+
+// QueryFunctionLibrary is a JavaScript library which implements query functions.
+// UNCOMMITTED: This API may change in the future.
+type QueryFunctionLibrary struct {
+	Name string
+
+	// BucketName and ScopeName are the scope which the library belongs to, they are empty for global
+	// libraries.
+	BucketName string
+	ScopeName  string
+
+	Code string
+}
+
+type jsonQueryFunctionLibrary struct {
+	Name   string `json:"name"`
+	Bucket string `json:"bucket,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	Code   string `json:"code"`
+}
+
+var queryFunctionParameterRegexp = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+func (fm *QueryFunctionManager) tryParseErrorMessage(err error) error {
+	var qErr *QueryError
+	if !errors.As(err, &qErr) {
+		return err
+	}
+
+	if len(qErr.Errors) == 0 {
+		return err
+	}
+
+	var innerErr error
+	// As with indexes, the server doesn't return consistent error codes for functions.
+	msg := strings.ToLower(qErr.Errors[0].Message)
+	if match, err := regexp.MatchString(".*?function .*? not found.*", msg); err == nil && match {
+		innerErr = ErrQueryFunctionNotFound
+	} else if match, err := regexp.MatchString(".*?function .*? already exists.*", msg); err == nil && match {
+		innerErr = ErrQueryFunctionExists
+	}
+
+	if innerErr == nil {
+		return err
+	}
+
+	return QueryError{
+		InnerError:      innerErr,
+		Statement:       qErr.Statement,
+		ClientContextID: qErr.ClientContextID,
+		Errors:          qErr.Errors,
+		Endpoint:        qErr.Endpoint,
+		RetryReasons:    qErr.RetryReasons,
+		RetryAttempts:   qErr.RetryAttempts,
+	}
+}
+
+func (fm *QueryFunctionManager) doQuery(q string, opts *QueryOptions) ([]json.RawMessage, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = fm.globalTimeout
+	}
+
+	result, err := fm.runQuery(q, opts)
+	if err != nil {
+		return nil, fm.tryParseErrorMessage(err)
+	}
+
+	var rows []json.RawMessage
+	if err := result.All(&rows, &ResultAllOptions{MaxRows: ^uint32(0)}); err != nil {
+		return nil, fm.tryParseErrorMessage(err)
+	}
+
+	return rows, nil
+}
+
+// functionName returns the fully qualified name of a function within the scope of the manager.
+func (fm *QueryFunctionManager) functionName(name string) string {
+	if fm.scopeName == "" {
+		return fmt.Sprintf("default:`%s`", name)
+	}
+
+	return fmt.Sprintf("default:`%s`.`%s`.`%s`", fm.bucketName, fm.scopeName, name)
+}
+
+// CreateQueryFunctionOptions is the set of options available to the query functions CreateFunction
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type CreateQueryFunctionOptions struct {
+	IgnoreIfExists bool
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// CreateFunction creates a function, failing with ErrQueryFunctionExists if it already exists.
+func (fm *QueryFunctionManager) CreateFunction(fn QueryFunction, opts *CreateQueryFunctionOptions) error {
+	if opts == nil {
+		opts = &CreateQueryFunctionOptions{}
+	}
+
+	span := fm.tracer.StartSpan("CreateFunction", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	err := fm.createFunction(span.Context(), "CREATE FUNCTION ", fn, opts.Timeout, opts.RetryStrategy)
+	if err != nil {
+		if opts.IgnoreIfExists && errors.Is(err, ErrQueryFunctionExists) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpsertQueryFunctionOptions is the set of options available to the query functions UpsertFunction
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type UpsertQueryFunctionOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// UpsertFunction creates a function, or replaces it if it already exists.
+func (fm *QueryFunctionManager) UpsertFunction(fn QueryFunction, opts *UpsertQueryFunctionOptions) error {
+	if opts == nil {
+		opts = &UpsertQueryFunctionOptions{}
+	}
+
+	span := fm.tracer.StartSpan("UpsertFunction", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	return fm.createFunction(span.Context(), "CREATE OR REPLACE FUNCTION ", fn, opts.Timeout, opts.RetryStrategy)
+}
+
+func (fm *QueryFunctionManager) createFunction(
+	tracectx RequestSpanContext,
+	verb string,
+	fn QueryFunction,
+	timeout time.Duration,
+	retryStrategy RetryStrategy,
+) error {
+	if fn.Name == "" {
+		return makeInvalidArgumentsError("function name cannot be empty")
+	}
+
+	params := "..."
+	if !fn.Variadic {
+		for _, param := range fn.Parameters {
+			if !queryFunctionParameterRegexp.MatchString(param) {
+				return makeInvalidArgumentsError("invalid function parameter name " + param)
+			}
+		}
+		params = strings.Join(fn.Parameters, ", ")
+	} else if len(fn.Parameters) > 0 {
+		return makeInvalidArgumentsError("variadic functions cannot have named parameters")
+	}
+
+	q := verb + fm.functionName(fn.Name) + "(" + params + ")"
+	switch fn.Language {
+	case QueryFunctionLanguageInline:
+		if fn.Expression == "" {
+			return makeInvalidArgumentsError("inline functions must have an expression")
+		}
+		q += " LANGUAGE INLINE AS " + fn.Expression
+	case QueryFunctionLanguageJavaScript:
+		if fn.Library == "" || fn.LibraryFunction == "" {
+			return makeInvalidArgumentsError("javascript functions must have a library and library function")
+		}
+		object, err := json.Marshal(fn.LibraryFunction)
+		if err != nil {
+			return err
+		}
+		library, err := json.Marshal(fn.Library)
+		if err != nil {
+			return err
+		}
+		q += " LANGUAGE JAVASCRIPT AS " + string(object) + " AT " + string(library)
+	default:
+		return makeInvalidArgumentsError("unknown function language")
+	}
+
+	_, err := fm.doQuery(q, &QueryOptions{
+		Timeout:       timeout,
+		RetryStrategy: retryStrategy,
+		Adhoc:         true,
+		ParentSpan:    tracectx,
+	})
+	return err
+}
+
+// DropQueryFunctionOptions is the set of options available to the query functions DropFunction operation.
+// UNCOMMITTED: This API may change in the future.
+type DropQueryFunctionOptions struct {
+	IgnoreIfNotExists bool
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// DropFunction drops a function, failing with ErrQueryFunctionNotFound if it does not exist.
+func (fm *QueryFunctionManager) DropFunction(name string, opts *DropQueryFunctionOptions) error {
+	if opts == nil {
+		opts = &DropQueryFunctionOptions{}
+	}
+
+	if name == "" {
+		return makeInvalidArgumentsError("function name cannot be empty")
+	}
+
+	span := fm.tracer.StartSpan("DropFunction", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	_, err := fm.doQuery("DROP FUNCTION "+fm.functionName(name), &QueryOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		Adhoc:         true,
+		ParentSpan:    span.Context(),
+	})
+	if err != nil {
+		if opts.IgnoreIfNotExists && errors.Is(err, ErrQueryFunctionNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetAllQueryFunctionsOptions is the set of options available to the query functions GetAllFunctions
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAllQueryFunctionsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// GetAllFunctions returns the functions within the scope of the manager.
+func (fm *QueryFunctionManager) GetAllFunctions(opts *GetAllQueryFunctionsOptions) ([]QueryFunction, error) {
+	if opts == nil {
+		opts = &GetAllQueryFunctionsOptions{}
+	}
+
+	span := fm.tracer.StartSpan("GetAllFunctions", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	q := "SELECT f.* FROM system:functions AS f WHERE f.identity.type = \"global\""
+	var args []interface{}
+	if fm.scopeName != "" {
+		q = "SELECT f.* FROM system:functions AS f WHERE f.identity.bucket = ? AND f.identity.`scope` = ?"
+		args = []interface{}{fm.bucketName, fm.scopeName}
+	}
+
+	rows, err := fm.doQuery(q, &QueryOptions{
+		PositionalParameters: args,
+		Readonly:             true,
+		Timeout:              opts.Timeout,
+		RetryStrategy:        opts.RetryStrategy,
+		Adhoc:                true,
+		ParentSpan:           span.Context(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []QueryFunction
+	for _, row := range rows {
+		var jsonFn jsonQueryFunction
+		if err := json.Unmarshal(row, &jsonFn); err != nil {
+			return nil, err
+		}
+
+		var fn QueryFunction
+		if err := fn.fromData(jsonFn); err != nil {
+			return nil, err
+		}
+
+		functions = append(functions, fn)
+	}
+
+	return functions, nil
+}
+
+// libraryPath returns the path of the evaluator API for a library within the scope of the manager, or
+// of every library if name is empty.
+func (fm *QueryFunctionManager) libraryPath(name string) string {
+	path := "/evaluator/v1/libraries"
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+
+	if fm.scopeName != "" {
+		query := url.Values{}
+		query.Set("bucket", fm.bucketName)
+		query.Set("scope", fm.scopeName)
+		path += "?" + query.Encode()
+	}
+
+	return path
+}
+
+func (fm *QueryFunctionManager) doMgmtRequest(req mgmtRequest) ([]byte, error) {
+	if req.Timeout == 0 {
+		req.Timeout = fm.globalTimeout
+	}
+
+	resp, err := fm.mgmtProvider.executeMgmtRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, makeGenericMgmtError(ErrQueryFunctionLibraryNotFound, &req, resp)
+	}
+	if resp.StatusCode != 200 {
+		if len(body) > 0 {
+			return nil, makeGenericMgmtError(errors.New(string(body)), &req, resp)
+		}
+		return nil, makeMgmtBadStatusError("failed to "+strings.ToLower(req.Method)+" query function library", &req, resp)
+	}
+
+	return body, nil
+}
+
+// UpsertQueryFunctionLibraryOptions is the set of options available to the query functions UpsertLibrary
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type UpsertQueryFunctionLibraryOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// UpsertLibrary uploads a JavaScript library, replacing it if it already exists.
+func (fm *QueryFunctionManager) UpsertLibrary(name, code string, opts *UpsertQueryFunctionLibraryOptions) error {
+	if opts == nil {
+		opts = &UpsertQueryFunctionLibraryOptions{}
+	}
+
+	if name == "" {
+		return makeInvalidArgumentsError("library name cannot be empty")
+	}
+
+	span := fm.tracer.StartSpan("UpsertLibrary", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	_, err := fm.doMgmtRequest(mgmtRequest{
+		Service:       ServiceTypeQuery,
+		Method:        "POST",
+		Path:          fm.libraryPath(name),
+		Body:          []byte(code),
+		ContentType:   "application/json",
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       opts.Timeout,
+		parentSpan:    span.Context(),
+	})
+	return err
+}
+
+// DropQueryFunctionLibraryOptions is the set of options available to the query functions DropLibrary
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type DropQueryFunctionLibraryOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// DropLibrary deletes a JavaScript library, failing with ErrQueryFunctionLibraryNotFound if it does not
+// exist.
+func (fm *QueryFunctionManager) DropLibrary(name string, opts *DropQueryFunctionLibraryOptions) error {
+	if opts == nil {
+		opts = &DropQueryFunctionLibraryOptions{}
+	}
+
+	if name == "" {
+		return makeInvalidArgumentsError("library name cannot be empty")
+	}
+
+	span := fm.tracer.StartSpan("DropLibrary", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	_, err := fm.doMgmtRequest(mgmtRequest{
+		Service:       ServiceTypeQuery,
+		Method:        "DELETE",
+		Path:          fm.libraryPath(name),
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       opts.Timeout,
+		parentSpan:    span.Context(),
+	})
+	return err
+}
+
+// GetAllQueryFunctionLibrariesOptions is the set of options available to the query functions
+// GetAllLibraries operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAllQueryFunctionLibrariesOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// GetAllLibraries returns the JavaScript libraries within the scope of the manager.
+func (fm *QueryFunctionManager) GetAllLibraries(opts *GetAllQueryFunctionLibrariesOptions) ([]QueryFunctionLibrary, error) {
+	if opts == nil {
+		opts = &GetAllQueryFunctionLibrariesOptions{}
+	}
+
+	span := fm.tracer.StartSpan("GetAllLibraries", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	body, err := fm.doMgmtRequest(mgmtRequest{
+		Service:       ServiceTypeQuery,
+		Method:        "GET",
+		Path:          fm.libraryPath(""),
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       opts.Timeout,
+		parentSpan:    span.Context(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonLibraries []jsonQueryFunctionLibrary
+	if err := json.Unmarshal(body, &jsonLibraries); err != nil {
+		return nil, err
+	}
+
+	var libraries []QueryFunctionLibrary
+	for _, library := range jsonLibraries {
+		if library.Bucket != fm.bucketName || library.Scope != fm.scopeName {
+			continue
+		}
+
+		libraries = append(libraries, QueryFunctionLibrary{
+			Name:       library.Name,
+			BucketName: library.Bucket,
+			ScopeName:  library.Scope,
+			Code:       library.Code,
+		})
+	}
+
+	return libraries, nil
+}
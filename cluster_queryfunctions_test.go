@@ -0,0 +1,188 @@
+package gocb
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) queryFunctionManager(scoped bool, rows [][]byte, queryErr error) (*QueryFunctionManager, *[]string) {
+	var statements []string
+	mgr := &QueryFunctionManager{
+		runQuery: func(statement string, opts *QueryOptions) (*QueryResult, error) {
+			statements = append(statements, statement)
+			suite.Assert().Equal(5*time.Second, opts.Timeout)
+			if queryErr != nil {
+				return nil, queryErr
+			}
+			return newQueryResult(&mockQueryRowReaderRows{Rows: rows}), nil
+		},
+		globalTimeout: 5 * time.Second,
+		tracer:        &noopTracer{},
+	}
+	if scoped {
+		mgr.bucketName = "travel-sample"
+		mgr.scopeName = "inventory"
+	}
+
+	return mgr, &statements
+}
+
+func (suite *UnitTestSuite) TestQueryFunctionManagerCreateFunction() {
+	mgr, statements := suite.queryFunctionManager(false, nil, nil)
+
+	err := mgr.CreateFunction(QueryFunction{
+		Name:       "celsius",
+		Language:   QueryFunctionLanguageInline,
+		Parameters: []string{"fahrenheit"},
+		Expression: "(fahrenheit - 32) * 5 / 9",
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.UpsertFunction(QueryFunction{
+		Name:       "sum",
+		Language:   QueryFunctionLanguageInline,
+		Variadic:   true,
+		Expression: "ARRAY_SUM(args)",
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	scoped, scopedStatements := suite.queryFunctionManager(true, nil, nil)
+	err = scoped.CreateFunction(QueryFunction{
+		Name:            "add",
+		Language:        QueryFunctionLanguageJavaScript,
+		Parameters:      []string{"a", "b"},
+		Library:         "math",
+		LibraryFunction: "add",
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{
+		"CREATE FUNCTION default:`celsius`(fahrenheit) LANGUAGE INLINE AS (fahrenheit - 32) * 5 / 9",
+		"CREATE OR REPLACE FUNCTION default:`sum`(...) LANGUAGE INLINE AS ARRAY_SUM(args)",
+	}, *statements)
+	suite.Assert().Equal([]string{
+		"CREATE FUNCTION default:`travel-sample`.`inventory`.`add`(a, b) LANGUAGE JAVASCRIPT AS \"add\" AT \"math\"",
+	}, *scopedStatements)
+
+	err = mgr.CreateFunction(QueryFunction{
+		Name:       "bad",
+		Language:   QueryFunctionLanguageInline,
+		Parameters: []string{"a) LANGUAGE"},
+		Expression: "a",
+	}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	err = mgr.CreateFunction(QueryFunction{Name: "bad", Language: QueryFunctionLanguageJavaScript}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestQueryFunctionManagerErrors() {
+	exists := &QueryError{
+		InnerError: errors.New("query error"),
+		Errors:     []QueryErrorDesc{{Code: 10102, Message: "Function 'default:celsius' already exists"}},
+	}
+	mgr, _ := suite.queryFunctionManager(false, nil, exists)
+
+	fn := QueryFunction{Name: "celsius", Language: QueryFunctionLanguageInline, Expression: "1"}
+	err := mgr.CreateFunction(fn, nil)
+	suite.Assert().True(errors.Is(err, ErrQueryFunctionExists))
+	suite.Assert().Nil(mgr.CreateFunction(fn, &CreateQueryFunctionOptions{IgnoreIfExists: true}))
+
+	notFound := &QueryError{
+		InnerError: errors.New("query error"),
+		Errors:     []QueryErrorDesc{{Code: 10101, Message: "Function 'default:celsius' not found"}},
+	}
+	mgr, statements := suite.queryFunctionManager(true, nil, notFound)
+
+	err = mgr.DropFunction("celsius", nil)
+	suite.Assert().True(errors.Is(err, ErrQueryFunctionNotFound))
+	suite.Assert().Nil(mgr.DropFunction("celsius", &DropQueryFunctionOptions{IgnoreIfNotExists: true}))
+	suite.Assert().Equal("DROP FUNCTION default:`travel-sample`.`inventory`.`celsius`", (*statements)[0])
+}
+
+func (suite *UnitTestSuite) TestQueryFunctionManagerGetAllFunctions() {
+	mgr, statements := suite.queryFunctionManager(false, [][]byte{
+		[]byte(`{"identity":{"name":"celsius","namespace":"default","type":"global"},
+			"definition":{"#language":"inline","expression":"(fahrenheit - 32) * 5 / 9","parameters":["fahrenheit"]}}`),
+		[]byte(`{"identity":{"name":"add","namespace":"default","type":"global"},
+			"definition":{"#language":"javascript","library":"math","object":"add","parameters":["..."]}}`),
+	}, nil)
+
+	functions, err := mgr.GetAllFunctions(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]QueryFunction{
+		{
+			Name:       "celsius",
+			Language:   QueryFunctionLanguageInline,
+			Parameters: []string{"fahrenheit"},
+			Expression: "(fahrenheit - 32) * 5 / 9",
+		},
+		{
+			Name:            "add",
+			Language:        QueryFunctionLanguageJavaScript,
+			Variadic:        true,
+			Library:         "math",
+			LibraryFunction: "add",
+		},
+	}, functions)
+	suite.Assert().Contains((*statements)[0], "f.identity.type = \"global\"")
+}
+
+func (suite *UnitTestSuite) TestQueryFunctionManagerLibraries() {
+	var reqs []mgmtRequest
+	provider := new(mockMgmtProvider)
+	provider.
+		On("executeMgmtRequest", mock.AnythingOfType("mgmtRequest")).
+		Return(func(req mgmtRequest) *mgmtResponse {
+			reqs = append(reqs, req)
+
+			body := ""
+			status := 200
+			switch req.Method {
+			case "GET":
+				body = `[{"name":"math","code":"function add(a, b) { return a + b; }"},
+					{"name":"math","bucket":"travel-sample","scope":"inventory","code":"function sub(a, b) { return a - b; }"}]`
+			case "DELETE":
+				status = 404
+			}
+			return &mgmtResponse{
+				StatusCode: uint32(status),
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	mgr := &QueryFunctionManager{
+		mgmtProvider:  provider,
+		bucketName:    "travel-sample",
+		scopeName:     "inventory",
+		globalTimeout: 5 * time.Second,
+		tracer:        &noopTracer{},
+	}
+
+	err := mgr.UpsertLibrary("math", "function sub(a, b) { return a - b; }", nil)
+	suite.Require().Nil(err, err)
+
+	libraries, err := mgr.GetAllLibraries(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]QueryFunctionLibrary{{
+		Name:       "math",
+		BucketName: "travel-sample",
+		ScopeName:  "inventory",
+		Code:       "function sub(a, b) { return a - b; }",
+	}}, libraries)
+
+	err = mgr.DropLibrary("math", nil)
+	suite.Assert().True(errors.Is(err, ErrQueryFunctionLibraryNotFound))
+
+	suite.Require().Len(reqs, 3)
+	suite.Assert().Equal("/evaluator/v1/libraries/math?bucket=travel-sample&scope=inventory", reqs[0].Path)
+	suite.Assert().Equal("POST", reqs[0].Method)
+	suite.Assert().Equal([]byte("function sub(a, b) { return a - b; }"), reqs[0].Body)
+	suite.Assert().Equal(ServiceTypeQuery, reqs[0].Service)
+	suite.Assert().Equal(5*time.Second, reqs[0].Timeout)
+	suite.Assert().Equal("/evaluator/v1/libraries?bucket=travel-sample&scope=inventory", reqs[1].Path)
+}
@@ -10,10 +10,11 @@ import (
 
 // QueryIndexManager provides methods for performing Couchbase query index management.
 type QueryIndexManager struct {
-	provider queryIndexQueryProvider
+	provider     queryIndexQueryProvider
+	mgmtProvider mgmtProvider
 
 	globalTimeout time.Duration
-	tracer        requestTracer
+	tracer        RequestTracer
 }
 
 type queryIndexQueryProvider interface {
@@ -70,7 +71,7 @@ func (qm *QueryIndexManager) doQuery(q string, opts *QueryOptions) ([][]byte, er
 		var row json.RawMessage
 		err := result.Row(&row)
 		if err != nil {
-			logWarnf("management operation failed to read row: %s", err)
+			logFieldsf(LogSubsystemManagement, LogWarn, nil, "management operation failed to read row: %s", err)
 		} else {
 			rows = append(rows, row)
 		}
@@ -92,6 +93,12 @@ type jsonQueryIndex struct {
 	Namespace string         `json:"namespace_id"`
 	IndexKey  []string       `json:"index_key"`
 	Condition string         `json:"condition"`
+	Partition string         `json:"partition"`
+	Bucket    string         `json:"bucket_id"`
+	Scope     string         `json:"scope_id"`
+	Metadata  struct {
+		NumReplica uint32 `json:"num_replica"`
+	} `json:"metadata"`
 }
 
 // QueryIndex represents a Couchbase GSI index.
@@ -104,6 +111,22 @@ type QueryIndex struct {
 	Namespace string
 	IndexKey  []string
 	Condition string
+
+	// BucketName is the bucket of the index.  ScopeName and CollectionName are only set for indexes
+	// which were created on a collection rather than directly on the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	BucketName     string
+	ScopeName      string
+	CollectionName string
+
+	// Partition is the partitioning expression of a partitioned index, e.g. HASH(`country`).
+	// UNCOMMITTED: This API may change in the future.
+	Partition string
+
+	// NumReplicas is the number of replicas of the index, this is only reported by servers which
+	// include index metadata in system:indexes.
+	// UNCOMMITTED: This API may change in the future.
+	NumReplicas uint32
 }
 
 func (index *QueryIndex) fromData(data jsonQueryIndex) error {
@@ -115,24 +138,62 @@ func (index *QueryIndex) fromData(data jsonQueryIndex) error {
 	index.Namespace = data.Namespace
 	index.IndexKey = data.IndexKey
 	index.Condition = data.Condition
+	index.Partition = data.Partition
+	index.NumReplicas = data.Metadata.NumReplica
+
+	if data.Bucket == "" {
+		index.BucketName = data.Keyspace
+	} else {
+		index.BucketName = data.Bucket
+		index.ScopeName = data.Scope
+		index.CollectionName = data.Keyspace
+	}
 
 	return nil
 }
 
+// queryIndexKeyspace returns the keyspace that indexes are managed on, which is the bucket itself unless
+// a collection is specified.  A collection without a scope belongs to the default scope.
+func queryIndexKeyspace(bucketName, scopeName, collectionName string) (string, error) {
+	if scopeName == "" && collectionName == "" {
+		return "`" + bucketName + "`", nil
+	}
+	if collectionName == "" {
+		return "", makeInvalidArgumentsError("a collection name must be specified with a scope name")
+	}
+	if scopeName == "" {
+		scopeName = "_default"
+	}
+
+	return "`" + bucketName + "`.`" + scopeName + "`.`" + collectionName + "`", nil
+}
+
 type createQueryIndexOptions struct {
 	IgnoreIfExists bool
 	Deferred       bool
 
+	ScopeName      string
+	CollectionName string
+	NumReplicas    uint32
+	Nodes          []string
+	PartitionBy    []string
+	Condition      string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 }
 
 func (qm *QueryIndexManager) createIndex(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	bucketName, indexName string,
 	fields []string,
 	opts createQueryIndexOptions,
 ) error {
+	keyspace, err := queryIndexKeyspace(bucketName, opts.ScopeName, opts.CollectionName)
+	if err != nil {
+		return err
+	}
+
 	var qs string
 
 	if len(fields) == 0 {
@@ -143,7 +204,7 @@ func (qm *QueryIndexManager) createIndex(
 	if indexName != "" {
 		qs += " `" + indexName + "`"
 	}
-	qs += " ON `" + bucketName + "`"
+	qs += " ON " + keyspace
 	if len(fields) > 0 {
 		qs += " ("
 		for i := 0; i < len(fields); i++ {
@@ -154,15 +215,36 @@ func (qm *QueryIndexManager) createIndex(
 		}
 		qs += ")"
 	}
+	if len(opts.PartitionBy) > 0 {
+		qs += " PARTITION BY HASH(" + strings.Join(opts.PartitionBy, ", ") + ")"
+	}
+	if opts.Condition != "" {
+		qs += " WHERE " + opts.Condition
+	}
+
+	with := make(map[string]interface{})
 	if opts.Deferred {
-		qs += " WITH {\"defer_build\": true}"
+		with["defer_build"] = true
+	}
+	if opts.NumReplicas > 0 {
+		with["num_replica"] = opts.NumReplicas
+	}
+	if len(opts.Nodes) > 0 {
+		with["nodes"] = opts.Nodes
+	}
+	if len(with) > 0 {
+		withBytes, err := json.Marshal(with)
+		if err != nil {
+			return err
+		}
+		qs += " WITH " + string(withBytes)
 	}
 
-	_, err := qm.doQuery(qs, &QueryOptions{
+	_, err = qm.doQuery(qs, &QueryOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
 		Adhoc:         true,
-		parentSpan:    tracectx,
+		ParentSpan:    tracectx,
 	})
 	if err == nil {
 		return nil
@@ -180,8 +262,30 @@ type CreateQueryIndexOptions struct {
 	IgnoreIfExists bool
 	Deferred       bool
 
+	// ScopeName and CollectionName create the index on a collection rather than on the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
+	// NumReplicas is the number of replicas of the index to create in addition to the index itself.
+	// UNCOMMITTED: This API may change in the future.
+	NumReplicas uint32
+
+	// Nodes are the index nodes, as host:port, which the index and its replicas are placed on.
+	// UNCOMMITTED: This API may change in the future.
+	Nodes []string
+
+	// PartitionBy are the expressions which the index is hash partitioned by, e.g. META().id.
+	// UNCOMMITTED: This API may change in the future.
+	PartitionBy []string
+
+	// Condition is a WHERE clause expression which limits the documents that are indexed.
+	// UNCOMMITTED: This API may change in the future.
+	Condition string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreateIndex creates an index over the specified fields.
@@ -201,13 +305,19 @@ func (qm *QueryIndexManager) CreateIndex(bucketName, indexName string, fields []
 		}
 	}
 
-	span := qm.tracer.StartSpan("CreateIndex", nil).
+	span := qm.tracer.StartSpan("CreateIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
 	return qm.createIndex(span.Context(), bucketName, indexName, fields, createQueryIndexOptions{
 		IgnoreIfExists: opts.IgnoreIfExists,
 		Deferred:       opts.Deferred,
+		ScopeName:      opts.ScopeName,
+		CollectionName: opts.CollectionName,
+		NumReplicas:    opts.NumReplicas,
+		Nodes:          opts.Nodes,
+		PartitionBy:    opts.PartitionBy,
+		Condition:      opts.Condition,
 		Timeout:        opts.Timeout,
 		RetryStrategy:  opts.RetryStrategy,
 	})
@@ -219,8 +329,22 @@ type CreatePrimaryQueryIndexOptions struct {
 	Deferred       bool
 	CustomName     string
 
+	// ScopeName and CollectionName create the index on a collection rather than on the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
+	// NumReplicas is the number of replicas of the index to create in addition to the index itself.
+	// UNCOMMITTED: This API may change in the future.
+	NumReplicas uint32
+
+	// Nodes are the index nodes, as host:port, which the index and its replicas are placed on.
+	// UNCOMMITTED: This API may change in the future.
+	Nodes []string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // CreatePrimaryIndex creates a primary index.  An empty customName uses the default naming.
@@ -229,7 +353,7 @@ func (qm *QueryIndexManager) CreatePrimaryIndex(bucketName string, opts *CreateP
 		opts = &CreatePrimaryQueryIndexOptions{}
 	}
 
-	span := qm.tracer.StartSpan("CreatePrimaryIndex", nil).
+	span := qm.tracer.StartSpan("CreatePrimaryIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -241,6 +365,10 @@ func (qm *QueryIndexManager) CreatePrimaryIndex(bucketName string, opts *CreateP
 		createQueryIndexOptions{
 			IgnoreIfExists: opts.IgnoreIfExists,
 			Deferred:       opts.Deferred,
+			ScopeName:      opts.ScopeName,
+			CollectionName: opts.CollectionName,
+			NumReplicas:    opts.NumReplicas,
+			Nodes:          opts.Nodes,
 			Timeout:        opts.Timeout,
 			RetryStrategy:  opts.RetryStrategy,
 		})
@@ -249,28 +377,38 @@ func (qm *QueryIndexManager) CreatePrimaryIndex(bucketName string, opts *CreateP
 type dropQueryIndexOptions struct {
 	IgnoreIfNotExists bool
 
+	ScopeName      string
+	CollectionName string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 }
 
 func (qm *QueryIndexManager) dropIndex(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	bucketName, indexName string,
 	opts dropQueryIndexOptions,
 ) error {
+	keyspace, err := queryIndexKeyspace(bucketName, opts.ScopeName, opts.CollectionName)
+	if err != nil {
+		return err
+	}
+
 	var qs string
 
 	if indexName == "" {
-		qs += "DROP PRIMARY INDEX ON `" + bucketName + "`"
-	} else {
+		qs += "DROP PRIMARY INDEX ON " + keyspace
+	} else if opts.CollectionName == "" {
 		qs += "DROP INDEX `" + bucketName + "`.`" + indexName + "`"
+	} else {
+		qs += "DROP INDEX `" + indexName + "` ON " + keyspace
 	}
 
-	_, err := qm.doQuery(qs, &QueryOptions{
+	_, err = qm.doQuery(qs, &QueryOptions{
 		Timeout:       opts.Timeout,
 		RetryStrategy: opts.RetryStrategy,
 		Adhoc:         true,
-		parentSpan:    tracectx,
+		ParentSpan:    tracectx,
 	})
 	if err == nil {
 		return nil
@@ -287,8 +425,14 @@ func (qm *QueryIndexManager) dropIndex(
 type DropQueryIndexOptions struct {
 	IgnoreIfNotExists bool
 
+	// ScopeName and CollectionName drop an index of a collection rather than of the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropIndex drops a specific index by name.
@@ -303,7 +447,7 @@ func (qm *QueryIndexManager) DropIndex(bucketName, indexName string, opts *DropQ
 		}
 	}
 
-	span := qm.tracer.StartSpan("DropIndex", nil).
+	span := qm.tracer.StartSpan("DropIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -313,6 +457,8 @@ func (qm *QueryIndexManager) DropIndex(bucketName, indexName string, opts *DropQ
 		indexName,
 		dropQueryIndexOptions{
 			IgnoreIfNotExists: opts.IgnoreIfNotExists,
+			ScopeName:         opts.ScopeName,
+			CollectionName:    opts.CollectionName,
 			Timeout:           opts.Timeout,
 			RetryStrategy:     opts.RetryStrategy,
 		})
@@ -323,8 +469,14 @@ type DropPrimaryQueryIndexOptions struct {
 	IgnoreIfNotExists bool
 	CustomName        string
 
+	// ScopeName and CollectionName drop an index of a collection rather than of the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropPrimaryIndex drops the primary index.  Pass an empty customName for unnamed primary indexes.
@@ -333,7 +485,7 @@ func (qm *QueryIndexManager) DropPrimaryIndex(bucketName string, opts *DropPrima
 		opts = &DropPrimaryQueryIndexOptions{}
 	}
 
-	span := qm.tracer.StartSpan("DropPrimaryIndex", nil).
+	span := qm.tracer.StartSpan("DropPrimaryIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -343,6 +495,8 @@ func (qm *QueryIndexManager) DropPrimaryIndex(bucketName string, opts *DropPrima
 		opts.CustomName,
 		dropQueryIndexOptions{
 			IgnoreIfNotExists: opts.IgnoreIfNotExists,
+			ScopeName:         opts.ScopeName,
+			CollectionName:    opts.CollectionName,
 			Timeout:           opts.Timeout,
 			RetryStrategy:     opts.RetryStrategy,
 		})
@@ -350,8 +504,15 @@ func (qm *QueryIndexManager) DropPrimaryIndex(bucketName string, opts *DropPrima
 
 // GetAllQueryIndexesOptions is the set of options available to the query indexes GetAllIndexes operation.
 type GetAllQueryIndexesOptions struct {
+	// ScopeName and CollectionName return the indexes of a collection, or of every collection in a
+	// scope when only ScopeName is specified, rather than those of the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllIndexes returns a list of all currently registered indexes.
@@ -360,7 +521,7 @@ func (qm *QueryIndexManager) GetAllIndexes(bucketName string, opts *GetAllQueryI
 		opts = &GetAllQueryIndexesOptions{}
 	}
 
-	span := qm.tracer.StartSpan("GetAllIndexes", nil).
+	span := qm.tracer.StartSpan("GetAllIndexes", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -368,18 +529,35 @@ func (qm *QueryIndexManager) GetAllIndexes(bucketName string, opts *GetAllQueryI
 }
 
 func (qm *QueryIndexManager) getAllIndexes(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	bucketName string,
 	opts *GetAllQueryIndexesOptions,
 ) ([]QueryIndex, error) {
-	q := "SELECT `indexes`.* FROM system:indexes WHERE keyspace_id=? AND `using`=\"gsi\""
+	var where string
+	var params []interface{}
+	if opts.ScopeName == "" && opts.CollectionName == "" {
+		where = "keyspace_id=? AND bucket_id IS MISSING"
+		params = []interface{}{bucketName}
+	} else if opts.CollectionName == "" {
+		where = "bucket_id=? AND scope_id=?"
+		params = []interface{}{bucketName, opts.ScopeName}
+	} else {
+		scopeName := opts.ScopeName
+		if scopeName == "" {
+			scopeName = "_default"
+		}
+		where = "bucket_id=? AND scope_id=? AND keyspace_id=?"
+		params = []interface{}{bucketName, scopeName, opts.CollectionName}
+	}
+
+	q := "SELECT `indexes`.* FROM system:indexes WHERE " + where + " AND `using`=\"gsi\""
 	rows, err := qm.doQuery(q, &QueryOptions{
-		PositionalParameters: []interface{}{bucketName},
+		PositionalParameters: params,
 		Readonly:             true,
 		Timeout:              opts.Timeout,
 		RetryStrategy:        opts.RetryStrategy,
 		Adhoc:                true,
-		parentSpan:           tracectx,
+		ParentSpan:           tracectx,
 	})
 	if err != nil {
 		return nil, err
@@ -407,8 +585,14 @@ func (qm *QueryIndexManager) getAllIndexes(
 
 // BuildDeferredQueryIndexOptions is the set of options available to the query indexes BuildDeferredIndexes operation.
 type BuildDeferredQueryIndexOptions struct {
+	// ScopeName and CollectionName build the deferred indexes of a collection rather than of the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // BuildDeferredIndexes builds all indexes which are currently in deferred state.
@@ -417,7 +601,12 @@ func (qm *QueryIndexManager) BuildDeferredIndexes(bucketName string, opts *Build
 		opts = &BuildDeferredQueryIndexOptions{}
 	}
 
-	span := qm.tracer.StartSpan("BuildDeferredIndexes", nil).
+	keyspace, err := queryIndexKeyspace(bucketName, opts.ScopeName, opts.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	span := qm.tracer.StartSpan("BuildDeferredIndexes", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -425,8 +614,10 @@ func (qm *QueryIndexManager) BuildDeferredIndexes(bucketName string, opts *Build
 		span.Context(),
 		bucketName,
 		&GetAllQueryIndexesOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
+			ScopeName:      opts.ScopeName,
+			CollectionName: opts.CollectionName,
+			Timeout:        opts.Timeout,
+			RetryStrategy:  opts.RetryStrategy,
 		})
 	if err != nil {
 		return nil, err
@@ -445,27 +636,38 @@ func (qm *QueryIndexManager) BuildDeferredIndexes(bucketName string, opts *Build
 		return nil, nil
 	}
 
+	err = qm.buildIndexes(span.Context(), keyspace, deferredList, opts.Timeout, opts.RetryStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	return deferredList, nil
+}
+
+func (qm *QueryIndexManager) buildIndexes(
+	tracectx RequestSpanContext,
+	keyspace string,
+	indexNames []string,
+	timeout time.Duration,
+	retryStrategy RetryStrategy,
+) error {
 	var qs string
-	qs += "BUILD INDEX ON `" + bucketName + "`("
-	for i := 0; i < len(deferredList); i++ {
+	qs += "BUILD INDEX ON " + keyspace + "("
+	for i := 0; i < len(indexNames); i++ {
 		if i > 0 {
 			qs += ", "
 		}
-		qs += "`" + deferredList[i] + "`"
+		qs += "`" + indexNames[i] + "`"
 	}
 	qs += ")"
 
-	_, err = qm.doQuery(qs, &QueryOptions{
-		Timeout:       opts.Timeout,
-		RetryStrategy: opts.RetryStrategy,
+	_, err := qm.doQuery(qs, &QueryOptions{
+		Timeout:       timeout,
+		RetryStrategy: retryStrategy,
 		Adhoc:         true,
-		parentSpan:    span,
+		ParentSpan:    tracectx,
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	return deferredList, nil
+	return err
 }
 
 func checkIndexesActive(indexes []QueryIndex, checkList []string) (bool, error) {
@@ -497,7 +699,13 @@ func checkIndexesActive(indexes []QueryIndex, checkList []string) (bool, error)
 type WatchQueryIndexOptions struct {
 	WatchPrimary bool
 
+	// ScopeName and CollectionName watch the indexes of a collection rather than of the bucket.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // WatchIndexes waits for a set of indexes to come online.
@@ -506,7 +714,11 @@ func (qm *QueryIndexManager) WatchIndexes(bucketName string, watchList []string,
 		opts = &WatchQueryIndexOptions{}
 	}
 
-	span := qm.tracer.StartSpan("WatchIndexes", nil).
+	if _, err := queryIndexKeyspace(bucketName, opts.ScopeName, opts.CollectionName); err != nil {
+		return err
+	}
+
+	span := qm.tracer.StartSpan("WatchIndexes", opts.ParentSpan).
 		SetTag("couchbase.service", "query")
 	defer span.Finish()
 
@@ -526,8 +738,10 @@ func (qm *QueryIndexManager) WatchIndexes(bucketName string, watchList []string,
 			span.Context(),
 			bucketName,
 			&GetAllQueryIndexesOptions{
-				Timeout:       time.Until(deadline),
-				RetryStrategy: opts.RetryStrategy,
+				ScopeName:      opts.ScopeName,
+				CollectionName: opts.CollectionName,
+				Timeout:        time.Until(deadline),
+				RetryStrategy:  opts.RetryStrategy,
 			})
 		if err != nil {
 			return err
@@ -106,3 +106,103 @@ func (suite *IntegrationTestSuite) TestQueryIndexesCrud() {
 		suite.T().Fatalf("Expected index not found error but was %s", err)
 	}
 }
+
+type mockQueryIndexProvider struct {
+	statements []string
+	params     [][]interface{}
+	rows       [][]byte
+}
+
+func (p *mockQueryIndexProvider) Query(statement string, opts *QueryOptions) (*QueryResult, error) {
+	p.statements = append(p.statements, statement)
+	p.params = append(p.params, opts.PositionalParameters)
+	return newQueryResult(&mockQueryRowReaderRows{Rows: p.rows}), nil
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesCollectionStatements() {
+	provider := &mockQueryIndexProvider{}
+	mgr := &QueryIndexManager{
+		provider: provider,
+		tracer:   &noopTracer{},
+	}
+
+	err := mgr.CreateIndex("travel-sample", "idx", []string{"country", "city"}, &CreateQueryIndexOptions{
+		ScopeName:      "inventory",
+		CollectionName: "hotel",
+		Deferred:       true,
+		NumReplicas:    1,
+		Nodes:          []string{"10.0.0.1:8091", "10.0.0.2:8091"},
+		PartitionBy:    []string{"META().id"},
+		Condition:      "free_parking = true",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreatePrimaryIndex("travel-sample", &CreatePrimaryQueryIndexOptions{CollectionName: "hotel"})
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropIndex("travel-sample", "idx", &DropQueryIndexOptions{ScopeName: "inventory", CollectionName: "hotel"})
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropIndex("travel-sample", "idx", nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropPrimaryIndex("travel-sample", &DropPrimaryQueryIndexOptions{ScopeName: "inventory", CollectionName: "hotel"})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{
+		"CREATE INDEX `idx` ON `travel-sample`.`inventory`.`hotel` (`country`, `city`) PARTITION BY HASH(META().id) " +
+			"WHERE free_parking = true WITH {\"defer_build\":true,\"nodes\":[\"10.0.0.1:8091\",\"10.0.0.2:8091\"],\"num_replica\":1}",
+		"CREATE PRIMARY INDEX ON `travel-sample`.`_default`.`hotel`",
+		"DROP INDEX `idx` ON `travel-sample`.`inventory`.`hotel`",
+		"DROP INDEX `travel-sample`.`idx`",
+		"DROP PRIMARY INDEX ON `travel-sample`.`inventory`.`hotel`",
+	}, provider.statements)
+
+	err = mgr.CreateIndex("travel-sample", "idx", []string{"country"}, &CreateQueryIndexOptions{ScopeName: "inventory"})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesGetAllIndexesCollection() {
+	provider := &mockQueryIndexProvider{
+		rows: [][]byte{
+			[]byte(`{"name":"idx","is_primary":false,"using":"gsi","state":"deferred","keyspace_id":"hotel",
+				"bucket_id":"travel-sample","scope_id":"inventory","namespace_id":"default","index_key":["country"],
+				"partition":"HASH(META().id)","metadata":{"num_replica":1}}`),
+		},
+	}
+	mgr := &QueryIndexManager{
+		provider: provider,
+		tracer:   &noopTracer{},
+	}
+
+	indexes, err := mgr.GetAllIndexes("travel-sample", &GetAllQueryIndexesOptions{ScopeName: "inventory"})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(indexes, 1)
+	suite.Assert().Equal(QueryIndex{
+		Name:           "idx",
+		Type:           QueryIndexTypeGsi,
+		State:          "deferred",
+		Keyspace:       "hotel",
+		Namespace:      "default",
+		IndexKey:       []string{"country"},
+		BucketName:     "travel-sample",
+		ScopeName:      "inventory",
+		CollectionName: "hotel",
+		Partition:      "HASH(META().id)",
+		NumReplicas:    1,
+	}, indexes[0])
+
+	names, err := mgr.BuildDeferredIndexes("travel-sample", &BuildDeferredQueryIndexOptions{
+		ScopeName:      "inventory",
+		CollectionName: "hotel",
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]string{"idx"}, names)
+
+	suite.Require().Len(provider.statements, 3)
+	suite.Assert().Equal("SELECT `indexes`.* FROM system:indexes WHERE bucket_id=? AND scope_id=? AND `using`=\"gsi\"",
+		provider.statements[0])
+	suite.Assert().Equal([]interface{}{"travel-sample", "inventory"}, provider.params[0])
+	suite.Assert().Equal([]interface{}{"travel-sample", "inventory", "hotel"}, provider.params[1])
+	suite.Assert().Equal("BUILD INDEX ON `travel-sample`.`inventory`.`hotel`(`idx`)", provider.statements[2])
+}
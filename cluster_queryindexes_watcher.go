@@ -0,0 +1,279 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// QueryIndexWatchEvent describes a change in the state or build progress of a watched index.
+// UNCOMMITTED: This API may change in the future.
+type QueryIndexWatchEvent struct {
+	Name string
+
+	// State is the state of the index as reported by system:indexes, e.g. deferred, building or online.
+	// PreviousState is empty for the first event of each index.
+	State         string
+	PreviousState string
+
+	// Progress is the build progress of the index as a percentage, or -1 if the index service did not
+	// report it.
+	Progress int
+}
+
+// WatchQueryIndexProgressOptions is the set of options available to the query indexes
+// WatchIndexesWithProgress operation.
+// UNCOMMITTED: This API may change in the future.
+type WatchQueryIndexProgressOptions struct {
+	WatchPrimary bool
+
+	// ScopeName and CollectionName watch the indexes of a collection rather than of the bucket.
+	ScopeName      string
+	CollectionName string
+
+	// BuildDeferred builds any of the watched indexes which are deferred before waiting for them.
+	BuildDeferred bool
+
+	// OnEvent is called, from the watching goroutine, whenever the state or build progress of a watched
+	// index changes.
+	OnEvent func(QueryIndexWatchEvent)
+
+	// PollInterval is how often the indexes are polled.  Defaults to 1 second.
+	PollInterval time.Duration
+
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+type jsonQueryIndexStatus struct {
+	Indexes []struct {
+		Bucket     string `json:"bucket"`
+		Scope      string `json:"scope"`
+		Collection string `json:"collection"`
+		Index      string `json:"index"`
+		Progress   int    `json:"progress"`
+	} `json:"indexes"`
+}
+
+// WatchIndexesWithProgress waits for a set of indexes to come online, reporting each change in their
+// state and build progress to OnEvent.  It returns ErrUnambiguousTimeout once the deadline of ctx has
+// passed, or ErrRequestCanceled if ctx is canceled, which is checked between each poll.
+// UNCOMMITTED: This API may change in the future.
+func (qm *QueryIndexManager) WatchIndexesWithProgress(ctx context.Context, bucketName string, watchList []string,
+	opts *WatchQueryIndexProgressOptions) error {
+	if opts == nil {
+		opts = &WatchQueryIndexProgressOptions{}
+	}
+
+	keyspace, err := queryIndexKeyspace(bucketName, opts.ScopeName, opts.CollectionName)
+	if err != nil {
+		return err
+	}
+
+	span := qm.tracer.StartSpan("WatchIndexesWithProgress", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	if opts.WatchPrimary {
+		watchList = append(watchList, "#primary")
+	}
+
+	pollInterval := opts.PollInterval
+	if pollInterval == 0 {
+		pollInterval = 1 * time.Second
+	}
+
+	events := make(map[string]QueryIndexWatchEvent, len(watchList))
+	built := !opts.BuildDeferred
+	for {
+		if err := queryIndexWatchContextError(ctx); err != nil {
+			return err
+		}
+
+		indexes, err := qm.getAllIndexes(
+			span.Context(),
+			bucketName,
+			&GetAllQueryIndexesOptions{
+				ScopeName:      opts.ScopeName,
+				CollectionName: opts.CollectionName,
+				Timeout:        qm.watchRequestTimeout(ctx),
+				RetryStrategy:  opts.RetryStrategy,
+			})
+		if err != nil {
+			return err
+		}
+
+		states := make(map[string]string, len(indexes))
+		for _, index := range indexes {
+			states[index.Name] = index.State
+		}
+
+		var deferredList []string
+		allOnline := true
+		for _, name := range watchList {
+			state, ok := states[name]
+			if !ok {
+				return ErrIndexNotFound
+			}
+
+			if state == "deferred" || state == "pending" {
+				deferredList = append(deferredList, name)
+			}
+			if state != "online" {
+				allOnline = false
+			}
+		}
+
+		if !built && len(deferredList) > 0 {
+			err := qm.buildIndexes(span.Context(), keyspace, deferredList, qm.watchRequestTimeout(ctx), opts.RetryStrategy)
+			if err != nil {
+				return err
+			}
+		}
+		built = true
+
+		var progress map[string]int
+		if !allOnline {
+			progress = qm.getIndexProgress(span.Context(), bucketName, opts.ScopeName, opts.CollectionName,
+				qm.watchRequestTimeout(ctx), opts.RetryStrategy)
+		}
+
+		for _, name := range watchList {
+			event := QueryIndexWatchEvent{
+				Name:     name,
+				State:    states[name],
+				Progress: -1,
+			}
+			if event.State == "online" {
+				event.Progress = 100
+			} else if p, ok := progress[name]; ok {
+				event.Progress = p
+			}
+
+			last, ok := events[name]
+			if ok && last.State == event.State && last.Progress == event.Progress {
+				continue
+			}
+			event.PreviousState = last.State
+			events[name] = event
+
+			if opts.OnEvent != nil {
+				opts.OnEvent(event)
+			}
+		}
+
+		if allOnline {
+			return nil
+		}
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return queryIndexWatchContextError(ctx)
+		case <-timer.C:
+		}
+	}
+}
+
+// watchRequestTimeout returns the timeout for each request made whilst watching, which is the management
+// timeout unless the deadline of ctx is sooner.
+func (qm *QueryIndexManager) watchRequestTimeout(ctx context.Context) time.Duration {
+	timeout := qm.globalTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); timeout == 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	return timeout
+}
+
+// getIndexProgress returns the build progress of the indexes of a keyspace as reported by the index
+// service.  The progress is only informational, so failures are logged rather than returned.
+func (qm *QueryIndexManager) getIndexProgress(
+	tracectx RequestSpanContext,
+	bucketName, scopeName, collectionName string,
+	timeout time.Duration,
+	retryStrategy RetryStrategy,
+) map[string]int {
+	if qm.mgmtProvider == nil {
+		return nil
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/indexStatus",
+		IsIdempotent:  true,
+		RetryStrategy: retryStrategy,
+		Timeout:       timeout,
+		parentSpan:    tracectx,
+	}
+	resp, err := qm.mgmtProvider.executeMgmtRequest(req)
+	if err != nil {
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to get index status: %s", err)
+		return nil
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to get index status, status code: %d", resp.StatusCode)
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to read index status: %s", err)
+		return nil
+	}
+
+	var status jsonQueryIndexStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to parse index status: %s", err)
+		return nil
+	}
+
+	// Servers without collections report neither the scope nor the collection of an index, and report
+	// indexes of the bucket itself as belonging to the default collection once they do.
+	if scopeName == "" {
+		scopeName = "_default"
+	}
+	if collectionName == "" {
+		collectionName = "_default"
+	}
+
+	progress := make(map[string]int)
+	for _, index := range status.Indexes {
+		indexScope, indexCollection := index.Scope, index.Collection
+		if indexScope == "" {
+			indexScope = "_default"
+		}
+		if indexCollection == "" {
+			indexCollection = "_default"
+		}
+		if index.Bucket != bucketName || indexScope != scopeName || indexCollection != collectionName {
+			continue
+		}
+
+		// Replicas are reported separately, e.g. as "name (replica 1)", so we only ever match the index
+		// itself.  Partitioned indexes may be reported once per node, in which case the least progress is used.
+		if p, ok := progress[index.Index]; !ok || index.Progress < p {
+			progress[index.Index] = index.Progress
+		}
+	}
+
+	return progress
+}
+
+func queryIndexWatchContextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return ErrUnambiguousTimeout
+	default:
+		return ErrRequestCanceled
+	}
+}
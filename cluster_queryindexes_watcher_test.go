@@ -0,0 +1,131 @@
+package gocb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type mockQueryIndexWatchProvider struct {
+	pages      [][]string
+	polls      int
+	statements []string
+}
+
+func (p *mockQueryIndexWatchProvider) Query(statement string, opts *QueryOptions) (*QueryResult, error) {
+	p.statements = append(p.statements, statement)
+	if !strings.HasPrefix(statement, "SELECT") {
+		return newQueryResult(&mockQueryRowReaderRows{}), nil
+	}
+
+	page := p.pages[len(p.pages)-1]
+	if p.polls < len(p.pages) {
+		page = p.pages[p.polls]
+	}
+	p.polls++
+
+	rows := make([][]byte, len(page))
+	for i, row := range page {
+		rows[i] = []byte(row)
+	}
+	return newQueryResult(&mockQueryRowReaderRows{Rows: rows}), nil
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesWatchWithProgress() {
+	provider := &mockQueryIndexWatchProvider{
+		pages: [][]string{
+			{`{"name":"idx","state":"deferred","keyspace_id":"hotel","bucket_id":"travel-sample","scope_id":"inventory"}`},
+			{`{"name":"idx","state":"building","keyspace_id":"hotel","bucket_id":"travel-sample","scope_id":"inventory"}`},
+			{`{"name":"idx","state":"building","keyspace_id":"hotel","bucket_id":"travel-sample","scope_id":"inventory"}`},
+			{`{"name":"idx","state":"online","keyspace_id":"hotel","bucket_id":"travel-sample","scope_id":"inventory"}`},
+		},
+	}
+
+	statuses := []string{`0`, `40`, `40`}
+	var statusReqs []mgmtRequest
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", mock.AnythingOfType("mgmtRequest")).
+		Return(func(req mgmtRequest) *mgmtResponse {
+			progress := statuses[len(statusReqs)]
+			statusReqs = append(statusReqs, req)
+
+			body := `{"indexes":[
+				{"bucket":"travel-sample","scope":"inventory","collection":"hotel","index":"idx","progress":` + progress + `},
+				{"bucket":"travel-sample","scope":"inventory","collection":"hotel","index":"idx (replica 1)","progress":5},
+				{"bucket":"travel-sample","scope":"_default","collection":"_default","index":"idx","progress":100}
+			]}`
+			return &mgmtResponse{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	mgr := &QueryIndexManager{
+		provider:      provider,
+		mgmtProvider:  mgmtProvider,
+		globalTimeout: 5 * time.Second,
+		tracer:        &noopTracer{},
+	}
+
+	var events []QueryIndexWatchEvent
+	err := mgr.WatchIndexesWithProgress(context.Background(), "travel-sample", []string{"idx"}, &WatchQueryIndexProgressOptions{
+		ScopeName:      "inventory",
+		CollectionName: "hotel",
+		BuildDeferred:  true,
+		PollInterval:   time.Millisecond,
+		OnEvent: func(event QueryIndexWatchEvent) {
+			events = append(events, event)
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]QueryIndexWatchEvent{
+		{Name: "idx", State: "deferred", Progress: 0},
+		{Name: "idx", State: "building", PreviousState: "deferred", Progress: 40},
+		{Name: "idx", State: "online", PreviousState: "building", Progress: 100},
+	}, events)
+
+	suite.Assert().Equal("BUILD INDEX ON `travel-sample`.`inventory`.`hotel`(`idx`)", provider.statements[1])
+	suite.Assert().Len(provider.statements, 5)
+	suite.Require().Len(statusReqs, 3)
+	suite.Assert().Equal("/indexStatus", statusReqs[0].Path)
+	suite.Assert().Equal(ServiceTypeManagement, statusReqs[0].Service)
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesWatchWithProgressCanceled() {
+	provider := &mockQueryIndexWatchProvider{
+		pages: [][]string{{`{"name":"idx","state":"building","keyspace_id":"default"}`}},
+	}
+	mgr := &QueryIndexManager{
+		provider: provider,
+		tracer:   &noopTracer{},
+	}
+
+	var events []QueryIndexWatchEvent
+	ctx, cancel := context.WithCancel(context.Background())
+	err := mgr.WatchIndexesWithProgress(ctx, "default", []string{"idx"}, &WatchQueryIndexProgressOptions{
+		PollInterval: time.Millisecond,
+		OnEvent: func(event QueryIndexWatchEvent) {
+			events = append(events, event)
+			cancel()
+		},
+	})
+	suite.Assert().True(errors.Is(err, ErrRequestCanceled))
+	suite.Assert().Equal([]QueryIndexWatchEvent{{Name: "idx", State: "building", Progress: -1}}, events)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = mgr.WatchIndexesWithProgress(ctx, "default", []string{"idx"}, &WatchQueryIndexProgressOptions{
+		PollInterval: time.Millisecond,
+	})
+	suite.Assert().True(errors.Is(err, ErrUnambiguousTimeout))
+
+	err = mgr.WatchIndexesWithProgress(context.Background(), "default", []string{"missing"}, nil)
+	suite.Assert().True(errors.Is(err, ErrIndexNotFound))
+}
@@ -0,0 +1,338 @@
+package gocb
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryRequestsManager provides methods for listing and cancelling the requests executed by the query
+// service.
+// UNCOMMITTED: This API may change in the future.
+type QueryRequestsManager struct {
+	provider queryRequestsQueryProvider
+
+	globalTimeout time.Duration
+	tracer        RequestTracer
+}
+
+type queryRequestsQueryProvider interface {
+	Query(statement string, opts *QueryOptions) (*QueryResult, error)
+}
+
+// QueryRequests returns a QueryRequestsManager for listing and cancelling query requests.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) QueryRequests() *QueryRequestsManager {
+	return &QueryRequestsManager{
+		provider:      c,
+		globalTimeout: c.timeoutsConfig.ManagementTimeout,
+		tracer:        c.tracer,
+	}
+}
+
+// QueryRequest is a request which is being, or has been, executed by the query service.
+// UNCOMMITTED: This API may change in the future.
+type QueryRequest struct {
+	RequestID       string
+	ClientContextID string
+	Statement       string
+	PreparedName    string
+	QueryContext    string
+
+	// State is the state of the request, e.g. running, completed, errors, cancelled or timeout.
+	State string
+
+	// Users is the comma separated list of users which the request was executed as.
+	Users         string
+	Node          string
+	RemoteAddress string
+	UserAgent     string
+
+	RequestTime   time.Time
+	ElapsedTime   time.Duration
+	ExecutionTime time.Duration
+
+	// ResultCount, ResultSize and ErrorCount are only set for completed requests.
+	ResultCount uint64
+	ResultSize  uint64
+	ErrorCount  uint64
+}
+
+type jsonQueryRequest struct {
+	RequestID       string `json:"requestId"`
+	ClientContextID string `json:"clientContextID,omitempty"`
+	Statement       string `json:"statement,omitempty"`
+	PreparedName    string `json:"preparedName,omitempty"`
+	QueryContext    string `json:"queryContext,omitempty"`
+	State           string `json:"state,omitempty"`
+	Users           string `json:"users,omitempty"`
+	Node            string `json:"node,omitempty"`
+	RemoteAddress   string `json:"remoteAddr,omitempty"`
+	UserAgent       string `json:"userAgent,omitempty"`
+	RequestTime     string `json:"requestTime,omitempty"`
+	ElapsedTime     string `json:"elapsedTime,omitempty"`
+	ExecutionTime   string `json:"serviceTime,omitempty"`
+	ResultCount     uint64 `json:"resultCount,omitempty"`
+	ResultSize      uint64 `json:"resultSize,omitempty"`
+	ErrorCount      uint64 `json:"errorCount,omitempty"`
+}
+
+// queryRequestTimeLayouts are the formats used by the query service for request times.
+var queryRequestTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+}
+
+func (request *QueryRequest) fromData(data jsonQueryRequest) error {
+	request.RequestID = data.RequestID
+	request.ClientContextID = data.ClientContextID
+	request.Statement = data.Statement
+	request.PreparedName = data.PreparedName
+	request.QueryContext = data.QueryContext
+	request.State = data.State
+	request.Users = data.Users
+	request.Node = data.Node
+	request.RemoteAddress = data.RemoteAddress
+	request.UserAgent = data.UserAgent
+	request.ElapsedTime = parseQueryDuration(data.ElapsedTime)
+	request.ExecutionTime = parseQueryDuration(data.ExecutionTime)
+	request.ResultCount = data.ResultCount
+	request.ResultSize = data.ResultSize
+	request.ErrorCount = data.ErrorCount
+
+	if data.RequestTime != "" {
+		for _, layout := range queryRequestTimeLayouts {
+			requestTime, err := time.Parse(layout, data.RequestTime)
+			if err == nil {
+				request.RequestTime = requestTime
+				break
+			}
+		}
+		if request.RequestTime.IsZero() {
+			logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to parse query request time: %s", data.RequestTime)
+		}
+	}
+
+	return nil
+}
+
+func (qm *QueryRequestsManager) doQuery(q string, opts *QueryOptions) ([]QueryRequest, *QueryMetaData, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = qm.globalTimeout
+	}
+
+	result, err := qm.provider.Query(q, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []jsonQueryRequest
+	if err := result.All(&rows, &ResultAllOptions{MaxRows: ^uint32(0)}); err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := result.MetaData()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requests := make([]QueryRequest, len(rows))
+	for i, row := range rows {
+		if err := requests[i].fromData(row); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return requests, meta, nil
+}
+
+// GetActiveQueryRequestsOptions is the set of options available to the query requests GetActiveRequests
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type GetActiveQueryRequestsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// GetActiveRequests returns the requests which the query service is currently executing, excluding the
+// request made to list them.
+func (qm *QueryRequestsManager) GetActiveRequests(opts *GetActiveQueryRequestsOptions) ([]QueryRequest, error) {
+	if opts == nil {
+		opts = &GetActiveQueryRequestsOptions{}
+	}
+
+	span := qm.tracer.StartSpan("GetActiveRequests", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	contextID := uuid.New().String()
+	requests, _, err := qm.doQuery("SELECT r.* FROM system:active_requests AS r WHERE r.clientContextID IS MISSING OR "+
+		"r.clientContextID != ?", &QueryOptions{
+		ClientContextID:      contextID,
+		PositionalParameters: []interface{}{contextID},
+		Readonly:             true,
+		Timeout:              opts.Timeout,
+		RetryStrategy:        opts.RetryStrategy,
+		Adhoc:                true,
+		ParentSpan:           span.Context(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// GetCompletedQueryRequestsOptions is the set of options available to the query requests
+// GetCompletedRequests operation.  Requests must match every filter which is set.
+// UNCOMMITTED: This API may change in the future.
+type GetCompletedQueryRequestsOptions struct {
+	// Since limits the results to requests which started at or after the given time.
+	Since time.Time
+
+	// MinElapsedTime limits the results to requests which took at least the given time.
+	MinElapsedTime time.Duration
+
+	// State limits the results to requests which finished in the given state, e.g. errors or cancelled.
+	State string
+
+	// StatementContains limits the results to requests whose statement contains the given text.
+	StatementContains string
+
+	ClientContextID string
+	User            string
+
+	// Limit is the maximum number of requests to return, the most recent first.  Defaults to no limit.
+	Limit uint32
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// GetCompletedRequests returns the requests which the query service has recorded as completed, most
+// recent first.  The query service only records requests which meet its completed-threshold setting.
+func (qm *QueryRequestsManager) GetCompletedRequests(opts *GetCompletedQueryRequestsOptions) ([]QueryRequest, error) {
+	if opts == nil {
+		opts = &GetCompletedQueryRequestsOptions{}
+	}
+
+	span := qm.tracer.StartSpan("GetCompletedRequests", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	var where []string
+	var args []interface{}
+	if !opts.Since.IsZero() {
+		where = append(where, "STR_TO_MILLIS(r.requestTime) >= ?")
+		args = append(args, opts.Since.UnixNano()/int64(time.Millisecond))
+	}
+	if opts.MinElapsedTime > 0 {
+		where = append(where, "STR_TO_DURATION(r.elapsedTime) >= ?")
+		args = append(args, opts.MinElapsedTime.Nanoseconds())
+	}
+	if opts.State != "" {
+		where = append(where, "r.state = ?")
+		args = append(args, opts.State)
+	}
+	if opts.StatementContains != "" {
+		where = append(where, "CONTAINS(r.statement, ?)")
+		args = append(args, opts.StatementContains)
+	}
+	if opts.ClientContextID != "" {
+		where = append(where, "r.clientContextID = ?")
+		args = append(args, opts.ClientContextID)
+	}
+	if opts.User != "" {
+		where = append(where, "? IN SPLIT(r.users, \",\")")
+		args = append(args, opts.User)
+	}
+
+	q := "SELECT r.* FROM system:completed_requests AS r"
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY STR_TO_MILLIS(r.requestTime) DESC"
+	if opts.Limit > 0 {
+		q += " LIMIT " + strconv.FormatUint(uint64(opts.Limit), 10)
+	}
+
+	requests, _, err := qm.doQuery(q, &QueryOptions{
+		PositionalParameters: args,
+		Readonly:             true,
+		Timeout:              opts.Timeout,
+		RetryStrategy:        opts.RetryStrategy,
+		Adhoc:                true,
+		ParentSpan:           span.Context(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// CancelQueryRequestOptions is the set of options available to the query requests CancelRequest and
+// CancelRequestByClientContextID operations.
+// UNCOMMITTED: This API may change in the future.
+type CancelQueryRequestOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
+}
+
+// CancelRequest cancels the running request with the given request ID.  If no such request is running,
+// for example because it has already completed, then ErrQueryRequestNotFound is returned.
+func (qm *QueryRequestsManager) CancelRequest(requestID string, opts *CancelQueryRequestOptions) error {
+	if opts == nil {
+		opts = &CancelQueryRequestOptions{}
+	}
+
+	span := qm.tracer.StartSpan("CancelRequest", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	return qm.cancelRequests(span.Context(), "requestId", requestID, opts)
+}
+
+// CancelRequestByClientContextID cancels the running requests which were sent with the given
+// ClientContextID.  If no such request is running then ErrQueryRequestNotFound is returned.
+func (qm *QueryRequestsManager) CancelRequestByClientContextID(clientContextID string, opts *CancelQueryRequestOptions) error {
+	if opts == nil {
+		opts = &CancelQueryRequestOptions{}
+	}
+
+	span := qm.tracer.StartSpan("CancelRequestByClientContextID", opts.ParentSpan).
+		SetTag("couchbase.service", "query")
+	defer span.Finish()
+
+	return qm.cancelRequests(span.Context(), "clientContextID", clientContextID, opts)
+}
+
+func (qm *QueryRequestsManager) cancelRequests(tracectx RequestSpanContext, field, value string,
+	opts *CancelQueryRequestOptions) error {
+	if value == "" {
+		return makeInvalidArgumentsError("a " + field + " must be specified")
+	}
+
+	_, meta, err := qm.doQuery("DELETE FROM system:active_requests WHERE "+field+" = ?", &QueryOptions{
+		PositionalParameters: []interface{}{value},
+		Metrics:              true,
+		Timeout:              opts.Timeout,
+		RetryStrategy:        opts.RetryStrategy,
+		Adhoc:                true,
+		ParentSpan:           tracectx,
+	})
+	if err != nil {
+		return err
+	}
+
+	if meta.Metrics.MutationCount == 0 {
+		return ErrQueryRequestNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package gocb
+
+import (
+	"errors"
+	"time"
+)
+
+type mockQueryRequestsProvider struct {
+	statement string
+	opts      *QueryOptions
+	reader    queryRowReader
+}
+
+func (p *mockQueryRequestsProvider) Query(statement string, opts *QueryOptions) (*QueryResult, error) {
+	p.statement = statement
+	p.opts = opts
+	return newQueryResult(p.reader), nil
+}
+
+func (suite *UnitTestSuite) TestQueryRequestsGetActiveRequests() {
+	provider := &mockQueryRequestsProvider{
+		reader: &mockQueryRowReaderRows{
+			Rows: [][]byte{[]byte(`{
+				"requestId": "b8c9f4a1", "clientContextID": "ctx-1", "statement": "SELECT * FROM default",
+				"state": "running", "users": "admin", "node": "10.112.0.2:8091", "remoteAddr": "10.112.0.9:51234",
+				"requestTime": "2020-09-14 10:31:02.589437 +0000 UTC", "elapsedTime": "2.5s", "serviceTime": "2.4s"
+			}`)},
+			mockQueryRowReaderBase: mockQueryRowReaderBase{Meta: []byte(`{"status":"success"}`)},
+		},
+	}
+	mgr := &QueryRequestsManager{
+		provider:      provider,
+		globalTimeout: 10 * time.Second,
+		tracer:        &noopTracer{},
+	}
+
+	requests, err := mgr.GetActiveRequests(nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(requests, 1)
+
+	request := requests[0]
+	suite.Assert().Equal("b8c9f4a1", request.RequestID)
+	suite.Assert().Equal("ctx-1", request.ClientContextID)
+	suite.Assert().Equal("admin", request.Users)
+	suite.Assert().Equal(2500*time.Millisecond, request.ElapsedTime)
+	suite.Assert().Equal(2400*time.Millisecond, request.ExecutionTime)
+	suite.Assert().Equal(time.Date(2020, 9, 14, 10, 31, 2, 589437000, time.UTC), request.RequestTime.UTC())
+
+	suite.Assert().Contains(provider.statement, "system:active_requests")
+	suite.Assert().Equal([]interface{}{provider.opts.ClientContextID}, provider.opts.PositionalParameters)
+	suite.Assert().Equal(10*time.Second, provider.opts.Timeout)
+	suite.Assert().True(provider.opts.Readonly)
+}
+
+func (suite *UnitTestSuite) TestQueryRequestsGetCompletedRequests() {
+	provider := &mockQueryRequestsProvider{
+		reader: &mockQueryRowReaderRows{
+			mockQueryRowReaderBase: mockQueryRowReaderBase{Meta: []byte(`{"status":"success"}`)},
+		},
+	}
+	mgr := &QueryRequestsManager{
+		provider: provider,
+		tracer:   &noopTracer{},
+	}
+
+	since := time.Unix(1600000000, 0)
+	_, err := mgr.GetCompletedRequests(&GetCompletedQueryRequestsOptions{
+		Since:          since,
+		MinElapsedTime: time.Second,
+		State:          "errors",
+		User:           "admin",
+		Limit:          10,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal("SELECT r.* FROM system:completed_requests AS r WHERE STR_TO_MILLIS(r.requestTime) >= ? AND "+
+		"STR_TO_DURATION(r.elapsedTime) >= ? AND r.state = ? AND ? IN SPLIT(r.users, \",\") "+
+		"ORDER BY STR_TO_MILLIS(r.requestTime) DESC LIMIT 10", provider.statement)
+	suite.Assert().Equal([]interface{}{int64(1600000000000), int64(time.Second), "errors", "admin"},
+		provider.opts.PositionalParameters)
+}
+
+func (suite *UnitTestSuite) TestQueryRequestsCancelRequest() {
+	provider := &mockQueryRequestsProvider{
+		reader: &mockQueryRowReaderRows{
+			mockQueryRowReaderBase: mockQueryRowReaderBase{Meta: []byte(`{"status":"success","metrics":{"elapsedTime":"1ms","executionTime":"1ms","mutationCount":1}}`)},
+		},
+	}
+	mgr := &QueryRequestsManager{
+		provider: provider,
+		tracer:   &noopTracer{},
+	}
+
+	err := mgr.CancelRequest("b8c9f4a1", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("DELETE FROM system:active_requests WHERE requestId = ?", provider.statement)
+	suite.Assert().Equal([]interface{}{"b8c9f4a1"}, provider.opts.PositionalParameters)
+
+	provider.reader = &mockQueryRowReaderRows{
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Meta: []byte(`{"status":"success","metrics":{"elapsedTime":"1ms","executionTime":"1ms"}}`)},
+	}
+	err = mgr.CancelRequestByClientContextID("ctx-1", nil)
+	suite.Assert().True(errors.Is(err, ErrQueryRequestNotFound))
+	suite.Assert().Equal("DELETE FROM system:active_requests WHERE clientContextID = ?", provider.statement)
+
+	err = mgr.CancelRequest("", nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
@@ -95,13 +95,13 @@ func (si *SearchIndex) toData() (jsonSearchIndex, error) {
 type SearchIndexManager struct {
 	mgmtProvider mgmtProvider
 
-	tracer requestTracer
+	tracer RequestTracer
 }
 
 func (sm *SearchIndexManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logDebugf("Failed to read search index response body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to read search index response body: %s", err)
 		return nil
 	}
 
@@ -130,6 +130,7 @@ func (sm *SearchIndexManager) doMgmtRequest(req mgmtRequest) (*mgmtResponse, err
 type GetAllSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllIndexes retrieves all of the search indexes for the cluster.
@@ -138,7 +139,7 @@ func (sm *SearchIndexManager) GetAllIndexes(opts *GetAllSearchIndexOptions) ([]S
 		opts = &GetAllSearchIndexOptions{}
 	}
 
-	span := sm.tracer.StartSpan("GetAllIndexes", nil).
+	span := sm.tracer.StartSpan("GetAllIndexes", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -192,6 +193,7 @@ func (sm *SearchIndexManager) GetAllIndexes(opts *GetAllSearchIndexOptions) ([]S
 type GetSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetIndex retrieves a specific search index by name.
@@ -200,7 +202,7 @@ func (sm *SearchIndexManager) GetIndex(indexName string, opts *GetSearchIndexOpt
 		opts = &GetSearchIndexOptions{}
 	}
 
-	span := sm.tracer.StartSpan("GetIndex", nil).
+	span := sm.tracer.StartSpan("GetIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -248,6 +250,7 @@ func (sm *SearchIndexManager) GetIndex(indexName string, opts *GetSearchIndexOpt
 type UpsertSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // UpsertIndex creates or updates a search index.
@@ -263,7 +266,7 @@ func (sm *SearchIndexManager) UpsertIndex(indexDefinition SearchIndex, opts *Ups
 		return invalidArgumentsError{"index type cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("UpsertIndex", nil).
+	span := sm.tracer.StartSpan("UpsertIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -311,6 +314,7 @@ func (sm *SearchIndexManager) UpsertIndex(indexDefinition SearchIndex, opts *Ups
 type DropSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropIndex removes the search index with the specific name.
@@ -323,7 +327,7 @@ func (sm *SearchIndexManager) DropIndex(indexName string, opts *DropSearchIndexO
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("DropIndex", nil).
+	span := sm.tracer.StartSpan("DropIndex", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -352,6 +356,7 @@ func (sm *SearchIndexManager) DropIndex(indexName string, opts *DropSearchIndexO
 type AnalyzeDocumentOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // AnalyzeDocument returns how a doc is analyzed against a specific index.
@@ -364,7 +369,7 @@ func (sm *SearchIndexManager) AnalyzeDocument(indexName string, doc interface{},
 		return nil, invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("AnalyzeDocument", nil).
+	span := sm.tracer.StartSpan("AnalyzeDocument", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -415,6 +420,7 @@ func (sm *SearchIndexManager) AnalyzeDocument(indexName string, doc interface{},
 type GetIndexedDocumentsCountOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetIndexedDocumentsCount retrieves the document count for a search index.
@@ -427,7 +433,7 @@ func (sm *SearchIndexManager) GetIndexedDocumentsCount(indexName string, opts *G
 		return 0, invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("GetIndexedDocumentsCount", nil).
+	span := sm.tracer.StartSpan("GetIndexedDocumentsCount", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -468,7 +474,7 @@ func (sm *SearchIndexManager) GetIndexedDocumentsCount(indexName string, opts *G
 }
 
 func (sm *SearchIndexManager) performControlRequest(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	method, uri string,
 	timeout time.Duration,
 	retryStrategy RetryStrategy,
@@ -505,6 +511,7 @@ func (sm *SearchIndexManager) performControlRequest(
 type PauseIngestSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // PauseIngest pauses updates and maintenance for an index.
@@ -517,7 +524,7 @@ func (sm *SearchIndexManager) PauseIngest(indexName string, opts *PauseIngestSea
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("PauseIngest", nil).
+	span := sm.tracer.StartSpan("PauseIngest", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -533,6 +540,7 @@ func (sm *SearchIndexManager) PauseIngest(indexName string, opts *PauseIngestSea
 type ResumeIngestSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // ResumeIngest resumes updates and maintenance for an index.
@@ -545,7 +553,7 @@ func (sm *SearchIndexManager) ResumeIngest(indexName string, opts *ResumeIngestS
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("ResumeIngest", nil).
+	span := sm.tracer.StartSpan("ResumeIngest", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -561,6 +569,7 @@ func (sm *SearchIndexManager) ResumeIngest(indexName string, opts *ResumeIngestS
 type AllowQueryingSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // AllowQuerying allows querying against an index.
@@ -573,7 +582,7 @@ func (sm *SearchIndexManager) AllowQuerying(indexName string, opts *AllowQueryin
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("AllowQuerying", nil).
+	span := sm.tracer.StartSpan("AllowQuerying", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -589,6 +598,7 @@ func (sm *SearchIndexManager) AllowQuerying(indexName string, opts *AllowQueryin
 type DisallowQueryingSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DisallowQuerying disallows querying against an index.
@@ -601,7 +611,7 @@ func (sm *SearchIndexManager) DisallowQuerying(indexName string, opts *AllowQuer
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("DisallowQuerying", nil).
+	span := sm.tracer.StartSpan("DisallowQuerying", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -617,6 +627,7 @@ func (sm *SearchIndexManager) DisallowQuerying(indexName string, opts *AllowQuer
 type FreezePlanSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // FreezePlan freezes the assignment of index partitions to nodes.
@@ -629,7 +640,7 @@ func (sm *SearchIndexManager) FreezePlan(indexName string, opts *AllowQueryingSe
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("FreezePlan", nil).
+	span := sm.tracer.StartSpan("FreezePlan", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -645,6 +656,7 @@ func (sm *SearchIndexManager) FreezePlan(indexName string, opts *AllowQueryingSe
 type UnfreezePlanSearchIndexOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // UnfreezePlan unfreezes the assignment of index partitions to nodes.
@@ -657,7 +669,7 @@ func (sm *SearchIndexManager) UnfreezePlan(indexName string, opts *AllowQuerying
 		return invalidArgumentsError{"indexName cannot be empty"}
 	}
 
-	span := sm.tracer.StartSpan("UnfreezePlan", nil).
+	span := sm.tracer.StartSpan("UnfreezePlan", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
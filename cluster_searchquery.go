@@ -161,22 +161,29 @@ func (r *SearchResult) Next() bool {
 		return false
 	}
 
-	r.currentRow = SearchRow{}
-
 	var rowData jsonSearchRow
 	if err := json.Unmarshal(rowBytes, &rowData); err != nil {
 		// This should never happen but if it does then lets store it in a best efforts basis and maybe the next
 		// row will be ok. We can then return this from .Err().
+		r.currentRow = SearchRow{}
 		r.jsonErr = err
 		return true
 	}
 
-	r.currentRow.Index = rowData.Index
-	r.currentRow.ID = rowData.ID
-	r.currentRow.Score = rowData.Score
-	r.currentRow.Explanation = rowData.Explanation
-	r.currentRow.Fragments = rowData.Fragments
-	r.currentRow.fieldsBytes = rowData.Fields
+	r.currentRow = newSearchRow(rowData)
+
+	return true
+}
+
+func newSearchRow(rowData jsonSearchRow) SearchRow {
+	row := SearchRow{
+		Index:       rowData.Index,
+		ID:          rowData.ID,
+		Score:       rowData.Score,
+		Explanation: rowData.Explanation,
+		Fragments:   rowData.Fragments,
+		fieldsBytes: rowData.Fields,
+	}
 
 	locations := make(map[string]map[string][]SearchRowLocation)
 	for fieldName, fieldData := range rowData.Locations {
@@ -186,16 +193,16 @@ func (r *SearchResult) Next() bool {
 			for locIdx, locData := range termData {
 				err := locations[locIdx].fromData(locData)
 				if err != nil {
-					logWarnf("failed to parse search query location data: %s", err)
+					logFieldsf(LogSubsystemQuery, LogWarn, nil, "failed to parse search query location data: %s", err)
 				}
 			}
 			terms[termName] = locations
 		}
 		locations[fieldName] = terms
 	}
-	r.currentRow.Locations = locations
+	row.Locations = locations
 
-	return true
+	return row
 }
 
 // Row returns the contents of the current row.
@@ -274,12 +281,77 @@ func (r *SearchResult) Facets() (map[string]SearchFacetResult, error) {
 }
 
 // SearchQuery executes the analytics query statement on the server.
-func (c *Cluster) SearchQuery(indexName string, query cbsearch.Query, opts *SearchOptions) (*SearchResult, error) {
+func (c *Cluster) SearchQuery(indexName string, query cbsearch.Query, opts *SearchOptions) (resOut *SearchResult, errOut error) {
 	if opts == nil {
 		opts = &SearchOptions{}
 	}
 
-	span := c.tracer.StartSpan("SearchQuery", opts.parentSpan).
+	start := time.Now()
+	history := &retryHistory{}
+	defer func() {
+		errOut = c.retryBudget.maybeMarkExhausted(errOut)
+		c.retryBudget.recordOutcome(errOut)
+		if errOut != nil {
+			recordOperationMetric(c.meter, ServiceTypeSearch, "SearchQuery", start, errOut, history)
+		}
+	}()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = c.timeoutsConfig.SearchTimeout
+	}
+	release, err := c.admission.admit(ServiceTypeSearch, "", start.Add(timeout))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errOut != nil {
+			release()
+			return
+		}
+
+		// The request remains in flight, and is timed, until its rows have been read or the results closed.
+		complete := func(streamErr error) {
+			release()
+			recordOperationMetric(c.meter, ServiceTypeSearch, "SearchQuery", start, streamErr, history)
+		}
+		resOut.reader = releaseOnComplete(resOut.reader, complete)
+	}()
+
+	if len(c.serviceInterceptors) == 0 {
+		return c.searchQuery(indexName, query, opts, start, history)
+	}
+
+	interceptedOpts := *opts
+	req := &ServiceInterceptorRequest{
+		Service:   ServiceTypeSearch,
+		IndexName: indexName,
+		Query:     query,
+		Options:   &interceptedOpts,
+	}
+	interception, err := interceptServiceRequest(c.serviceInterceptors, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.searchQuery(req.IndexName, req.Query, &interceptedOpts, start, history)
+	if err != nil {
+		interception.complete(nil, err)
+		return nil, err
+	}
+
+	res.reader = interception.completionReader(res.reader, func() (interface{}, error) {
+		return res.MetaData()
+	})
+
+	return res, nil
+}
+
+// searchQuery executes query, timing out relative to start so that any time spent waiting for admission
+// counts towards the timeout.
+func (c *Cluster) searchQuery(indexName string, query cbsearch.Query, opts *SearchOptions, start time.Time,
+	history *retryHistory) (*SearchResult, error) {
+	span := c.tracer.StartSpan("SearchQuery", opts.ParentSpan).
 		SetTag("couchbase.service", "search")
 	defer span.Finish()
 
@@ -287,12 +359,9 @@ func (c *Cluster) SearchQuery(indexName string, query cbsearch.Query, opts *Sear
 	if timeout == 0 {
 		timeout = c.timeoutsConfig.SearchTimeout
 	}
-	deadline := time.Now().Add(timeout)
+	deadline := start.Add(timeout)
 
-	retryStrategy := c.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryStrategy = newRetryStrategyWrapper(opts.RetryStrategy)
-	}
+	retryStrategy := c.retryStrategyWrapper.withHistory(history).forOperation(opts.RetryStrategy)
 
 	searchOpts, err := opts.toMap()
 	if err != nil {
@@ -315,7 +384,7 @@ func maybeGetSearchOptionQuery(options map[string]interface{}) interface{} {
 }
 
 func (c *Cluster) execSearchQuery(
-	span requestSpan,
+	span RequestSpan,
 	indexName string,
 	options map[string]interface{},
 	deadline time.Time,
@@ -345,7 +414,7 @@ func (c *Cluster) execSearchQuery(
 		TraceContext:  span.Context(),
 	})
 	if err != nil {
-		return nil, maybeEnhanceSearchError(err)
+		return nil, retryStrategy.attachHistory(maybeEnhanceSearchError(err))
 	}
 
 	return newSearchResult(res), nil
@@ -227,13 +227,13 @@ func (g *Group) fromData(data jsonGroup) error {
 // UserManager provides methods for performing Couchbase user management.
 type UserManager struct {
 	provider mgmtProvider
-	tracer   requestTracer
+	tracer   RequestTracer
 }
 
 func (um *UserManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logDebugf("Failed to read search index response body: %s", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to read search index response body: %s", err)
 		return nil
 	}
 
@@ -261,6 +261,7 @@ func (um *UserManager) tryParseErrorMessage(req *mgmtRequest, resp *mgmtResponse
 type GetAllUsersOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 
 	DomainName string
 }
@@ -271,7 +272,7 @@ func (um *UserManager) GetAllUsers(opts *GetAllUsersOptions) ([]UserAndMetadata,
 		opts = &GetAllUsersOptions{}
 	}
 
-	span := um.tracer.StartSpan("GetAllUsers", nil).
+	span := um.tracer.StartSpan("GetAllUsers", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -326,6 +327,7 @@ func (um *UserManager) GetAllUsers(opts *GetAllUsersOptions) ([]UserAndMetadata,
 type GetUserOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 
 	DomainName string
 }
@@ -336,7 +338,7 @@ func (um *UserManager) GetUser(name string, opts *GetUserOptions) (*UserAndMetad
 		opts = &GetUserOptions{}
 	}
 
-	span := um.tracer.StartSpan("GetUser", nil).
+	span := um.tracer.StartSpan("GetUser", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -389,6 +391,7 @@ func (um *UserManager) GetUser(name string, opts *GetUserOptions) (*UserAndMetad
 type UpsertUserOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 
 	DomainName string
 }
@@ -399,7 +402,7 @@ func (um *UserManager) UpsertUser(user User, opts *UpsertUserOptions) error {
 		opts = &UpsertUserOptions{}
 	}
 
-	span := um.tracer.StartSpan("UpsertUser", nil).
+	span := um.tracer.StartSpan("UpsertUser", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -459,6 +462,7 @@ func (um *UserManager) UpsertUser(user User, opts *UpsertUserOptions) error {
 type DropUserOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 
 	DomainName string
 }
@@ -469,7 +473,7 @@ func (um *UserManager) DropUser(name string, opts *DropUserOptions) error {
 		opts = &DropUserOptions{}
 	}
 
-	span := um.tracer.StartSpan("DropUser", nil).
+	span := um.tracer.StartSpan("DropUser", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -508,6 +512,7 @@ func (um *UserManager) DropUser(name string, opts *DropUserOptions) error {
 type GetRolesOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetRoles lists the roles supported by the cluster.
@@ -516,7 +521,7 @@ func (um *UserManager) GetRoles(opts *GetRolesOptions) ([]RoleAndDescription, er
 		opts = &GetRolesOptions{}
 	}
 
-	span := um.tracer.StartSpan("GetRoles", nil).
+	span := um.tracer.StartSpan("GetRoles", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -567,6 +572,7 @@ func (um *UserManager) GetRoles(opts *GetRolesOptions) ([]RoleAndDescription, er
 type GetGroupOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetGroup fetches a single group from the server.
@@ -578,7 +584,7 @@ func (um *UserManager) GetGroup(groupName string, opts *GetGroupOptions) (*Group
 		opts = &GetGroupOptions{}
 	}
 
-	span := um.tracer.StartSpan("GetGroup", nil).
+	span := um.tracer.StartSpan("GetGroup", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -627,6 +633,7 @@ func (um *UserManager) GetGroup(groupName string, opts *GetGroupOptions) (*Group
 type GetAllGroupsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllGroups fetches all groups from the server.
@@ -635,7 +642,7 @@ func (um *UserManager) GetAllGroups(opts *GetAllGroupsOptions) ([]Group, error)
 		opts = &GetAllGroupsOptions{}
 	}
 
-	span := um.tracer.StartSpan("GetAllGroups", nil).
+	span := um.tracer.StartSpan("GetAllGroups", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -686,6 +693,7 @@ func (um *UserManager) GetAllGroups(opts *GetAllGroupsOptions) ([]Group, error)
 type UpsertGroupOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // UpsertGroup creates, or updates, a group on the server.
@@ -697,7 +705,7 @@ func (um *UserManager) UpsertGroup(group Group, opts *UpsertGroupOptions) error
 		opts = &UpsertGroupOptions{}
 	}
 
-	span := um.tracer.StartSpan("UpsertGroup", nil).
+	span := um.tracer.StartSpan("UpsertGroup", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -748,6 +756,7 @@ func (um *UserManager) UpsertGroup(group Group, opts *UpsertGroupOptions) error
 type DropGroupOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // DropGroup removes a group from the server.
@@ -760,7 +769,7 @@ func (um *UserManager) DropGroup(groupName string, opts *DropGroupOptions) error
 		opts = &DropGroupOptions{}
 	}
 
-	span := um.tracer.StartSpan("DropGroup", nil).
+	span := um.tracer.StartSpan("DropGroup", opts.ParentSpan).
 		SetTag("couchbase.service", "mgmt")
 	defer span.Finish()
 
@@ -17,7 +17,16 @@ type Collection struct {
 
 	transcoder           Transcoder
 	retryStrategyWrapper *retryStrategyWrapper
-	tracer               requestTracer
+	tracer               RequestTracer
+	meter                Meter
+	retryBudget          *RetryBudget
+	admission            *admissionController
+	kvInterceptors       []KVInterceptor
+
+	// operationStart is when the operation being dispatched with this collection began, its timeout
+	// runs from then so that time spent waiting for admission counts towards it.  It is only set on
+	// the copy of the collection which interceptKV dispatches with.
+	operationStart time.Time
 
 	useMutationTokens bool
 
@@ -35,6 +44,10 @@ func newCollection(scope *Scope, collectionName string) *Collection {
 		transcoder:           scope.transcoder,
 		retryStrategyWrapper: scope.retryStrategyWrapper,
 		tracer:               scope.tracer,
+		meter:                scope.meter,
+		retryBudget:          scope.retryBudget,
+		admission:            scope.admission,
+		kvInterceptors:       scope.kvInterceptors,
 
 		useMutationTokens: scope.useMutationTokens,
 
@@ -63,7 +76,7 @@ func (c *Collection) Name() string {
 	return c.collectionName
 }
 
-func (c *Collection) startKvOpTrace(operationName string, tracectx requestSpanContext) requestSpan {
+func (c *Collection) startKvOpTrace(operationName string, tracectx RequestSpanContext) RequestSpan {
 	return c.tracer.StartSpan(operationName, tracectx).
 		SetTag("couchbase.bucket", c.bucket).
 		SetTag("couchbase.collection", c.collectionName).
@@ -73,3 +86,13 @@ func (c *Collection) startKvOpTrace(operationName string, tracectx requestSpanCo
 func (c *Collection) bucketName() string {
 	return c.bucket.Name()
 }
+
+func (c *Collection) logFields(operation, id string) []LogField {
+	return []LogField{
+		{Key: LogFieldBucket, Value: redactMetaData(c.bucketName())},
+		{Key: LogFieldScope, Value: redactMetaData(c.ScopeName())},
+		{Key: LogFieldCollection, Value: redactMetaData(c.Name())},
+		{Key: LogFieldOperation, Value: operation},
+		{Key: LogFieldDocumentID, Value: redactUserData(id)},
+	}
+}
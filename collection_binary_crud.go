@@ -19,6 +19,7 @@ type AppendOptions struct {
 	ReplicateTo     uint
 	Cas             Cas
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 func (c *Collection) binaryAppend(id string, val []byte, opts *AppendOptions) (mutOut *MutationResult, errOut error) {
@@ -26,7 +27,7 @@ func (c *Collection) binaryAppend(id string, val []byte, opts *AppendOptions) (m
 		opts = &AppendOptions{}
 	}
 
-	opm := c.newKvOpManager("Append", nil)
+	opm := c.newKvOpManager("Append", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -74,7 +75,20 @@ func (c *Collection) binaryAppend(id string, val []byte, opts *AppendOptions) (m
 
 // Append appends a byte value to a document.
 func (c *BinaryCollection) Append(id string, val []byte, opts *AppendOptions) (mutOut *MutationResult, errOut error) {
-	return c.collection.binaryAppend(id, val, opts)
+	if opts == nil {
+		opts = &AppendOptions{}
+	}
+
+	interceptedOpts := *opts
+	errOut = c.collection.interceptKV("Append", id, val, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var val []byte
+		if err := assignIntercepted(&val, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.binaryAppend(req.DocumentID, val, &interceptedOpts)
+	})
+	return
 }
 
 // PrependOptions are the options available to the Prepend operation.
@@ -85,6 +99,7 @@ type PrependOptions struct {
 	ReplicateTo     uint
 	Cas             Cas
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 func (c *Collection) binaryPrepend(id string, val []byte, opts *PrependOptions) (mutOut *MutationResult, errOut error) {
@@ -92,7 +107,7 @@ func (c *Collection) binaryPrepend(id string, val []byte, opts *PrependOptions)
 		opts = &PrependOptions{}
 	}
 
-	opm := c.newKvOpManager("Prepend", nil)
+	opm := c.newKvOpManager("Prepend", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -140,7 +155,20 @@ func (c *Collection) binaryPrepend(id string, val []byte, opts *PrependOptions)
 
 // Prepend prepends a byte value to a document.
 func (c *BinaryCollection) Prepend(id string, val []byte, opts *PrependOptions) (mutOut *MutationResult, errOut error) {
-	return c.collection.binaryPrepend(id, val, opts)
+	if opts == nil {
+		opts = &PrependOptions{}
+	}
+
+	interceptedOpts := *opts
+	errOut = c.collection.interceptKV("Prepend", id, val, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var val []byte
+		if err := assignIntercepted(&val, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.binaryPrepend(req.DocumentID, val, &interceptedOpts)
+	})
+	return
 }
 
 // IncrementOptions are the options available to the Increment operation.
@@ -159,6 +187,7 @@ type IncrementOptions struct {
 	ReplicateTo     uint
 	Cas             Cas
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 func (c *Collection) binaryIncrement(id string, opts *IncrementOptions) (countOut *CounterResult, errOut error) {
@@ -166,7 +195,7 @@ func (c *Collection) binaryIncrement(id string, opts *IncrementOptions) (countOu
 		opts = &IncrementOptions{}
 	}
 
-	opm := c.newKvOpManager("Increment", nil)
+	opm := c.newKvOpManager("Increment", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -224,7 +253,15 @@ func (c *Collection) binaryIncrement(id string, opts *IncrementOptions) (countOu
 // non-negative `initial` value will cause the document to be created if it did not
 // already exist.
 func (c *BinaryCollection) Increment(id string, opts *IncrementOptions) (countOut *CounterResult, errOut error) {
-	return c.collection.binaryIncrement(id, opts)
+	if opts == nil {
+		opts = &IncrementOptions{}
+	}
+
+	interceptedOpts := *opts
+	errOut = c.collection.interceptKV("Increment", id, nil, &interceptedOpts, &countOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.binaryIncrement(req.DocumentID, &interceptedOpts)
+	})
+	return
 }
 
 // DecrementOptions are the options available to the Decrement operation.
@@ -243,6 +280,7 @@ type DecrementOptions struct {
 	ReplicateTo     uint
 	Cas             Cas
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 func (c *Collection) binaryDecrement(id string, opts *DecrementOptions) (countOut *CounterResult, errOut error) {
@@ -250,7 +288,7 @@ func (c *Collection) binaryDecrement(id string, opts *DecrementOptions) (countOu
 		opts = &DecrementOptions{}
 	}
 
-	opm := c.newKvOpManager("Decrement", nil)
+	opm := c.newKvOpManager("Decrement", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -308,5 +346,13 @@ func (c *Collection) binaryDecrement(id string, opts *DecrementOptions) (countOu
 // non-negative `initial` value will cause the document to be created if it did not
 // already exist.
 func (c *BinaryCollection) Decrement(id string, opts *DecrementOptions) (countOut *CounterResult, errOut error) {
-	return c.collection.binaryDecrement(id, opts)
+	if opts == nil {
+		opts = &DecrementOptions{}
+	}
+
+	interceptedOpts := *opts
+	errOut = c.collection.interceptKV("Decrement", id, nil, &interceptedOpts, &countOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.binaryDecrement(req.DocumentID, &interceptedOpts)
+	})
+	return
 }
@@ -8,7 +8,7 @@ import (
 
 type bulkOp struct {
 	pendop gocbcore.PendingOp
-	span   requestSpan
+	span   RequestSpan
 }
 
 func (op *bulkOp) cancel() {
@@ -24,8 +24,8 @@ func (op *bulkOp) finish() {
 // such as GetOp, UpsertOp, ReplaceOp, and more.
 // UNCOMMITTED: This API may change in the future.
 type BulkOp interface {
-	execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-		retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan)
+	execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+		retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan)
 	markError(err error)
 	cancel()
 	finish()
@@ -36,6 +36,7 @@ type BulkOpOptions struct {
 	Timeout       time.Duration
 	Transcoder    Transcoder
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // Do execute one or more `BulkOp` items in parallel.
@@ -45,17 +46,26 @@ func (c *Collection) Do(ops []BulkOp, opts *BulkOpOptions) error {
 		opts = &BulkOpOptions{}
 	}
 
-	span := c.startKvOpTrace("Do", nil)
+	interceptedOpts := *opts
+	return c.interceptKV("Do", "", ops, &interceptedOpts, nil, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var ops []BulkOp
+		if err := assignIntercepted(&ops, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return nil, col.do(ops, &interceptedOpts)
+	})
+}
+
+func (c *Collection) do(ops []BulkOp, opts *BulkOpOptions) error {
+	span := c.startKvOpTrace("Do", opts.ParentSpan)
 
 	timeout := opts.Timeout
 	if opts.Timeout == 0 {
 		timeout = c.timeoutsConfig.KVTimeout * time.Duration(len(ops))
 	}
 
-	retryWrapper := c.retryStrategyWrapper
-	if opts.RetryStrategy != nil {
-		retryWrapper = newRetryStrategyWrapper(opts.RetryStrategy)
-	}
+	retryWrapper := c.retryStrategyWrapper.withStrategy(opts.RetryStrategy)
 
 	if opts.Transcoder == nil {
 		opts.Transcoder = c.transcoder
@@ -71,7 +81,7 @@ func (c *Collection) Do(ops []BulkOp, opts *BulkOpOptions) error {
 	//   individual op handlers when they dispatch their signal).
 	signal := make(chan BulkOp, len(ops))
 	for _, item := range ops {
-		item.execute(span.Context(), c, agent, opts.Transcoder, signal, retryWrapper, time.Now().Add(timeout), c.startKvOpTrace)
+		item.execute(span.Context(), c, agent, opts.Transcoder, signal, retryWrapper, c.operationDeadline(timeout), c.startKvOpTrace)
 	}
 
 	for range ops {
@@ -97,8 +107,8 @@ func (item *GetOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *GetOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *GetOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("GetOp", tracectx)
 	item.bulkOp.span = span
 
@@ -146,8 +156,8 @@ func (item *GetAndTouchOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *GetAndTouchOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *GetAndTouchOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("GetAndTouchOp", tracectx)
 	item.bulkOp.span = span
 
@@ -196,8 +206,8 @@ func (item *TouchOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *TouchOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *TouchOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("TouchOp", tracectx)
 	item.bulkOp.span = span
 
@@ -251,8 +261,8 @@ func (item *RemoveOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *RemoveOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *RemoveOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("RemoveOp", tracectx)
 	item.bulkOp.span = span
 
@@ -308,8 +318,8 @@ func (item *UpsertOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *UpsertOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder,
-	signal chan BulkOp, retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *UpsertOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder,
+	signal chan BulkOp, retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("UpsertOp", tracectx)
 	item.bulkOp.span = span
 
@@ -376,8 +386,8 @@ func (item *InsertOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *InsertOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *InsertOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("InsertOp", tracectx)
 	item.bulkOp.span = span
 
@@ -445,8 +455,8 @@ func (item *ReplaceOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *ReplaceOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *ReplaceOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("ReplaceOp", tracectx)
 	item.bulkOp.span = span
 
@@ -513,8 +523,8 @@ func (item *AppendOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *AppendOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *AppendOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("AppendOp", tracectx)
 	item.bulkOp.span = span
 
@@ -568,8 +578,8 @@ func (item *PrependOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *PrependOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *PrependOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("PrependOp", tracectx)
 	item.bulkOp.span = span
 
@@ -626,8 +636,8 @@ func (item *IncrementOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *IncrementOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *IncrementOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("IncrementOp", tracectx)
 	item.bulkOp.span = span
 
@@ -694,8 +704,8 @@ func (item *DecrementOp) markError(err error) {
 	item.Err = err
 }
 
-func (item *DecrementOp) execute(tracectx requestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
-	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, requestSpanContext) requestSpan) {
+func (item *DecrementOp) execute(tracectx RequestSpanContext, c *Collection, provider kvProvider, transcoder Transcoder, signal chan BulkOp,
+	retryWrapper *retryStrategyWrapper, deadline time.Time, startSpanFunc func(string, RequestSpanContext) RequestSpan) {
 	span := startSpanFunc("DecrementOp", tracectx)
 	item.bulkOp.span = span
 
@@ -43,6 +43,7 @@ type InsertOptions struct {
 	Transcoder      Transcoder
 	Timeout         time.Duration
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 // Insert creates a new document in the Collection.
@@ -51,7 +52,15 @@ func (c *Collection) Insert(id string, val interface{}, opts *InsertOptions) (mu
 		opts = &InsertOptions{}
 	}
 
-	opm := c.newKvOpManager("Insert", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Insert", id, val, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.insert(req.DocumentID, req.Value, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) insert(id string, val interface{}, opts *InsertOptions) (mutOut *MutationResult, errOut error) {
+	opm := c.newKvOpManager("Insert", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -109,6 +118,7 @@ type UpsertOptions struct {
 	Transcoder      Transcoder
 	Timeout         time.Duration
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 // Upsert creates a new document in the Collection if it does not exist, if it does exist then it updates it.
@@ -117,7 +127,15 @@ func (c *Collection) Upsert(id string, val interface{}, opts *UpsertOptions) (mu
 		opts = &UpsertOptions{}
 	}
 
-	opm := c.newKvOpManager("Upsert", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Upsert", id, val, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.upsert(req.DocumentID, req.Value, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) upsert(id string, val interface{}, opts *UpsertOptions) (mutOut *MutationResult, errOut error) {
+	opm := c.newKvOpManager("Upsert", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -176,6 +194,7 @@ type ReplaceOptions struct {
 	Transcoder      Transcoder
 	Timeout         time.Duration
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 // Replace updates a document in the collection.
@@ -184,7 +203,15 @@ func (c *Collection) Replace(id string, val interface{}, opts *ReplaceOptions) (
 		opts = &ReplaceOptions{}
 	}
 
-	opm := c.newKvOpManager("Replace", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Replace", id, val, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.replace(req.DocumentID, req.Value, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) replace(id string, val interface{}, opts *ReplaceOptions) (mutOut *MutationResult, errOut error) {
+	opm := c.newKvOpManager("Replace", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -244,6 +271,7 @@ type GetOptions struct {
 	Transcoder    Transcoder
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // Get performs a fetch operation against the collection. This can take 3 paths, a standard full document
@@ -254,11 +282,15 @@ func (c *Collection) Get(id string, opts *GetOptions) (docOut *GetResult, errOut
 		opts = &GetOptions{}
 	}
 
-	if len(opts.Project) == 0 && !opts.WithExpiry {
-		return c.getDirect(id, opts)
-	}
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Get", id, nil, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		if len(interceptedOpts.Project) == 0 && !interceptedOpts.WithExpiry {
+			return col.getDirect(req.DocumentID, &interceptedOpts)
+		}
 
-	return c.getProjected(id, opts)
+		return col.getProjected(req.DocumentID, &interceptedOpts)
+	})
+	return
 }
 
 func (c *Collection) getDirect(id string, opts *GetOptions) (docOut *GetResult, errOut error) {
@@ -266,7 +298,7 @@ func (c *Collection) getDirect(id string, opts *GetOptions) (docOut *GetResult,
 		opts = &GetOptions{}
 	}
 
-	opm := c.newKvOpManager("Get", nil)
+	opm := c.newKvOpManager("Get", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -320,7 +352,7 @@ func (c *Collection) getProjected(id string, opts *GetOptions) (docOut *GetResul
 		opts = &GetOptions{}
 	}
 
-	opm := c.newKvOpManager("Get", nil)
+	opm := c.newKvOpManager("Get", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -422,6 +454,7 @@ func (c *Collection) getProjected(id string, opts *GetOptions) (docOut *GetResul
 type ExistsOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // Exists checks if a document exists for the given id.
@@ -430,7 +463,15 @@ func (c *Collection) Exists(id string, opts *ExistsOptions) (docOut *ExistsResul
 		opts = &ExistsOptions{}
 	}
 
-	opm := c.newKvOpManager("Exists", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Exists", id, nil, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.exists(req.DocumentID, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) exists(id string, opts *ExistsOptions) (docOut *ExistsResult, errOut error) {
+	opm := c.newKvOpManager("Exists", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -488,7 +529,7 @@ func (c *Collection) Exists(id string, opts *ExistsOptions) (docOut *ExistsResul
 }
 
 func (c *Collection) getOneReplica(
-	span requestSpanContext,
+	span RequestSpanContext,
 	id string,
 	replicaIdx int,
 	transcoder Transcoder,
@@ -574,6 +615,7 @@ type GetAllReplicaOptions struct {
 	Transcoder    Transcoder
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAllReplicasResult represents the results of a GetAllReplicas operation.
@@ -641,7 +683,15 @@ func (c *Collection) GetAllReplicas(id string, opts *GetAllReplicaOptions) (docO
 		opts = &GetAllReplicaOptions{}
 	}
 
-	span := c.startKvOpTrace("GetAllReplicas", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("GetAllReplicas", id, nil, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.getAllReplicas(req.DocumentID, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) getAllReplicas(id string, opts *GetAllReplicaOptions) (docOut *GetAllReplicasResult, errOut error) {
+	span := c.startKvOpTrace("GetAllReplicas", opts.ParentSpan)
 	defer span.Finish()
 
 	// Timeout needs to be adjusted here, since we use it at the bottom of this
@@ -652,7 +702,7 @@ func (c *Collection) GetAllReplicas(id string, opts *GetAllReplicaOptions) (docO
 		timeout = c.timeoutsConfig.KVTimeout
 	}
 
-	deadline := time.Now().Add(timeout)
+	deadline := c.operationDeadline(timeout)
 	transcoder := opts.Transcoder
 	retryStrategy := opts.RetryStrategy
 
@@ -687,9 +737,10 @@ func (c *Collection) GetAllReplicas(id string, opts *GetAllReplicaOptions) (docO
 			// This timeout value will cause the getOneReplica operation to timeout after our deadline has expired,
 			// as the deadline has already begun. getOneReplica timing out before our deadline would cause inconsistent
 			// behaviour.
-			res, err := c.getOneReplica(span, id, replicaIdx, transcoder, retryStrategy, cancelCh, timeout)
+			res, err := c.getOneReplica(span.Context(), id, replicaIdx, transcoder, retryStrategy, cancelCh, timeout)
 			if err != nil {
-				logDebugf("Failed to fetch replica from replica %d: %s", replicaIdx, err)
+				logFieldsf(LogSubsystemKeyValue, LogDebug, withErrorLogFields(c.logFields("GetAllReplicas", id), err),
+					"Failed to fetch replica from replica %d: %s", replicaIdx, err)
 			} else {
 				repRes.addResult(res)
 			}
@@ -703,7 +754,8 @@ func (c *Collection) GetAllReplicas(id string, opts *GetAllReplicaOptions) (docO
 			// If we timeout, we should close the result
 			err := repRes.Close()
 			if err != nil {
-				logDebugf("failed to close GetAllReplicas response: %s", err)
+				logFieldsf(LogSubsystemKeyValue, LogDebug, c.logFields("GetAllReplicas", id),
+					"failed to close GetAllReplicas response: %s", err)
 			}
 			return
 		case <-cancelCh:
@@ -720,6 +772,7 @@ type GetAnyReplicaOptions struct {
 	Transcoder    Transcoder
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAnyReplica returns the value of a particular document from a replica server.
@@ -728,13 +781,22 @@ func (c *Collection) GetAnyReplica(id string, opts *GetAnyReplicaOptions) (docOu
 		opts = &GetAnyReplicaOptions{}
 	}
 
-	span := c.startKvOpTrace("GetAnyReplica", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("GetAnyReplica", id, nil, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.getAnyReplica(req.DocumentID, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) getAnyReplica(id string, opts *GetAnyReplicaOptions) (docOut *GetReplicaResult, errOut error) {
+	span := c.startKvOpTrace("GetAnyReplica", opts.ParentSpan)
 	defer span.Finish()
 
-	repRes, err := c.GetAllReplicas(id, &GetAllReplicaOptions{
+	repRes, err := c.getAllReplicas(id, &GetAllReplicaOptions{
 		Timeout:       opts.Timeout,
 		Transcoder:    opts.Transcoder,
 		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span.Context(),
 	})
 	if err != nil {
 		return nil, err
@@ -755,7 +817,8 @@ func (c *Collection) GetAnyReplica(id string, opts *GetAnyReplicaOptions) (docOu
 	// remaining result objects at this point.
 	err = repRes.Close()
 	if err != nil {
-		logDebugf("failed to close GetAnyReplica response: %s", err)
+		logFieldsf(LogSubsystemKeyValue, LogDebug, c.logFields("GetAnyReplica", id),
+			"failed to close GetAnyReplica response: %s", err)
 	}
 
 	return res, nil
@@ -769,6 +832,7 @@ type RemoveOptions struct {
 	DurabilityLevel DurabilityLevel
 	Timeout         time.Duration
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 }
 
 // Remove removes a document from the collection.
@@ -777,7 +841,15 @@ func (c *Collection) Remove(id string, opts *RemoveOptions) (mutOut *MutationRes
 		opts = &RemoveOptions{}
 	}
 
-	opm := c.newKvOpManager("Remove", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Remove", id, nil, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		return col.remove(req.DocumentID, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) remove(id string, opts *RemoveOptions) (mutOut *MutationResult, errOut error) {
+	opm := c.newKvOpManager("Remove", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -827,6 +899,7 @@ type GetAndTouchOptions struct {
 	Transcoder    Transcoder
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAndTouch retrieves a document and simultaneously updates its expiry time.
@@ -835,7 +908,20 @@ func (c *Collection) GetAndTouch(id string, expiry time.Duration, opts *GetAndTo
 		opts = &GetAndTouchOptions{}
 	}
 
-	opm := c.newKvOpManager("GetAndTouch", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("GetAndTouch", id, expiry, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var expiry time.Duration
+		if err := assignIntercepted(&expiry, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.getAndTouch(req.DocumentID, expiry, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) getAndTouch(id string, expiry time.Duration, opts *GetAndTouchOptions) (docOut *GetResult, errOut error) {
+	opm := c.newKvOpManager("GetAndTouch", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -892,6 +978,7 @@ type GetAndLockOptions struct {
 	Transcoder    Transcoder
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // GetAndLock locks a document for a period of time, providing exclusive RW access to it.
@@ -902,7 +989,20 @@ func (c *Collection) GetAndLock(id string, lockTime time.Duration, opts *GetAndL
 		opts = &GetAndLockOptions{}
 	}
 
-	opm := c.newKvOpManager("GetAndLock", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("GetAndLock", id, lockTime, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var lockTime time.Duration
+		if err := assignIntercepted(&lockTime, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.getAndLock(req.DocumentID, lockTime, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) getAndLock(id string, lockTime time.Duration, opts *GetAndLockOptions) (docOut *GetResult, errOut error) {
+	opm := c.newKvOpManager("GetAndLock", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -958,6 +1058,7 @@ func (c *Collection) GetAndLock(id string, lockTime time.Duration, opts *GetAndL
 type UnlockOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // Unlock unlocks a document which was locked with GetAndLock.
@@ -966,7 +1067,20 @@ func (c *Collection) Unlock(id string, cas Cas, opts *UnlockOptions) (errOut err
 		opts = &UnlockOptions{}
 	}
 
-	opm := c.newKvOpManager("Unlock", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Unlock", id, cas, &interceptedOpts, nil, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var cas Cas
+		if err := assignIntercepted(&cas, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return nil, col.unlock(req.DocumentID, cas, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) unlock(id string, cas Cas, opts *UnlockOptions) (errOut error) {
+	opm := c.newKvOpManager("Unlock", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -1009,6 +1123,7 @@ func (c *Collection) Unlock(id string, cas Cas, opts *UnlockOptions) (errOut err
 type TouchOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 }
 
 // Touch touches a document, specifying a new expiry time for it.
@@ -1017,7 +1132,20 @@ func (c *Collection) Touch(id string, expiry time.Duration, opts *TouchOptions)
 		opts = &TouchOptions{}
 	}
 
-	opm := c.newKvOpManager("Touch", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("Touch", id, expiry, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var expiry time.Duration
+		if err := assignIntercepted(&expiry, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.touch(req.DocumentID, expiry, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) touch(id string, expiry time.Duration, opts *TouchOptions) (mutOut *MutationResult, errOut error) {
+	opm := c.newKvOpManager("Touch", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -1167,7 +1167,7 @@ func (suite *IntegrationTestSuite) TestGetAndLock() {
 	}
 
 	mutRes, err = globalCollection.Upsert("getAndLock", doc, &UpsertOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err == nil {
 		suite.T().Fatalf("Expected error but was nil")
@@ -1271,7 +1271,7 @@ func (suite *IntegrationTestSuite) TestUnlockInvalidCas() {
 	}
 
 	err = globalCollection.Unlock("unlockInvalidCas", lockedDoc.Cas()+1, &UnlockOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err == nil {
 		suite.T().Fatalf("Unlock should have failed")
@@ -1329,7 +1329,7 @@ func (suite *IntegrationTestSuite) TestDoubleLockFail() {
 	}
 
 	_, err = globalCollection.GetAndLock("doubleLock", 1, &GetAndLockOptions{
-		RetryStrategy: newFailFastRetryStrategy(),
+		RetryStrategy: NewFailFastRetryStrategy(),
 	})
 	if err == nil {
 		suite.T().Fatalf("Expected GetAndLock to fail")
@@ -7,7 +7,7 @@ import (
 )
 
 func (c *Collection) observeOnceSeqNo(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	docID string,
 	mt gocbcore.MutationToken,
 	replicaIdx int,
@@ -50,7 +50,7 @@ func (c *Collection) observeOnceSeqNo(
 }
 
 func (c *Collection) observeOne(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	docID string,
 	mt gocbcore.MutationToken,
 	replicaIdx int,
@@ -75,7 +75,8 @@ ObserveLoop:
 
 		didReplicate, didPersist, err := c.observeOnceSeqNo(tracectx, docID, mt, replicaIdx, cancelCh, timeout)
 		if err != nil {
-			logDebugf("ObserveOnce failed unexpected: %s", err)
+			logFieldsf(LogSubsystemKeyValue, LogDebug, withErrorLogFields(c.logFields("observeOnceSeqNo", docID), err),
+				"ObserveOnce failed unexpected: %s", err)
 			return
 		}
 
@@ -106,7 +107,7 @@ ObserveLoop:
 }
 
 func (c *Collection) waitForDurability(
-	tracectx requestSpanContext,
+	tracectx RequestSpanContext,
 	docID string,
 	mt gocbcore.MutationToken,
 	replicateTo uint,
@@ -14,6 +14,7 @@ import (
 type LookupInOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpanContext
 
 	// Internal: This should never be used and is not supported.
 	Internal struct {
@@ -27,7 +28,20 @@ func (c *Collection) LookupIn(id string, ops []LookupInSpec, opts *LookupInOptio
 		opts = &LookupInOptions{}
 	}
 
-	opm := c.newKvOpManager("LookupIn", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("LookupIn", id, ops, &interceptedOpts, &docOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var ops []LookupInSpec
+		if err := assignIntercepted(&ops, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.lookupIn(req.DocumentID, ops, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) lookupIn(id string, ops []LookupInSpec, opts *LookupInOptions) (docOut *LookupInResult, errOut error) {
+	opm := c.newKvOpManager("LookupIn", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -153,6 +167,7 @@ type MutateInOptions struct {
 	StoreSemantic   StoreSemantics
 	Timeout         time.Duration
 	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpanContext
 
 	// Internal: This should never be used and is not supported.
 	Internal struct {
@@ -166,7 +181,20 @@ func (c *Collection) MutateIn(id string, ops []MutateInSpec, opts *MutateInOptio
 		opts = &MutateInOptions{}
 	}
 
-	opm := c.newKvOpManager("MutateIn", nil)
+	interceptedOpts := *opts
+	errOut = c.interceptKV("MutateIn", id, ops, &interceptedOpts, &mutOut, func(col *Collection, req *KVInterceptorRequest) (interface{}, error) {
+		var ops []MutateInSpec
+		if err := assignIntercepted(&ops, req.Value, "value"); err != nil {
+			return nil, err
+		}
+
+		return col.mutateIn(req.DocumentID, ops, &interceptedOpts)
+	})
+	return
+}
+
+func (c *Collection) mutateIn(id string, ops []MutateInSpec, opts *MutateInOptions) (mutOut *MutateInResult, errOut error) {
+	opm := c.newKvOpManager("MutateIn", opts.ParentSpan)
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
@@ -44,6 +44,20 @@ func endpointStateToString(state EndpointState) string {
 	return ""
 }
 
+func circuitBreakerStateToString(state CircuitBreakerState) string {
+	switch state {
+	case CircuitBreakerStateDisabled:
+		return "disabled"
+	case CircuitBreakerStateClosed:
+		return "closed"
+	case CircuitBreakerStateHalfOpen:
+		return "half_open"
+	case CircuitBreakerStateOpen:
+		return "open"
+	}
+	return ""
+}
+
 func pingStateToString(state PingState) string {
 	switch state {
 	case PingStateOk:
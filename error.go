@@ -296,4 +296,39 @@ var (
 
 	// ErrNoResult occurs when no results are available to a query.
 	ErrNoResult = errors.New("no result was available")
+
+	// ErrRecordingNotFound occurs when replaying responses and no recording exists for a request.
+	ErrRecordingNotFound = errors.New("no recorded response was found for the request")
+
+	// ErrRetryBudgetExhausted occurs when an operation would have been retried but the retry budget
+	// was exhausted.  The error also wraps the error which caused the retry.
+	ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+	// ErrConcurrencyLimitReached occurs when an operation is not sent because too many operations are
+	// already in flight.
+	ErrConcurrencyLimitReached = errors.New("concurrency limit reached")
+
+	// ErrTooManyRows occurs when decoding all of the rows of a result which contains more rows than the
+	// maximum allowed.
+	ErrTooManyRows = errors.New("too many rows")
+
+	// ErrQueryTransactionFailed occurs when a statement within a query transaction failed, causing the
+	// transaction to be rolled back.
+	ErrQueryTransactionFailed = errors.New("query transaction failed")
+
+	// ErrQueryTransactionCompleted occurs when a query transaction is used after it has been committed or
+	// rolled back.
+	ErrQueryTransactionCompleted = errors.New("query transaction already completed")
+
+	// ErrQueryRequestNotFound occurs when cancelling a query request which is not running.
+	ErrQueryRequestNotFound = errors.New("query request not found")
+
+	// ErrQueryFunctionNotFound occurs when the requested query function could not be found.
+	ErrQueryFunctionNotFound = errors.New("query function not found")
+
+	// ErrQueryFunctionExists occurs when creating a query function which already exists.
+	ErrQueryFunctionExists = errors.New("query function already exists")
+
+	// ErrQueryFunctionLibraryNotFound occurs when the requested query function library could not be found.
+	ErrQueryFunctionLibraryNotFound = errors.New("query function library not found")
 )
@@ -22,13 +22,14 @@ func translateCoreAnalyticsErrorDesc(descs []gocbcore.AnalyticsErrorDesc) []Anal
 // AnalyticsError is the error type of all analytics query errors.
 // UNCOMMITTED: This API may change in the future.
 type AnalyticsError struct {
-	InnerError      error                `json:"-"`
-	Statement       string               `json:"statement,omitempty"`
-	ClientContextID string               `json:"client_context_id,omitempty"`
-	Errors          []AnalyticsErrorDesc `json:"errors,omitempty"`
-	Endpoint        string               `json:"endpoint,omitempty"`
-	RetryReasons    []RetryReason        `json:"retry_reasons,omitempty"`
-	RetryAttempts   uint32               `json:"retry_attempts,omitempty"`
+	InnerError           error                `json:"-"`
+	Statement            string               `json:"statement,omitempty"`
+	ClientContextID      string               `json:"client_context_id,omitempty"`
+	Errors               []AnalyticsErrorDesc `json:"errors,omitempty"`
+	Endpoint             string               `json:"endpoint,omitempty"`
+	RetryReasons         []RetryReason        `json:"retry_reasons,omitempty"`
+	RetryAttempts        uint32               `json:"retry_attempts,omitempty"`
+	RetryBudgetExhausted bool                 `json:"retry_budget_exhausted,omitempty"`
 }
 
 // Error returns the string representation of this error.
@@ -40,3 +41,22 @@ func (e AnalyticsError) Error() string {
 func (e AnalyticsError) Unwrap() error {
 	return e.InnerError
 }
+
+// Is reports whether target is ErrRetryBudgetExhausted and the error was caused by the retry budget.
+func (e AnalyticsError) Is(target error) bool {
+	return e.RetryBudgetExhausted && target == ErrRetryBudgetExhausted
+}
+
+func (e AnalyticsError) retryAttempts() uint32 {
+	return e.RetryAttempts
+}
+
+func (e AnalyticsError) retryReasons() []RetryReason {
+	return e.RetryReasons
+}
+
+func (e AnalyticsError) redacted() interface{} {
+	e.Statement = redactOptional(e.Statement, redactUserData)
+	e.Endpoint = redactOptional(e.Endpoint, redactSystemData)
+	return e
+}
@@ -0,0 +1,29 @@
+package gocb
+
+// ConcurrencyLimitError occurs when an operation is not sent because too many operations are already
+// in flight.
+// UNCOMMITTED: This API may change in the future.
+type ConcurrencyLimitError struct {
+	InnerError error `json:"-"`
+
+	// Limit is the limit which was reached, one of cluster, bucket or service.
+	Limit       string `json:"limit,omitempty"`
+	Service     string `json:"service,omitempty"`
+	BucketName  string `json:"bucket,omitempty"`
+	MaxInFlight int    `json:"max_in_flight,omitempty"`
+}
+
+// Error returns the string representation of a concurrency limit error.
+func (err ConcurrencyLimitError) Error() string {
+	return err.InnerError.Error() + " | " + serializeWrappedError(err)
+}
+
+// Unwrap returns the underlying reason for the error
+func (err ConcurrencyLimitError) Unwrap() error {
+	return err.InnerError
+}
+
+func (err ConcurrencyLimitError) redacted() interface{} {
+	err.BucketName = redactOptional(err.BucketName, redactMetaData)
+	return err
+}
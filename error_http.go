@@ -8,11 +8,12 @@ import (
 // HTTPError is the error type of management HTTP errors.
 // UNCOMMITTED: This API may change in the future.
 type HTTPError struct {
-	InnerError    error         `json:"-"`
-	UniqueID      string        `json:"unique_id,omitempty"`
-	Endpoint      string        `json:"endpoint,omitempty"`
-	RetryReasons  []RetryReason `json:"retry_reasons,omitempty"`
-	RetryAttempts uint32        `json:"retry_attempts,omitempty"`
+	InnerError           error         `json:"-"`
+	UniqueID             string        `json:"unique_id,omitempty"`
+	Endpoint             string        `json:"endpoint,omitempty"`
+	RetryReasons         []RetryReason `json:"retry_reasons,omitempty"`
+	RetryAttempts        uint32        `json:"retry_attempts,omitempty"`
+	RetryBudgetExhausted bool          `json:"retry_budget_exhausted,omitempty"`
 }
 
 // Error returns the string representation of this error.
@@ -25,9 +26,22 @@ func (e HTTPError) Unwrap() error {
 	return e.InnerError
 }
 
+// Is reports whether target is ErrRetryBudgetExhausted and the error was caused by the retry budget.
+func (e HTTPError) Is(target error) bool {
+	return e.RetryBudgetExhausted && target == ErrRetryBudgetExhausted
+}
+
+func (e HTTPError) retryAttempts() uint32 {
+	return e.RetryAttempts
+}
+
+func (e HTTPError) retryReasons() []RetryReason {
+	return e.RetryReasons
+}
+
 func makeGenericHTTPError(baseErr error, req *gocbcore.HTTPRequest, resp *gocbcore.HTTPResponse) error {
 	if baseErr == nil {
-		logErrorf("makeGenericHTTPError got an empty error")
+		logFieldsf(LogSubsystemSDK, LogError, nil, "makeGenericHTTPError got an empty error")
 		baseErr = errors.New("unknown error")
 	}
 
@@ -48,7 +62,7 @@ func makeGenericHTTPError(baseErr error, req *gocbcore.HTTPRequest, resp *gocbco
 
 func makeGenericMgmtError(baseErr error, req *mgmtRequest, resp *mgmtResponse) error {
 	if baseErr == nil {
-		logErrorf("makeGenericMgmtError got an empty error")
+		logFieldsf(LogSubsystemSDK, LogError, nil, "makeGenericMgmtError got an empty error")
 		baseErr = errors.New("unknown error")
 	}
 
@@ -70,3 +84,8 @@ func makeGenericMgmtError(baseErr error, req *mgmtRequest, resp *mgmtResponse) e
 func makeMgmtBadStatusError(message string, req *mgmtRequest, resp *mgmtResponse) error {
 	return makeGenericMgmtError(errors.New(message), req, resp)
 }
+
+func (e HTTPError) redacted() interface{} {
+	e.Endpoint = redactOptional(e.Endpoint, redactSystemData)
+	return e
+}
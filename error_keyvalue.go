@@ -2,25 +2,29 @@ package gocb
 
 import "github.com/couchbase/gocbcore/v9/memd"
 
-// KeyValueError wraps key-value errors that occur within the SDK.
+// KeyValueError wraps key-value errors that occur within the SDK.  The DocumentID is only included in the
+// string representation of the error when log redaction is enabled, in which case it is tagged as user data.
 // UNCOMMITTED: This API may change in the future.
 type KeyValueError struct {
-	InnerError         error           `json:"-"`
-	StatusCode         memd.StatusCode `json:"status_code,omitempty"`
-	BucketName         string          `json:"bucket,omitempty"`
-	ScopeName          string          `json:"scope,omitempty"`
-	CollectionName     string          `json:"collection,omitempty"`
-	CollectionID       uint32          `json:"collection_id,omitempty"`
-	ErrorName          string          `json:"error_name,omitempty"`
-	ErrorDescription   string          `json:"error_description,omitempty"`
-	Opaque             uint32          `json:"opaque,omitempty"`
-	Context            string          `json:"context,omitempty"`
-	Ref                string          `json:"ref,omitempty"`
-	RetryReasons       []RetryReason   `json:"retry_reasons,omitempty"`
-	RetryAttempts      uint32          `json:"retry_attempts,omitempty"`
-	LastDispatchedTo   string          `json:"last_dispatched_to,omitempty"`
-	LastDispatchedFrom string          `json:"last_dispatched_from,omitempty"`
-	LastConnectionID   string          `json:"last_connection_id,omitempty"`
+	InnerError           error           `json:"-"`
+	StatusCode           memd.StatusCode `json:"status_code,omitempty"`
+	DocumentID           string          `json:"-"`
+	BucketName           string          `json:"bucket,omitempty"`
+	ScopeName            string          `json:"scope,omitempty"`
+	CollectionName       string          `json:"collection,omitempty"`
+	CollectionID         uint32          `json:"collection_id,omitempty"`
+	ErrorName            string          `json:"error_name,omitempty"`
+	ErrorDescription     string          `json:"error_description,omitempty"`
+	Opaque               uint32          `json:"opaque,omitempty"`
+	Context              string          `json:"context,omitempty"`
+	Ref                  string          `json:"ref,omitempty"`
+	RetryReasons         []RetryReason   `json:"retry_reasons,omitempty"`
+	RetryAttempts        uint32          `json:"retry_attempts,omitempty"`
+	RetryHistory         []RetryDecision `json:"retry_history,omitempty"`
+	RetryBudgetExhausted bool            `json:"retry_budget_exhausted,omitempty"`
+	LastDispatchedTo     string          `json:"last_dispatched_to,omitempty"`
+	LastDispatchedFrom   string          `json:"last_dispatched_from,omitempty"`
+	LastConnectionID     string          `json:"last_connection_id,omitempty"`
 }
 
 // Error returns the string representation of a kv error.
@@ -32,3 +36,34 @@ func (e KeyValueError) Error() string {
 func (e KeyValueError) Unwrap() error {
 	return e.InnerError
 }
+
+// Is reports whether target is ErrRetryBudgetExhausted and the error was caused by the retry budget.
+func (e KeyValueError) Is(target error) bool {
+	return e.RetryBudgetExhausted && target == ErrRetryBudgetExhausted
+}
+
+func (e KeyValueError) retryAttempts() uint32 {
+	return e.RetryAttempts
+}
+
+func (e KeyValueError) retryReasons() []RetryReason {
+	return e.RetryReasons
+}
+
+// redactedKeyValueError adds the document ID, which is otherwise omitted, to a redacted KeyValueError.
+type redactedKeyValueError struct {
+	KeyValueError
+	DocumentID string `json:"document_id,omitempty"`
+}
+
+func (e KeyValueError) redacted() interface{} {
+	e.BucketName = redactOptional(e.BucketName, redactMetaData)
+	e.ScopeName = redactOptional(e.ScopeName, redactMetaData)
+	e.CollectionName = redactOptional(e.CollectionName, redactMetaData)
+	e.LastDispatchedTo = redactOptional(e.LastDispatchedTo, redactSystemData)
+	e.LastDispatchedFrom = redactOptional(e.LastDispatchedFrom, redactSystemData)
+	return redactedKeyValueError{
+		KeyValueError: e,
+		DocumentID:    redactOptional(e.DocumentID, redactUserData),
+	}
+}
@@ -22,13 +22,15 @@ func translateCoreQueryErrorDesc(descs []gocbcore.N1QLErrorDesc) []QueryErrorDes
 // QueryError is the error type of all query errors.
 // UNCOMMITTED: This API may change in the future.
 type QueryError struct {
-	InnerError      error            `json:"-"`
-	Statement       string           `json:"statement,omitempty"`
-	ClientContextID string           `json:"client_context_id,omitempty"`
-	Errors          []QueryErrorDesc `json:"errors,omitempty"`
-	Endpoint        string           `json:"endpoint,omitempty"`
-	RetryReasons    []RetryReason    `json:"retry_reasons,omitempty"`
-	RetryAttempts   uint32           `json:"retry_attempts,omitempty"`
+	InnerError           error            `json:"-"`
+	Statement            string           `json:"statement,omitempty"`
+	ClientContextID      string           `json:"client_context_id,omitempty"`
+	Errors               []QueryErrorDesc `json:"errors,omitempty"`
+	Endpoint             string           `json:"endpoint,omitempty"`
+	RetryReasons         []RetryReason    `json:"retry_reasons,omitempty"`
+	RetryAttempts        uint32           `json:"retry_attempts,omitempty"`
+	RetryHistory         []RetryDecision  `json:"retry_history,omitempty"`
+	RetryBudgetExhausted bool             `json:"retry_budget_exhausted,omitempty"`
 }
 
 // Error returns the string representation of this error.
@@ -40,3 +42,22 @@ func (e QueryError) Error() string {
 func (e QueryError) Unwrap() error {
 	return e.InnerError
 }
+
+// Is reports whether target is ErrRetryBudgetExhausted and the error was caused by the retry budget.
+func (e QueryError) Is(target error) bool {
+	return e.RetryBudgetExhausted && target == ErrRetryBudgetExhausted
+}
+
+func (e QueryError) retryAttempts() uint32 {
+	return e.RetryAttempts
+}
+
+func (e QueryError) retryReasons() []RetryReason {
+	return e.RetryReasons
+}
+
+func (e QueryError) redacted() interface{} {
+	e.Statement = redactOptional(e.Statement, redactUserData)
+	e.Endpoint = redactOptional(e.Endpoint, redactSystemData)
+	return e
+}
@@ -0,0 +1,42 @@
+package gocb
+
+import "errors"
+
+// QueryTransactionError occurs when a query transaction cannot be used, either because a statement
+// within it failed or because it has already been committed or rolled back.
+// UNCOMMITTED: This API may change in the future.
+type QueryTransactionError struct {
+	InnerError    error  `json:"-"`
+	TransactionID string `json:"txid,omitempty"`
+	Endpoint      string `json:"endpoint,omitempty"`
+
+	// RolledBack is whether the transaction has been rolled back.
+	RolledBack bool `json:"rolled_back"`
+
+	// Cause is the error which failed the transaction, if any.
+	Cause error `json:"-"`
+}
+
+// Error returns the string representation of a query transaction error.
+func (err QueryTransactionError) Error() string {
+	if err.Cause == nil {
+		return err.InnerError.Error() + " | " + serializeWrappedError(err)
+	}
+	return err.InnerError.Error() + " | " + serializeWrappedError(err) + " | " + err.Cause.Error()
+}
+
+// Unwrap returns the underlying reason for the error
+func (err QueryTransactionError) Unwrap() error {
+	return err.InnerError
+}
+
+// Is returns whether target matches the error which failed the transaction, allowing the cause of a
+// failed transaction to be checked using errors.Is.
+func (err QueryTransactionError) Is(target error) bool {
+	return err.Cause != nil && errors.Is(err.Cause, target)
+}
+
+func (err QueryTransactionError) redacted() interface{} {
+	err.Endpoint = redactOptional(err.Endpoint, redactSystemData)
+	return err
+}
@@ -21,3 +21,18 @@ func (e SearchError) Error() string {
 func (e SearchError) Unwrap() error {
 	return e.InnerError
 }
+
+func (e SearchError) retryAttempts() uint32 {
+	return e.RetryAttempts
+}
+
+func (e SearchError) retryReasons() []RetryReason {
+	return e.RetryReasons
+}
+
+func (e SearchError) redacted() interface{} {
+	e.Query = redactUserDataJSON(e.Query)
+	e.Endpoint = redactOptional(e.Endpoint, redactSystemData)
+	e.IndexName = redactOptional(e.IndexName, redactMetaData)
+	return e
+}
@@ -17,18 +17,20 @@ type TimeoutError struct {
 	LastDispatchedTo   string
 	LastDispatchedFrom string
 	LastConnectionID   string
+	RetryHistory       []RetryDecision
 }
 
 type timeoutError struct {
-	InnerError         error    `json:"-"`
-	OperationID        string   `json:"s,omitempty"`
-	Opaque             string   `json:"i,omitempty"`
-	TimeObserved       uint64   `json:"t,omitempty"`
-	RetryReasons       []string `json:"rr,omitempty"`
-	RetryAttempts      uint32   `json:"ra,omitempty"`
-	LastDispatchedTo   string   `json:"r,omitempty"`
-	LastDispatchedFrom string   `json:"l,omitempty"`
-	LastConnectionID   string   `json:"c,omitempty"`
+	InnerError         error           `json:"-"`
+	OperationID        string          `json:"s,omitempty"`
+	Opaque             string          `json:"i,omitempty"`
+	TimeObserved       uint64          `json:"t,omitempty"`
+	RetryReasons       []string        `json:"rr,omitempty"`
+	RetryAttempts      uint32          `json:"ra,omitempty"`
+	LastDispatchedTo   string          `json:"r,omitempty"`
+	LastDispatchedFrom string          `json:"l,omitempty"`
+	LastConnectionID   string          `json:"c,omitempty"`
+	RetryHistory       []RetryDecision `json:"rh,omitempty"`
 }
 
 // MarshalJSON implements the Marshaler interface.
@@ -48,6 +50,7 @@ func (err *TimeoutError) MarshalJSON() ([]byte, error) {
 		LastDispatchedTo:   err.LastDispatchedTo,
 		LastDispatchedFrom: err.LastDispatchedFrom,
 		LastConnectionID:   err.LastConnectionID,
+		RetryHistory:       err.RetryHistory,
 	}
 
 	return json.Marshal(toMarshal)
@@ -85,3 +88,17 @@ func (err TimeoutError) Error() string {
 func (err TimeoutError) Unwrap() error {
 	return err.InnerError
 }
+
+func (err TimeoutError) retryAttempts() uint32 {
+	return err.RetryAttempts
+}
+
+func (err TimeoutError) retryReasons() []RetryReason {
+	return err.RetryReasons
+}
+
+func (err TimeoutError) redacted() interface{} {
+	err.LastDispatchedTo = redactOptional(err.LastDispatchedTo, redactSystemData)
+	err.LastDispatchedFrom = redactOptional(err.LastDispatchedFrom, redactSystemData)
+	return err
+}
@@ -40,3 +40,18 @@ func (e ViewError) Error() string {
 func (e ViewError) Unwrap() error {
 	return e.InnerError
 }
+
+func (e ViewError) retryAttempts() uint32 {
+	return e.RetryAttempts
+}
+
+func (e ViewError) retryReasons() []RetryReason {
+	return e.RetryReasons
+}
+
+func (e ViewError) redacted() interface{} {
+	e.DesignDocumentName = redactOptional(e.DesignDocumentName, redactMetaData)
+	e.ViewName = redactOptional(e.ViewName, redactMetaData)
+	e.Endpoint = redactOptional(e.Endpoint, redactSystemData)
+	return e
+}
@@ -7,9 +7,15 @@ import (
 )
 
 func serializeWrappedError(err error) string {
-	errBytes, serErr := json.Marshal(err)
+	var errBytes []byte
+	var serErr error
+	if redactable, ok := err.(redactableError); ok && globalLogRedactionLevel != RedactNone {
+		errBytes, serErr = marshalRedactedJSON(redactable.redacted())
+	} else {
+		errBytes, serErr = json.Marshal(err)
+	}
 	if serErr != nil {
-		logErrorf("failed to serialize error to json: %s", serErr.Error())
+		logFieldsf(LogSubsystemSDK, LogError, nil, "failed to serialize error to json: %s", serErr.Error())
 	}
 	return string(errBytes)
 }
@@ -106,7 +112,12 @@ func maybeEnhanceCoreErr(err error) error {
 }
 
 func maybeEnhanceKVErr(err error, bucketName, scopeName, collName, docKey string) error {
-	return maybeEnhanceCoreErr(err)
+	enhancedErr := maybeEnhanceCoreErr(err)
+	if kvErr, ok := enhancedErr.(*KeyValueError); ok {
+		kvErr.DocumentID = docKey
+	}
+
+	return enhancedErr
 }
 
 func maybeEnhanceCollKVErr(err error, bucket kvProvider, coll *Collection, docKey string) error {
@@ -0,0 +1,285 @@
+package gocb
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	cbsearch "github.com/couchbase/gocb/v2/search"
+)
+
+// KVInterceptorRequest describes a key-value or sub-document operation which is passing through
+// the interceptor chain.  Interceptors may modify the DocumentID and Value fields as well as the
+// contents of the options block before invoking the next interceptor in the chain.
+// UNCOMMITTED: This API may change in the future.
+type KVInterceptorRequest struct {
+	// Operation is the name of the operation being performed, e.g. Get, Upsert or MutateIn.
+	Operation string
+
+	BucketName     string
+	ScopeName      string
+	CollectionName string
+	DocumentID     string
+
+	// Value is the operation specific argument.  This is the document content for Insert, Upsert
+	// and Replace, the bytes for Append and Prepend, the specs for LookupIn and MutateIn, the expiry
+	// for GetAndTouch and Touch, the lock time for GetAndLock, the Cas for Unlock and the list of
+	// operations for Do.  It is nil for all other operations.  If replaced then the new value must
+	// be of the same type.
+	Value interface{}
+
+	// Options is a pointer to a copy of the options block for the operation, e.g. *GetOptions.  It
+	// is never nil.  Each operation receives its own copy, which is the one used to dispatch the
+	// operation, so changes to it are never visible to the caller.  The pointer itself must not be
+	// replaced.
+	Options interface{}
+}
+
+// KVInvoker invokes the remainder of a key-value interceptor chain, eventually dispatching the
+// operation.  The returned value is the result of the operation, e.g. *GetResult for Get.
+// UNCOMMITTED: This API may change in the future.
+type KVInvoker func(req *KVInterceptorRequest) (interface{}, error)
+
+// KVInterceptor is invoked for every key-value and sub-document operation.  An interceptor must
+// either call next to continue the chain, or short-circuit the operation by returning its own
+// result or error.  Results returned when short-circuiting must be of the type that the
+// operation returns, e.g. *MutationResult for Upsert.
+// UNCOMMITTED: This API may change in the future.
+type KVInterceptor func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error)
+
+func chainKVInterceptors(interceptors []KVInterceptor, invoker KVInvoker) KVInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		next := invoker
+		invoker = func(req *KVInterceptorRequest) (interface{}, error) {
+			return interceptor(req, next)
+		}
+	}
+
+	return invoker
+}
+
+// kvOperationInvoker dispatches an operation which has passed through the interceptor chain.  The
+// operation must be dispatched with col, which records the retries of the operation.
+type kvOperationInvoker func(col *Collection, req *KVInterceptorRequest) (interface{}, error)
+
+// interceptKV runs an operation through the collections interceptor chain, storing the result of
+// the chain into resultPtr.  The outcome of the operation is recorded with the collections meter.
+func (c *Collection) interceptKV(
+	operation, id string,
+	value, options interface{},
+	resultPtr interface{},
+	invoker kvOperationInvoker,
+) (errOut error) {
+	start := time.Now()
+	history := &retryHistory{}
+	defer func() {
+		errOut = c.retryBudget.maybeMarkExhausted(errOut)
+		c.retryBudget.recordOutcome(errOut)
+		recordOperationMetric(c.meter, ServiceTypeKeyValue, operation, start, errOut, history)
+		maybeRecordOrphanTimeout(errOut)
+	}()
+
+	timeout := c.operationTimeout(operation, value, options)
+	release, err := c.admission.admit(ServiceTypeKeyValue, c.bucketName(), start.Add(timeout))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	req := &KVInterceptorRequest{
+		Operation:      operation,
+		BucketName:     c.bucketName(),
+		ScopeName:      c.ScopeName(),
+		CollectionName: c.Name(),
+		DocumentID:     id,
+		Value:          value,
+		Options:        options,
+	}
+
+	col := *c
+	col.retryStrategyWrapper = c.retryStrategyWrapper.withHistory(history)
+	col.operationStart = start
+
+	res, err := chainKVInterceptors(c.kvInterceptors, func(req *KVInterceptorRequest) (interface{}, error) {
+		return invoker(&col, req)
+	})(req)
+	if assignErr := assignIntercepted(resultPtr, res, "result"); assignErr != nil {
+		return assignErr
+	}
+
+	return err
+}
+
+// assignIntercepted copies val into the value pointed to by ptr, failing if an interceptor has
+// supplied a value of an unexpected type.  A nil val leaves ptr untouched.
+func assignIntercepted(ptr interface{}, val interface{}, what string) error {
+	if ptr == nil || val == nil {
+		return nil
+	}
+
+	dst := reflect.ValueOf(ptr).Elem()
+	src := reflect.ValueOf(val)
+	if !src.Type().AssignableTo(dst.Type()) {
+		return makeInvalidArgumentsError(
+			fmt.Sprintf("interceptor supplied %s of type %T but expected %s", what, val, dst.Type()))
+	}
+
+	dst.Set(src)
+	return nil
+}
+
+// ServiceInterceptorRequest describes a query, analytics, search or view request which is passing
+// through the service interceptors.  Interceptors may modify the statement, search query and the
+// contents of the options block before the request is dispatched.
+// UNCOMMITTED: This API may change in the future.
+type ServiceInterceptorRequest struct {
+	// Service is one of ServiceTypeQuery, ServiceTypeAnalytics, ServiceTypeSearch or ServiceTypeViews.
+	Service ServiceType
+
+	// Statement is the statement being executed by a query or analytics request.
+	Statement string
+
+	// IndexName and Query are the index and query being executed by a search request.
+	IndexName string
+	Query     cbsearch.Query
+
+	// BucketName, DesignDocumentName and ViewName identify the view targeted by a view request.
+	BucketName         string
+	DesignDocumentName string
+	ViewName           string
+
+	// Options is a copy of the options block for the request, i.e. one of *QueryOptions,
+	// *AnalyticsOptions, *SearchOptions or *ViewOptions.  It is never nil.  The options may be
+	// modified but the pointer itself must not be replaced.
+	Options interface{}
+}
+
+// ServiceInterceptor is invoked for every Query, AnalyticsQuery, SearchQuery and ViewQuery request.
+// UNCOMMITTED: This API may change in the future.
+type ServiceInterceptor interface {
+	// BeforeRequest is invoked before a request is dispatched.  Returning an error rejects the
+	// request and the error is returned to the caller.
+	BeforeRequest(req *ServiceInterceptorRequest) error
+
+	// AfterRequest is invoked once a request has completed, either because it failed to dispatch or
+	// because its results were fully read or closed.  The meta-data is one of *QueryMetaData,
+	// *AnalyticsMetaData, *SearchMetaData or *ViewMetaData.  It is nil when err is not nil, and also when
+	// the results were closed before being fully read, as the meta-data is not available in that case.
+	AfterRequest(req *ServiceInterceptorRequest, metaData interface{}, err error)
+}
+
+type serviceInterception struct {
+	req          *ServiceInterceptorRequest
+	interceptors []ServiceInterceptor
+	completed    bool
+}
+
+// interceptServiceRequest invokes BeforeRequest on each interceptor in order.  If an interceptor
+// rejects the request then AfterRequest is invoked on any interceptors which had already accepted it.
+func interceptServiceRequest(interceptors []ServiceInterceptor, req *ServiceInterceptorRequest) (*serviceInterception, error) {
+	for i, interceptor := range interceptors {
+		err := interceptor.BeforeRequest(req)
+		if err != nil {
+			accepted := &serviceInterception{
+				req:          req,
+				interceptors: interceptors[:i],
+			}
+			accepted.complete(nil, err)
+			return nil, err
+		}
+	}
+
+	return &serviceInterception{
+		req:          req,
+		interceptors: interceptors,
+	}, nil
+}
+
+// complete invokes AfterRequest on each interceptor in reverse order.  It is safe to call more than once.
+func (si *serviceInterception) complete(metaData interface{}, err error) {
+	if si.completed {
+		return
+	}
+	si.completed = true
+
+	if err != nil {
+		metaData = nil
+	}
+
+	for i := len(si.interceptors) - 1; i >= 0; i-- {
+		si.interceptors[i].AfterRequest(si.req, metaData, err)
+	}
+}
+
+// completionRowReader invokes onComplete once the wrapped stream has been fully read or closed, indicating
+// whether it was closed before being fully read.
+type completionRowReader struct {
+	reader     streamingRowReader
+	onComplete func(closedEarly bool)
+	completed  bool
+}
+
+func (r *completionRowReader) NextRow() []byte {
+	rowBytes := r.reader.NextRow()
+	if rowBytes == nil {
+		r.complete(false)
+	}
+
+	return rowBytes
+}
+
+func (r *completionRowReader) Err() error {
+	return r.reader.Err()
+}
+
+func (r *completionRowReader) MetaData() ([]byte, error) {
+	return r.reader.MetaData()
+}
+
+func (r *completionRowReader) Close() error {
+	err := r.reader.Close()
+	r.complete(true)
+	return err
+}
+
+func (r *completionRowReader) complete(closedEarly bool) {
+	if r.completed {
+		return
+	}
+	r.completed = true
+
+	r.onComplete(closedEarly)
+}
+
+type completionQueryRowReader struct {
+	*completionRowReader
+	queryReader queryRowReader
+}
+
+func (r *completionQueryRowReader) PreparedName() (string, error) {
+	return r.queryReader.PreparedName()
+}
+
+// completionReader wraps reader so that the interception is completed once the results have been
+// fully read or closed.
+func (si *serviceInterception) completionReader(
+	reader streamingRowReader,
+	metaDataFn func() (interface{}, error),
+) *completionRowReader {
+	return &completionRowReader{
+		reader: reader,
+		onComplete: func(closedEarly bool) {
+			if err := reader.Err(); err != nil {
+				si.complete(nil, err)
+				return
+			}
+			if closedEarly {
+				si.complete(nil, nil)
+				return
+			}
+
+			si.complete(metaDataFn())
+		},
+	}
+}
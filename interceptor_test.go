@@ -0,0 +1,331 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/couchbase/gocb/v2/search"
+	gocbcore "github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) interceptedCollection(provider kvProvider, interceptors ...KVInterceptor) *Collection {
+	return &Collection{
+		bucket:         &Bucket{bucketName: "mock"},
+		collectionName: "_default",
+		scope:          "_default",
+
+		getKvProvider: suite.kvProvider(provider, nil),
+		timeoutsConfig: kvTimeoutsConfig{
+			KVTimeout: 2500 * time.Millisecond,
+		},
+		transcoder:           NewJSONTranscoder(),
+		tracer:               &noopTracer{},
+		retryStrategyWrapper: newRetryStrategyWrapper(NewBestEffortRetryStrategy(nil)),
+		kvInterceptors:       interceptors,
+	}
+}
+
+func (suite *UnitTestSuite) TestKVInterceptorChainOrderAndModify() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.SetOptions)
+			suite.Assert().Equal([]byte("tenant::key"), opts.Key)
+			suite.Assert().Equal([]byte(`"modified"`), opts.Value)
+			suite.Assert().Equal(uint32(10), opts.Expiry)
+
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{Cas: 123}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	var order []string
+	var seenRes interface{}
+	col := suite.interceptedCollection(provider,
+		func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error) {
+			order = append(order, "first")
+			suite.Assert().Equal("Upsert", req.Operation)
+			suite.Assert().Equal("mock", req.BucketName)
+			suite.Assert().Equal("_default", req.ScopeName)
+			suite.Assert().Equal("_default", req.CollectionName)
+
+			req.DocumentID = "tenant::" + req.DocumentID
+			res, err := next(req)
+			seenRes = res
+			return res, err
+		},
+		func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error) {
+			order = append(order, "second")
+			suite.Assert().Equal("tenant::key", req.DocumentID)
+
+			req.Value = "modified"
+			req.Options.(*UpsertOptions).Expiry = 10 * time.Second
+			return next(req)
+		},
+	)
+
+	opts := &UpsertOptions{}
+	res, err := col.Upsert("key", "original", opts)
+	suite.Require().Nil(err, err)
+	suite.Assert().Zero(opts.Expiry)
+	suite.Assert().Equal(Cas(123), res.Cas())
+	suite.Assert().Equal([]string{"first", "second"}, order)
+	suite.Assert().Equal(res, seenRes)
+}
+
+func (suite *UnitTestSuite) TestKVInterceptorShortCircuit() {
+	provider := new(mockKvProvider)
+	errDenied := errors.New("tenant denied")
+
+	col := suite.interceptedCollection(provider,
+		func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error) {
+			if req.DocumentID == "denied" {
+				return nil, errDenied
+			}
+
+			return &GetResult{Result: Result{cas: 99}}, nil
+		},
+	)
+
+	res, err := col.Get("denied", nil)
+	suite.Assert().Equal(errDenied, err)
+	suite.Assert().Nil(res)
+
+	res, err = col.Get("cached", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(Cas(99), res.Cas())
+
+	provider.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestKVInterceptorInvalidShortCircuitResult() {
+	col := suite.interceptedCollection(new(mockKvProvider),
+		func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error) {
+			return &MutationResult{}, nil
+		},
+	)
+
+	res, err := col.Get("key", nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+	suite.Assert().Nil(res)
+}
+
+func (suite *UnitTestSuite) TestKVInterceptorInvalidValue() {
+	col := suite.interceptedCollection(new(mockKvProvider),
+		func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error) {
+			req.Value = "not a duration"
+			return next(req)
+		},
+	)
+
+	_, err := col.Touch("key", time.Second, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestKVInterceptorSeesErrors() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProvider)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+			cb(nil, gocbcore.ErrDocumentNotFound)
+		}).
+		Return(pendingOp, nil)
+
+	var seenErr error
+	var seenOps []LookupInSpec
+	col := suite.interceptedCollection(provider,
+		func(req *KVInterceptorRequest, next KVInvoker) (interface{}, error) {
+			seenOps = req.Value.([]LookupInSpec)
+			_, err := next(req)
+			seenErr = err
+			return nil, err
+		},
+	)
+
+	ops := []LookupInSpec{GetSpec("name", nil)}
+	_, err := col.LookupIn("key", ops, nil)
+	suite.Assert().True(errors.Is(err, ErrDocumentNotFound))
+	suite.Assert().True(errors.Is(seenErr, ErrDocumentNotFound))
+	suite.Assert().Equal(ops, seenOps)
+}
+
+type testServiceInterceptor struct {
+	before func(req *ServiceInterceptorRequest) error
+	after  func(req *ServiceInterceptorRequest, metaData interface{}, err error)
+}
+
+func (i *testServiceInterceptor) BeforeRequest(req *ServiceInterceptorRequest) error {
+	if i.before == nil {
+		return nil
+	}
+	return i.before(req)
+}
+
+func (i *testServiceInterceptor) AfterRequest(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+	if i.after != nil {
+		i.after(req, metaData, err)
+	}
+}
+
+func (suite *UnitTestSuite) TestServiceInterceptorQuery() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, func(args mock.Arguments) {
+		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
+
+		var actualOptions map[string]interface{}
+		err := json.Unmarshal(opts.Payload, &actualOptions)
+		suite.Require().Nil(err)
+
+		suite.Assert().Equal("/* audited */ SELECT * FROM dataset", actualOptions["statement"])
+		suite.Assert().Equal("tagged-context", actualOptions["client_context_id"])
+	})
+
+	var afterCalls int
+	var afterMeta interface{}
+	cluster.serviceInterceptors = []ServiceInterceptor{
+		&testServiceInterceptor{
+			before: func(req *ServiceInterceptorRequest) error {
+				suite.Assert().Equal(ServiceTypeQuery, req.Service)
+				req.Statement = "/* audited */ " + req.Statement
+				req.Options.(*QueryOptions).ClientContextID = "tagged-context"
+				return nil
+			},
+			after: func(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+				afterCalls++
+				afterMeta = metaData
+				suite.Assert().Nil(err)
+			},
+		},
+	}
+
+	opts := &QueryOptions{Adhoc: true}
+	result, err := cluster.Query("SELECT * FROM dataset", opts)
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(opts.ClientContextID)
+	suite.Assert().Zero(afterCalls)
+
+	suite.assertQueryBeerResult(dataset, result)
+	suite.Require().Nil(result.Close())
+
+	suite.Assert().Equal(1, afterCalls)
+	if suite.Assert().IsType(&QueryMetaData{}, afterMeta) {
+		suite.Assert().Equal(dataset.RequestID, afterMeta.(*QueryMetaData).RequestID)
+	}
+}
+
+func (suite *UnitTestSuite) TestServiceInterceptorReject() {
+	errRejected := errors.New("statement not allowed")
+
+	var firstAfterErr error
+	var secondAfterCalled, thirdBeforeCalled bool
+
+	cluster := suite.newCluster(new(mockConnectionManager))
+	cluster.serviceInterceptors = []ServiceInterceptor{
+		&testServiceInterceptor{
+			after: func(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+				firstAfterErr = err
+				suite.Assert().Nil(metaData)
+			},
+		},
+		&testServiceInterceptor{
+			before: func(req *ServiceInterceptorRequest) error {
+				return errRejected
+			},
+			after: func(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+				secondAfterCalled = true
+			},
+		},
+		&testServiceInterceptor{
+			before: func(req *ServiceInterceptorRequest) error {
+				thirdBeforeCalled = true
+				return nil
+			},
+		},
+	}
+
+	result, err := cluster.AnalyticsQuery("DROP DATAVERSE Default", nil)
+	suite.Assert().Equal(errRejected, err)
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errRejected, firstAfterErr)
+	suite.Assert().False(secondAfterCalled)
+	suite.Assert().False(thirdBeforeCalled)
+}
+
+func (suite *UnitTestSuite) TestServiceInterceptorDispatchError() {
+	retErr := errors.New("an error")
+	provider := new(mockSearchProvider)
+	provider.
+		On("SearchQuery", mock.AnythingOfType("gocbcore.SearchQueryOptions")).
+		Return(nil, retErr)
+
+	cli := new(mockConnectionManager)
+	cli.On("getSearchProvider").Return(provider, nil)
+
+	var afterErr error
+	cluster := suite.newCluster(cli)
+	cluster.serviceInterceptors = []ServiceInterceptor{
+		&testServiceInterceptor{
+			before: func(req *ServiceInterceptorRequest) error {
+				suite.Assert().Equal(ServiceTypeSearch, req.Service)
+				suite.Assert().Equal("searchy", req.IndexName)
+				return nil
+			},
+			after: func(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+				afterErr = err
+			},
+		},
+	}
+
+	_, err := cluster.SearchQuery("searchy", search.NewMatchAllQuery(), nil)
+	suite.Assert().Equal(retErr, err)
+	suite.Assert().Equal(retErr, afterErr)
+}
+
+func (suite *UnitTestSuite) TestServiceInterceptorClosedEarly() {
+	reader := &mockQueryRowReaderRows{
+		Rows:                   [][]byte{[]byte(`1`), []byte(`2`)},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Meta: []byte(`{"requestID":"1"}`)},
+	}
+	cluster := suite.queryCluster(false, reader, nil)
+
+	var afterCalls int
+	var afterMeta interface{}
+	var afterErr error
+	cluster.serviceInterceptors = []ServiceInterceptor{
+		&testServiceInterceptor{
+			after: func(req *ServiceInterceptorRequest, metaData interface{}, err error) {
+				afterCalls++
+				afterMeta = metaData
+				afterErr = err
+			},
+		},
+	}
+
+	result, err := cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+	suite.Require().True(result.Next())
+	suite.Require().Nil(result.Close())
+
+	suite.Assert().Equal(1, afterCalls)
+	suite.Assert().Nil(afterMeta)
+	suite.Assert().Nil(afterErr)
+}
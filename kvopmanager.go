@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"reflect"
 	"time"
 
 	gocbcore "github.com/couchbase/gocbcore/v9"
@@ -17,7 +18,8 @@ type kvOpManager struct {
 	wasResolved   bool
 	mutationToken *MutationToken
 
-	span            requestSpan
+	span            RequestSpan
+	operation       string
 	documentID      string
 	transcoder      Transcoder
 	timeout         time.Duration
@@ -35,7 +37,8 @@ func (m *kvOpManager) getTimeout() time.Duration {
 	if m.timeout > 0 {
 		if m.durabilityLevel > 0 && m.timeout < durabilityTimeoutFloor {
 			m.timeout = durabilityTimeoutFloor
-			logWarnf("Durable operation in use so timeout value coerced up to %s", m.timeout.String())
+			logFieldsf(LogSubsystemKeyValue, LogWarn, m.parent.logFields(m.operation, m.documentID),
+				"Durable operation in use so timeout value coerced up to %s", m.timeout.String())
 		}
 		return m.timeout
 	}
@@ -47,7 +50,8 @@ func (m *kvOpManager) getTimeout() time.Duration {
 
 	if m.durabilityLevel > 0 && defaultTimeout < durabilityTimeoutFloor {
 		defaultTimeout = durabilityTimeoutFloor
-		logWarnf("Durable operation in user so timeout value coerced up to %s", defaultTimeout.String())
+		logFieldsf(LogSubsystemKeyValue, LogWarn, m.parent.logFields(m.operation, m.documentID),
+			"Durable operation in user so timeout value coerced up to %s", defaultTimeout.String())
 	}
 
 	return defaultTimeout
@@ -81,7 +85,7 @@ func (m *kvOpManager) SetValue(val interface{}) {
 		return
 	}
 
-	espan := m.parent.startKvOpTrace("encode", m.span)
+	espan := m.parent.startKvOpTrace("encode", m.span.Context())
 	defer espan.Finish()
 
 	bytes, flags, err := m.transcoder.Encode(val)
@@ -113,19 +117,15 @@ func (m *kvOpManager) SetDuraOptions(persistTo, replicateTo uint, level Durabili
 }
 
 func (m *kvOpManager) SetRetryStrategy(retryStrategy RetryStrategy) {
-	wrapper := m.parent.retryStrategyWrapper
-	if retryStrategy != nil {
-		wrapper = newRetryStrategyWrapper(retryStrategy)
-	}
-	m.retryStrategy = wrapper
+	m.retryStrategy = m.parent.retryStrategyWrapper.forOperation(retryStrategy)
 }
 
 func (m *kvOpManager) Finish() {
 	m.span.Finish()
 }
 
-func (m *kvOpManager) TraceSpan() requestSpan {
-	return m.span
+func (m *kvOpManager) TraceSpan() RequestSpanContext {
+	return m.span.Context()
 }
 
 func (m *kvOpManager) DocumentID() []byte {
@@ -179,7 +179,7 @@ func (m *kvOpManager) DurabilityTimeout() time.Duration {
 func (m *kvOpManager) Deadline() time.Time {
 	if m.deadline.IsZero() {
 		timeout := m.getTimeout()
-		m.deadline = time.Now().Add(timeout)
+		m.deadline = m.parent.operationDeadline(timeout)
 	}
 
 	return m.deadline
@@ -206,7 +206,7 @@ func (m *kvOpManager) NeedsObserve() bool {
 }
 
 func (m *kvOpManager) EnhanceErr(err error) error {
-	return maybeEnhanceCollKVErr(err, nil, m.parent, m.documentID)
+	return m.retryStrategy.attachHistory(maybeEnhanceCollKVErr(err, nil, m.parent, m.documentID))
 }
 
 func (m *kvOpManager) EnhanceMt(token gocbcore.MutationToken) *MutationToken {
@@ -252,7 +252,7 @@ func (m *kvOpManager) Wait(op gocbcore.PendingOp, err error) error {
 		}
 
 		return m.parent.waitForDurability(
-			m.span,
+			m.span.Context(),
 			m.documentID,
 			m.mutationToken.token,
 			m.replicateTo,
@@ -265,13 +265,50 @@ func (m *kvOpManager) Wait(op gocbcore.PendingOp, err error) error {
 	return nil
 }
 
-func (c *Collection) newKvOpManager(opName string, tracectx requestSpanContext) *kvOpManager {
+// operationDeadline returns the deadline of an operation with timeout.
+func (c *Collection) operationDeadline(timeout time.Duration) time.Time {
+	if c.operationStart.IsZero() {
+		return time.Now().Add(timeout)
+	}
+
+	return c.operationStart.Add(timeout)
+}
+
+// operationTimeout returns the timeout which an operation with value and options will be dispatched
+// with, as the op manager will compute it once the operation is dispatched.
+func (c *Collection) operationTimeout(operation string, value, options interface{}) time.Duration {
+	opts := reflect.Indirect(reflect.ValueOf(options))
+	field := func(name string) interface{} {
+		if opts.Kind() != reflect.Struct {
+			return nil
+		}
+		if fieldValue := opts.FieldByName(name); fieldValue.IsValid() {
+			return fieldValue.Interface()
+		}
+		return nil
+	}
+
+	opm := &kvOpManager{parent: c, operation: operation}
+	opm.timeout, _ = field("Timeout").(time.Duration)
+	opm.durabilityLevel, _ = field("DurabilityLevel").(DurabilityLevel)
+	opm.persistTo, _ = field("PersistTo").(uint)
+
+	// Bulk operations default to a timeout for each of their operations.
+	if ops, ok := value.([]BulkOp); ok && opm.timeout == 0 {
+		return c.timeoutsConfig.KVTimeout * time.Duration(len(ops))
+	}
+
+	return opm.getTimeout()
+}
+
+func (c *Collection) newKvOpManager(opName string, tracectx RequestSpanContext) *kvOpManager {
 	span := c.startKvOpTrace(opName, tracectx)
 
 	return &kvOpManager{
-		parent: c,
-		signal: make(chan struct{}, 1),
-		span:   span,
+		parent:    c,
+		signal:    make(chan struct{}, 1),
+		span:      span,
+		operation: opName,
 	}
 }
 
@@ -2,7 +2,6 @@ package gocb
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	gocbcore "github.com/couchbase/gocbcore/v9"
@@ -38,6 +37,10 @@ const (
 )
 
 // SetLogRedactionLevel specifies the level with which logs should be redacted.
+// The same level applies to the string representation of errors returned by the SDK.
+// RedactPartial wraps user data, such as document IDs and statements, in <ud></ud> tags.
+// RedactFull also wraps metadata, such as bucket names, in <md></md> tags and system
+// data, such as server addresses, in <sd></sd> tags.
 func SetLogRedactionLevel(level LogRedactLevel) {
 	globalLogRedactionLevel = level
 	gocbcore.SetLogRedactionLevel(gocbcore.LogRedactLevel(level))
@@ -106,20 +109,17 @@ func getCoreLogger(logger Logger) gocbcore.Logger {
 
 // SetLogger sets a logger to be used by the library. A logger can be obtained via
 // the DefaultStdioLogger() or VerboseStdioLogger() functions. You can also implement
-// your own logger using the Logger interface.
+// your own logger using the Logger interface, replacing any logger set with
+// SetStructuredLogger.
 func SetLogger(logger Logger) {
 	globalLogger = logger
-	gocbcore.SetLogger(getCoreLogger(logger))
+	globalStructuredLogger = nil
+	gocbcore.SetLogger(&orphanLogger{wrapped: getCoreLogger(logger)})
 	// gocbcore.SetLogRedactionLevel(gocbcore.LogRedactLevel(globalLogRedactionLevel))
 }
 
 func logExf(level LogLevel, offset int, format string, v ...interface{}) {
-	if globalLogger != nil {
-		err := globalLogger.Log(level, offset+1, format, v...)
-		if err != nil {
-			log.Printf("Logger error occurred (%s)\n", err)
-		}
-	}
+	logFieldsExf(LogSubsystemSDK, level, offset+1, nil, format, v...)
 }
 
 func logInfof(format string, v ...interface{}) {
@@ -0,0 +1,139 @@
+package gocb
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// LoggingMeterOptions is the set of options available for configuring a LoggingMeter.
+type LoggingMeterOptions struct {
+	Interval time.Duration
+	Buckets  []time.Duration
+}
+
+// LoggingMeter is a Meter implementation which periodically logs the latency percentiles and
+// counters of every operation performed during the previous interval.  The metrics for the
+// current interval can be read using Snapshot.
+// UNCOMMITTED: This API may change in the future.
+type LoggingMeter struct {
+	*AggregatingMeter
+
+	Interval time.Duration
+
+	killCh   chan struct{}
+	refCount int32
+	nextTick time.Time
+}
+
+// NewLoggingMeter returns a new LoggingMeter.
+func NewLoggingMeter(opts *LoggingMeterOptions) *LoggingMeter {
+	if opts == nil {
+		opts = &LoggingMeterOptions{}
+	}
+	if opts.Interval == 0 {
+		opts.Interval = 10 * time.Minute
+	}
+
+	return &LoggingMeter{
+		AggregatingMeter: NewAggregatingMeter(&AggregatingMeterOptions{
+			Buckets: opts.Buckets,
+		}),
+		Interval: opts.Interval,
+		killCh:   make(chan struct{}),
+		nextTick: time.Now().Add(opts.Interval),
+	}
+}
+
+// AddRef is used internally to keep track of the number of Cluster instances referring to it.
+// This is used to correctly shut down the logging routine once there are no longer any
+// instances metering to it.
+func (m *LoggingMeter) AddRef() int32 {
+	newRefCount := atomic.AddInt32(&m.refCount, 1)
+	if newRefCount == 1 {
+		go m.loggerRoutine()
+	}
+	return newRefCount
+}
+
+// DecRef is the counterpart to AddRef (see AddRef for more information).
+func (m *LoggingMeter) DecRef() int32 {
+	newRefCount := atomic.AddInt32(&m.refCount, -1)
+	if newRefCount == 0 {
+		m.killCh <- struct{}{}
+	}
+	return newRefCount
+}
+
+func (m *LoggingMeter) loggerRoutine() {
+	for {
+		select {
+		case <-time.After(time.Until(m.nextTick)):
+			m.nextTick = m.nextTick.Add(m.Interval)
+			m.logRecordedMetrics()
+		case <-m.killCh:
+			m.logRecordedMetrics()
+			return
+		}
+	}
+}
+
+type loggingMeterItem struct {
+	Operation   string            `json:"operation"`
+	Count       uint64            `json:"count"`
+	Successes   uint64            `json:"successes"`
+	Errors      map[string]uint64 `json:"errors,omitempty"`
+	Retries     uint64            `json:"retries,omitempty"`
+	P50Us       uint64            `json:"p50_us"`
+	P90Us       uint64            `json:"p90_us"`
+	P99Us       uint64            `json:"p99_us"`
+	P999Us      uint64            `json:"p99_9_us"`
+	MaxUs       uint64            `json:"max_us"`
+	TotalTimeUs uint64            `json:"total_us"`
+}
+
+type loggingMeterService struct {
+	Service    string             `json:"service"`
+	Operations []loggingMeterItem `json:"operations"`
+}
+
+func (m *LoggingMeter) logRecordedMetrics() {
+	snapshot := m.snapshotAndReset()
+	if len(snapshot) == 0 {
+		return
+	}
+
+	var services []loggingMeterService
+	for _, metrics := range snapshot {
+		service := serviceTypeToString(metrics.Service)
+		if len(services) == 0 || services[len(services)-1].Service != service {
+			services = append(services, loggingMeterService{
+				Service: service,
+			})
+		}
+
+		latency := metrics.Latency
+		current := &services[len(services)-1]
+		current.Operations = append(current.Operations, loggingMeterItem{
+			Operation:   metrics.Operation,
+			Count:       metrics.Count,
+			Successes:   metrics.Successes,
+			Errors:      metrics.Errors,
+			Retries:     metrics.Retries,
+			P50Us:       uint64(latency.Percentile(50) / time.Microsecond),
+			P90Us:       uint64(latency.Percentile(90) / time.Microsecond),
+			P99Us:       uint64(latency.Percentile(99) / time.Microsecond),
+			P999Us:      uint64(latency.Percentile(99.9) / time.Microsecond),
+			MaxUs:       uint64(latency.Max / time.Microsecond),
+			TotalTimeUs: uint64(latency.Sum / time.Microsecond),
+		})
+	}
+
+	jsonBytes, err := json.Marshal(services)
+	if err != nil {
+		logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to generate metrics logging JSON: %s", err)
+		return
+	}
+
+	logFieldsf(LogSubsystemTelemetry, LogInfo, nil, "Metrics Log: %s", jsonBytes)
+}
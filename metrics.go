@@ -0,0 +1,403 @@
+package gocb
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OperationMetric describes the outcome of a single operation and is passed to a Meter once the
+// operation has completed.
+// UNCOMMITTED: This API may change in the future.
+type OperationMetric struct {
+	Service   ServiceType
+	Operation string
+
+	// Duration is the time taken for the operation to complete.  For query, analytics, search and
+	// view requests this is the time taken until the results were fully read or closed.
+	Duration time.Duration
+
+	// Err is the error returned to the application, or nil if the operation succeeded.  For query,
+	// analytics, search and view requests this includes any error which occurred while streaming the
+	// results.
+	Err error
+
+	// RetryAttempts and RetryReasons describe the retries which the operation underwent.  For
+	// operations which succeeded these only include the retries decided by the RetryStrategy.
+	RetryAttempts uint32
+	RetryReasons  []RetryReason
+
+	// RetryBudgetExhausted indicates that the operation failed because a retry was prevented by the
+	// cluster RetryBudget.
+	RetryBudgetExhausted bool
+}
+
+// Meter is used to record the outcome of operations performed by the SDK.  Meters are invoked
+// synchronously once an operation completes so implementations must be safe for concurrent use
+// and should not block.
+// UNCOMMITTED: This API may change in the future.
+type Meter interface {
+	RecordOperation(metric *OperationMetric)
+}
+
+func meterAddRef(meter Meter) {
+	if meter == nil {
+		return
+	}
+	if refMeter, ok := meter.(interface {
+		AddRef() int32
+	}); ok {
+		refMeter.AddRef()
+	}
+}
+
+func meterDecRef(meter Meter) {
+	if meter == nil {
+		return
+	}
+	if refMeter, ok := meter.(interface {
+		DecRef() int32
+	}); ok {
+		refMeter.DecRef()
+	}
+}
+
+// recordOperationMetric records the outcome of an operation which began at start with meter, if there is one.
+// The retries of the operation are taken from err, falling back to history if err does not describe them.
+func recordOperationMetric(meter Meter, service ServiceType, operation string, start time.Time, err error,
+	history *retryHistory) {
+	if meter == nil {
+		return
+	}
+
+	metric := &OperationMetric{
+		Service:   service,
+		Operation: operation,
+		Duration:  time.Since(start),
+		Err:       err,
+
+		RetryBudgetExhausted: errors.Is(err, ErrRetryBudgetExhausted),
+	}
+
+	var retryErr interface {
+		retryAttempts() uint32
+		retryReasons() []RetryReason
+	}
+	if errors.As(err, &retryErr) {
+		metric.RetryAttempts = retryErr.retryAttempts()
+		metric.RetryReasons = retryErr.retryReasons()
+	} else if history != nil {
+		metric.RetryAttempts, metric.RetryReasons = history.retries()
+	}
+
+	meter.RecordOperation(metric)
+}
+
+// metricErrorClass categorizes an error for the per class error counters.
+func metricErrorClass(err error) string {
+	switch {
+	case errors.Is(err, ErrTimeout):
+		return "timeout"
+	case errors.Is(err, ErrRequestCanceled):
+		return "canceled"
+	case errors.Is(err, ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, ErrDocumentNotFound):
+		return "document_not_found"
+	case errors.Is(err, ErrDocumentExists):
+		return "document_exists"
+	case errors.Is(err, ErrCasMismatch):
+		return "cas_mismatch"
+	case errors.Is(err, ErrDocumentLocked):
+		return "document_locked"
+	case errors.Is(err, ErrTemporaryFailure):
+		return "temporary_failure"
+	case errors.Is(err, ErrServiceNotAvailable):
+		return "service_not_available"
+	case errors.Is(err, ErrAuthenticationFailure):
+		return "authentication_failure"
+	case errors.Is(err, ErrConcurrencyLimitReached):
+		return "concurrency_limit"
+	}
+
+	return "other"
+}
+
+// HistogramBucket is a single bucket of a LatencyHistogram.  Count is cumulative, it is the number
+// of operations which completed within UpperBound.
+type HistogramBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// LatencyHistogram is a snapshot of the latencies recorded for an operation.
+// UNCOMMITTED: This API may change in the future.
+type LatencyHistogram struct {
+	Buckets []HistogramBucket
+	Count   uint64
+	Sum     time.Duration
+	Max     time.Duration
+}
+
+// Percentile estimates the latency below which the given percentile (between 0 and 100) of
+// operations completed.  The estimate is the upper bound of the bucket containing the percentile,
+// capped at the largest latency recorded.
+func (h LatencyHistogram) Percentile(percentile float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(percentile / 100 * float64(h.Count)))
+	if rank == 0 {
+		rank = 1
+	}
+
+	for _, bucket := range h.Buckets {
+		if bucket.Count >= rank {
+			if bucket.UpperBound > h.Max {
+				return h.Max
+			}
+			return bucket.UpperBound
+		}
+	}
+
+	return h.Max
+}
+
+// OperationMetrics is a snapshot of the metrics recorded for a single operation on a service.
+// UNCOMMITTED: This API may change in the future.
+type OperationMetrics struct {
+	Service   ServiceType
+	Operation string
+
+	Count     uint64
+	Successes uint64
+	Timeouts  uint64
+	Retries   uint64
+
+	// RetryBudgetExhausted is the number of operations which failed because the retry budget was
+	// exhausted.
+	RetryBudgetExhausted uint64
+
+	// Errors is the number of failed operations keyed by the class of error, one of timeout, canceled,
+	// invalid_argument, document_not_found, document_exists, cas_mismatch, document_locked,
+	// temporary_failure, service_not_available, authentication_failure, concurrency_limit or other.
+	Errors map[string]uint64
+
+	Latency LatencyHistogram
+}
+
+// AggregatingMeterOptions is the set of options available when creating an AggregatingMeter.
+type AggregatingMeterOptions struct {
+	// Buckets are the upper bounds of the latency histogram buckets, the default is a set of
+	// exponentially increasing bounds from 100 microseconds to 10 seconds.
+	Buckets []time.Duration
+}
+
+// AggregatingMeter is a Meter which aggregates per service and per operation counters and latency
+// histograms in memory.  The current values can be read using Snapshot.
+// UNCOMMITTED: This API may change in the future.
+type AggregatingMeter struct {
+	buckets []time.Duration
+
+	lock       sync.Mutex
+	operations map[operationMetricsKey]*operationAggregate
+
+	// totals are the metrics recorded since the meter was created, which are never reset so that
+	// they can be exported as monotonic counters.
+	totals map[operationMetricsKey]*operationAggregate
+}
+
+type operationMetricsKey struct {
+	service   ServiceType
+	operation string
+}
+
+type operationAggregate struct {
+	successes    uint64
+	retries      uint64
+	exhausted    uint64
+	errors       map[string]uint64
+	bucketCounts []uint64
+	count        uint64
+	sum          time.Duration
+	max          time.Duration
+}
+
+var defaultMetricsBuckets = []time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	1 * time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// NewAggregatingMeter returns a new AggregatingMeter.
+func NewAggregatingMeter(opts *AggregatingMeterOptions) *AggregatingMeter {
+	if opts == nil {
+		opts = &AggregatingMeterOptions{}
+	}
+
+	buckets := make([]time.Duration, len(opts.Buckets))
+	copy(buckets, opts.Buckets)
+	if len(buckets) == 0 {
+		buckets = append(buckets, defaultMetricsBuckets...)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+	return &AggregatingMeter{
+		buckets:    buckets,
+		operations: make(map[operationMetricsKey]*operationAggregate),
+		totals:     make(map[operationMetricsKey]*operationAggregate),
+	}
+}
+
+// RecordOperation belongs to the Meter interface.
+func (m *AggregatingMeter) RecordOperation(metric *OperationMetric) {
+	key := operationMetricsKey{
+		service:   metric.Service,
+		operation: metric.Operation,
+	}
+
+	errorClass := ""
+	if metric.Err != nil {
+		errorClass = metricErrorClass(metric.Err)
+	}
+	bucket := sort.Search(len(m.buckets), func(i int) bool { return metric.Duration <= m.buckets[i] })
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.aggregate(m.operations, key).record(metric, errorClass, bucket)
+	m.aggregate(m.totals, key).record(metric, errorClass, bucket)
+}
+
+func (m *AggregatingMeter) aggregate(operations map[operationMetricsKey]*operationAggregate,
+	key operationMetricsKey) *operationAggregate {
+	agg, ok := operations[key]
+	if !ok {
+		agg = &operationAggregate{
+			errors:       make(map[string]uint64),
+			bucketCounts: make([]uint64, len(m.buckets)),
+		}
+		operations[key] = agg
+	}
+
+	return agg
+}
+
+func (agg *operationAggregate) record(metric *OperationMetric, errorClass string, bucket int) {
+	if metric.Err == nil {
+		agg.successes++
+	} else {
+		agg.errors[errorClass]++
+	}
+	if metric.RetryBudgetExhausted {
+		agg.exhausted++
+	}
+	agg.retries += uint64(metric.RetryAttempts)
+
+	agg.count++
+	agg.sum += metric.Duration
+	if metric.Duration > agg.max {
+		agg.max = metric.Duration
+	}
+	if bucket < len(agg.bucketCounts) {
+		agg.bucketCounts[bucket]++
+	}
+}
+
+// Snapshot returns the current values of the metrics for every operation recorded so far, ordered
+// by service and then operation.
+func (m *AggregatingMeter) Snapshot() []OperationMetrics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.snapshotLocked(m.operations)
+}
+
+// totalsSnapshot returns the metrics for every operation recorded since the meter was created,
+// ignoring any resets.
+func (m *AggregatingMeter) totalsSnapshot() []OperationMetrics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.snapshotLocked(m.totals)
+}
+
+// Reset discards all of the metrics recorded so far.  The counters exported by NewPrometheusHandler
+// are cumulative and are not affected.
+func (m *AggregatingMeter) Reset() {
+	m.lock.Lock()
+	m.operations = make(map[operationMetricsKey]*operationAggregate)
+	m.lock.Unlock()
+}
+
+func (m *AggregatingMeter) snapshotAndReset() []OperationMetrics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	snapshot := m.snapshotLocked(m.operations)
+	m.operations = make(map[operationMetricsKey]*operationAggregate)
+
+	return snapshot
+}
+
+func (m *AggregatingMeter) snapshotLocked(operations map[operationMetricsKey]*operationAggregate) []OperationMetrics {
+	snapshot := make([]OperationMetrics, 0, len(operations))
+	for key, agg := range operations {
+		metrics := OperationMetrics{
+			Service:              key.service,
+			Operation:            key.operation,
+			Count:                agg.count,
+			Successes:            agg.successes,
+			Timeouts:             agg.errors["timeout"],
+			Retries:              agg.retries,
+			RetryBudgetExhausted: agg.exhausted,
+			Errors:               make(map[string]uint64, len(agg.errors)),
+			Latency: LatencyHistogram{
+				Buckets: make([]HistogramBucket, len(m.buckets)),
+				Count:   agg.count,
+				Sum:     agg.sum,
+				Max:     agg.max,
+			},
+		}
+
+		for class, count := range agg.errors {
+			metrics.Errors[class] = count
+		}
+
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += agg.bucketCounts[i]
+			metrics.Latency.Buckets[i] = HistogramBucket{
+				UpperBound: bound,
+				Count:      cumulative,
+			}
+		}
+
+		snapshot = append(snapshot, metrics)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Service != snapshot[j].Service {
+			return snapshot[i].Service < snapshot[j].Service
+		}
+		return snapshot[i].Operation < snapshot[j].Operation
+	})
+
+	return snapshot
+}
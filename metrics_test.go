@@ -0,0 +1,244 @@
+package gocb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestAggregatingMeterHistogram() {
+	meter := NewAggregatingMeter(&AggregatingMeterOptions{
+		Buckets: []time.Duration{10 * time.Millisecond, time.Millisecond, 100 * time.Millisecond},
+	})
+
+	for i := 0; i < 90; i++ {
+		meter.RecordOperation(&OperationMetric{
+			Service:   ServiceTypeKeyValue,
+			Operation: "Get",
+			Duration:  500 * time.Microsecond,
+		})
+	}
+	for i := 0; i < 9; i++ {
+		meter.RecordOperation(&OperationMetric{
+			Service:   ServiceTypeKeyValue,
+			Operation: "Get",
+			Duration:  5 * time.Millisecond,
+		})
+	}
+	meter.RecordOperation(&OperationMetric{
+		Service:       ServiceTypeKeyValue,
+		Operation:     "Get",
+		Duration:      time.Second,
+		Err:           &TimeoutError{InnerError: ErrUnambiguousTimeout, RetryAttempts: 3},
+		RetryAttempts: 3,
+	})
+	meter.RecordOperation(&OperationMetric{
+		Service:   ServiceTypeQuery,
+		Operation: "Query",
+		Duration:  20 * time.Millisecond,
+		Err:       errors.New("something else"),
+	})
+
+	snapshot := meter.Snapshot()
+	suite.Require().Len(snapshot, 2)
+
+	get := snapshot[0]
+	suite.Assert().Equal(ServiceTypeKeyValue, get.Service)
+	suite.Assert().Equal("Get", get.Operation)
+	suite.Assert().Equal(uint64(100), get.Count)
+	suite.Assert().Equal(uint64(99), get.Successes)
+	suite.Assert().Equal(uint64(1), get.Timeouts)
+	suite.Assert().Equal(uint64(3), get.Retries)
+	suite.Assert().Equal(map[string]uint64{"timeout": 1}, get.Errors)
+
+	suite.Assert().Equal([]HistogramBucket{
+		{UpperBound: time.Millisecond, Count: 90},
+		{UpperBound: 10 * time.Millisecond, Count: 99},
+		{UpperBound: 100 * time.Millisecond, Count: 99},
+	}, get.Latency.Buckets)
+	suite.Assert().Equal(time.Second, get.Latency.Max)
+	suite.Assert().Equal(time.Millisecond, get.Latency.Percentile(50))
+	suite.Assert().Equal(time.Millisecond, get.Latency.Percentile(90))
+	suite.Assert().Equal(10*time.Millisecond, get.Latency.Percentile(99))
+	suite.Assert().Equal(time.Second, get.Latency.Percentile(99.9))
+
+	query := snapshot[1]
+	suite.Assert().Equal(ServiceTypeQuery, query.Service)
+	suite.Assert().Equal(map[string]uint64{"other": 1}, query.Errors)
+	suite.Assert().Equal(20*time.Millisecond, query.Latency.Percentile(50))
+
+	meter.Reset()
+	suite.Assert().Empty(meter.Snapshot())
+}
+
+func (suite *UnitTestSuite) TestMeterRecordsKVOperations() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProvider)
+	provider.
+		On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+			cb(nil, &gocbcore.KeyValueError{
+				InnerError:    gocbcore.ErrDocumentNotFound,
+				RetryAttempts: 2,
+			})
+		}).
+		Return(pendingOp, nil)
+
+	meter := NewAggregatingMeter(nil)
+	col := suite.interceptedCollection(provider)
+	col.meter = meter
+
+	_, err := col.Get("key", nil)
+	suite.Require().True(errors.Is(err, ErrDocumentNotFound))
+
+	snapshot := meter.Snapshot()
+	suite.Require().Len(snapshot, 1)
+	suite.Assert().Equal(ServiceTypeKeyValue, snapshot[0].Service)
+	suite.Assert().Equal("Get", snapshot[0].Operation)
+	suite.Assert().Equal(uint64(1), snapshot[0].Count)
+	suite.Assert().Zero(snapshot[0].Successes)
+	suite.Assert().Equal(uint64(2), snapshot[0].Retries)
+	suite.Assert().Equal(map[string]uint64{"document_not_found": 1}, snapshot[0].Errors)
+}
+
+func (suite *UnitTestSuite) TestMeterRecordsKVRetriesOnSuccess() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProvider)
+	provider.
+		On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.GetOptions)
+			req := &mockGocbcoreRequest{idempotent: true}
+			opts.RetryStrategy.RetryAfter(req, gocbcore.KVLockedRetryReason)
+			req.attempts++
+			opts.RetryStrategy.RetryAfter(req, gocbcore.KVLockedRetryReason)
+
+			cb := args.Get(1).(gocbcore.GetCallback)
+			cb(&gocbcore.GetResult{Value: []byte(`{}`), Cas: 1}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	var metrics []*OperationMetric
+	col := suite.interceptedCollection(provider)
+	col.meter = meterFunc(func(metric *OperationMetric) {
+		metrics = append(metrics, metric)
+	})
+
+	_, err := col.Get("key", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(metrics, 1)
+	suite.Assert().Nil(metrics[0].Err)
+	suite.Assert().Equal(uint32(2), metrics[0].RetryAttempts)
+	suite.Assert().Equal([]RetryReason{KVLockedRetryReason}, metrics[0].RetryReasons)
+}
+
+func (suite *UnitTestSuite) TestMeterRecordsQueryOnClose() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	var metrics []*OperationMetric
+	cluster := suite.queryCluster(false, reader, nil)
+	cluster.meter = meterFunc(func(metric *OperationMetric) {
+		metrics = append(metrics, metric)
+	})
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+	suite.Require().True(result.Next())
+	suite.Assert().Empty(metrics)
+
+	time.Sleep(10 * time.Millisecond)
+	suite.Require().Nil(result.Close())
+
+	suite.Require().Len(metrics, 1)
+	suite.Assert().Equal("Query", metrics[0].Operation)
+	suite.Assert().Nil(metrics[0].Err)
+	suite.Assert().GreaterOrEqual(int64(metrics[0].Duration), int64(10*time.Millisecond))
+}
+
+type meterFunc func(metric *OperationMetric)
+
+func (f meterFunc) RecordOperation(metric *OperationMetric) {
+	f(metric)
+}
+
+func (suite *UnitTestSuite) TestMeterRecordsQuery() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	meter := NewAggregatingMeter(nil)
+	cluster := suite.queryCluster(false, reader, nil)
+	cluster.meter = meter
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+	suite.assertQueryBeerResult(dataset, result)
+
+	snapshot := meter.Snapshot()
+	suite.Require().Len(snapshot, 1)
+	suite.Assert().Equal(ServiceTypeQuery, snapshot[0].Service)
+	suite.Assert().Equal("Query", snapshot[0].Operation)
+	suite.Assert().Equal(uint64(1), snapshot[0].Successes)
+}
+
+type testCaptureLogger struct {
+	messages []string
+}
+
+func (l *testCaptureLogger) Log(level LogLevel, offset int, format string, v ...interface{}) error {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+	return nil
+}
+
+func (suite *UnitTestSuite) TestLoggingMeterLogsAndResets() {
+	meter := NewLoggingMeter(nil)
+	meter.RecordOperation(&OperationMetric{
+		Service:   ServiceTypeKeyValue,
+		Operation: "Upsert",
+		Duration:  2 * time.Millisecond,
+	})
+
+	logger := &testCaptureLogger{}
+	oldLogger := globalLogger
+	globalLogger = logger
+	defer func() {
+		globalLogger = oldLogger
+	}()
+
+	meter.logRecordedMetrics()
+	suite.Require().Len(logger.messages, 1)
+	suite.Assert().True(strings.HasPrefix(logger.messages[0], "Metrics Log: "))
+	suite.Assert().Contains(logger.messages[0], `"service":"kv"`)
+	suite.Assert().Contains(logger.messages[0], `"operation":"Upsert"`)
+	suite.Assert().Contains(logger.messages[0], `"p99_us":2000`)
+	suite.Assert().Empty(meter.Snapshot())
+
+	meter.logRecordedMetrics()
+	suite.Assert().Len(logger.messages, 1)
+}
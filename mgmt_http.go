@@ -17,10 +17,14 @@ type mgmtRequest struct {
 	IsIdempotent bool
 	UniqueID     string
 
+	// Endpoint pins the request to a specific node, e.g. http://10.0.0.1:8093, rather than letting the
+	// SDK pick one.
+	Endpoint string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 
-	parentSpan requestSpanContext
+	parentSpan RequestSpanContext
 }
 
 type mgmtResponse struct {
@@ -44,13 +48,11 @@ func (c *Cluster) executeMgmtRequest(req mgmtRequest) (mgmtRespOut *mgmtResponse
 		return nil, err
 	}
 
-	retryStrategy := c.retryStrategyWrapper
-	if req.RetryStrategy != nil {
-		retryStrategy = newRetryStrategyWrapper(req.RetryStrategy)
-	}
+	retryStrategy := c.retryStrategyWrapper.withStrategy(req.RetryStrategy)
 
 	corereq := &gocbcore.HTTPRequest{
 		Service:       gocbcore.ServiceType(req.Service),
+		Endpoint:      req.Endpoint,
 		Method:        req.Method,
 		Path:          req.Path,
 		Body:          req.Body,
@@ -87,13 +89,11 @@ func (b *Bucket) executeMgmtRequest(req mgmtRequest) (mgmtRespOut *mgmtResponse,
 		return nil, err
 	}
 
-	retryStrategy := b.retryStrategyWrapper
-	if req.RetryStrategy != nil {
-		retryStrategy = newRetryStrategyWrapper(req.RetryStrategy)
-	}
+	retryStrategy := b.retryStrategyWrapper.withStrategy(req.RetryStrategy)
 
 	corereq := &gocbcore.HTTPRequest{
 		Service:       gocbcore.ServiceType(req.Service),
+		Endpoint:      req.Endpoint,
 		Method:        req.Method,
 		Path:          req.Path,
 		Body:          req.Body,
@@ -121,6 +121,6 @@ func (b *Bucket) executeMgmtRequest(req mgmtRequest) (mgmtRespOut *mgmtResponse,
 func ensureBodyClosed(body io.ReadCloser) {
 	err := body.Close()
 	if err != nil {
-		logDebugf("Failed to close socket: %v", err)
+		logFieldsf(LogSubsystemManagement, LogDebug, nil, "Failed to close socket: %v", err)
 	}
 }
@@ -0,0 +1,523 @@
+package n1ql
+
+import "errors"
+
+// SelectBuilder builds a SELECT statement.
+type SelectBuilder struct {
+	distinct bool
+	raw      bool
+	exprs    []expression
+	from     *Keyspace
+	useKeys  []interface{}
+	joins    []selectJoin
+	where    []expression
+	groupBy  []expression
+	having   []expression
+	orderBy  []expression
+	limit    int
+	offset   int
+}
+
+type selectJoin struct {
+	joinType string
+	keyspace *Keyspace
+	on       expression
+}
+
+// NewSelect creates a new SelectBuilder for the projection exprs, each of which is an expression
+// such as `name` or COUNT(*) AS total.
+func NewSelect(exprs ...string) *SelectBuilder {
+	return &SelectBuilder{
+		exprs:  newExpressions(exprs),
+		limit:  -1,
+		offset: -1,
+	}
+}
+
+// Distinct removes duplicate rows from the results.
+func (b *SelectBuilder) Distinct() *SelectBuilder {
+	b.distinct = true
+	return b
+}
+
+// Raw returns the value of the single projection expression as each row, rather than an object.
+func (b *SelectBuilder) Raw() *SelectBuilder {
+	b.raw = true
+	return b
+}
+
+// From sets the keyspace to select from.
+func (b *SelectBuilder) From(keyspace *Keyspace) *SelectBuilder {
+	b.from = keyspace
+	return b
+}
+
+// UseKeys limits the statement to the documents with the given keys.
+func (b *SelectBuilder) UseKeys(keys ...string) *SelectBuilder {
+	b.useKeys = stringArgs(keys)
+	return b
+}
+
+// Join adds an ANSI join of keyspace on the condition on, joinType is e.g. INNER or LEFT OUTER and
+// may be empty.
+func (b *SelectBuilder) Join(joinType string, keyspace *Keyspace, on string, args ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, selectJoin{
+		joinType: joinType,
+		keyspace: keyspace,
+		on:       expression{text: on, args: args},
+	})
+	return b
+}
+
+// Where adds a condition which rows must satisfy, multiple conditions are combined using AND.
+func (b *SelectBuilder) Where(expr string, args ...interface{}) *SelectBuilder {
+	b.where = append(b.where, expression{text: expr, args: args})
+	return b
+}
+
+// GroupBy sets the expressions to group rows by.
+func (b *SelectBuilder) GroupBy(exprs ...string) *SelectBuilder {
+	b.groupBy = newExpressions(exprs)
+	return b
+}
+
+// Having adds a condition which groups must satisfy, multiple conditions are combined using AND.
+func (b *SelectBuilder) Having(expr string, args ...interface{}) *SelectBuilder {
+	b.having = append(b.having, expression{text: expr, args: args})
+	return b
+}
+
+// OrderBy sets the expressions to order rows by, e.g. `name` DESC.
+func (b *SelectBuilder) OrderBy(exprs ...string) *SelectBuilder {
+	b.orderBy = newExpressions(exprs)
+	return b
+}
+
+// Limit sets the maximum number of rows to return.
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+	return b
+}
+
+// Offset sets the number of rows to skip.
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = offset
+	return b
+}
+
+// Build builds the statement.
+func (b *SelectBuilder) Build() (*Statement, error) {
+	return buildStatement(b.write)
+}
+
+func (b *SelectBuilder) write(w *statementWriter) {
+	w.writeString("SELECT ")
+	if b.distinct {
+		w.writeString("DISTINCT ")
+	}
+	if b.raw {
+		w.writeString("RAW ")
+	}
+	if len(b.exprs) == 0 {
+		w.writeString("*")
+	}
+	w.writeExpressions("", b.exprs, ", ")
+
+	if b.from != nil {
+		w.writeKeyspace(" FROM ", b.from)
+		w.writeUseKeys(b.useKeys)
+	}
+	for _, join := range b.joins {
+		if join.joinType != "" {
+			w.writeString(" " + join.joinType)
+		}
+		w.writeKeyspace(" JOIN ", join.keyspace)
+		w.writeString(" ON ")
+		w.writeExpression(join.on)
+	}
+	w.writeConditions(" WHERE ", b.where)
+	w.writeExpressions(" GROUP BY ", b.groupBy, ", ")
+	w.writeConditions(" HAVING ", b.having)
+	w.writeExpressions(" ORDER BY ", b.orderBy, ", ")
+	w.writeLimit(" LIMIT ", b.limit)
+	w.writeLimit(" OFFSET ", b.offset)
+}
+
+// InsertBuilder builds an INSERT or UPSERT statement.
+type InsertBuilder struct {
+	verb      string
+	keyspace  *Keyspace
+	values    []insertValue
+	returning []expression
+}
+
+type insertValue struct {
+	key   string
+	value interface{}
+}
+
+// NewInsert creates a new InsertBuilder which inserts documents into keyspace, failing for any
+// document which already exists.
+func NewInsert(keyspace *Keyspace) *InsertBuilder {
+	return &InsertBuilder{
+		verb:     "INSERT",
+		keyspace: keyspace,
+	}
+}
+
+// NewUpsert creates a new InsertBuilder which inserts or replaces documents in keyspace.
+func NewUpsert(keyspace *Keyspace) *InsertBuilder {
+	return &InsertBuilder{
+		verb:     "UPSERT",
+		keyspace: keyspace,
+	}
+}
+
+// Value adds a document to be written.
+func (b *InsertBuilder) Value(key string, value interface{}) *InsertBuilder {
+	b.values = append(b.values, insertValue{key: key, value: value})
+	return b
+}
+
+// Returning sets the expressions to return for each document written.
+func (b *InsertBuilder) Returning(exprs ...string) *InsertBuilder {
+	b.returning = newExpressions(exprs)
+	return b
+}
+
+// Build builds the statement.
+func (b *InsertBuilder) Build() (*Statement, error) {
+	return buildStatement(b.write)
+}
+
+func (b *InsertBuilder) write(w *statementWriter) {
+	w.writeKeyspace(b.verb+" INTO ", b.keyspace)
+	w.writeString(" (KEY, VALUE) VALUES ")
+	if len(b.values) == 0 {
+		w.fail(errIncomplete(b.verb, "at least one value"))
+	}
+	for i, value := range b.values {
+		if i > 0 {
+			w.writeString(", ")
+		}
+		w.writeString("(")
+		w.writeValue(value.key)
+		w.writeString(", ")
+		w.writeValue(value.value)
+		w.writeString(")")
+	}
+	w.writeExpressions(" RETURNING ", b.returning, ", ")
+}
+
+// UpdateBuilder builds an UPDATE statement.
+type UpdateBuilder struct {
+	keyspace  *Keyspace
+	useKeys   []interface{}
+	set       []Assignment
+	unset     []string
+	where     []expression
+	limit     int
+	returning []expression
+}
+
+// NewUpdate creates a new UpdateBuilder which updates documents in keyspace.
+func NewUpdate(keyspace *Keyspace) *UpdateBuilder {
+	return &UpdateBuilder{
+		keyspace: keyspace,
+		limit:    -1,
+	}
+}
+
+// UseKeys limits the statement to the documents with the given keys.
+func (b *UpdateBuilder) UseKeys(keys ...string) *UpdateBuilder {
+	b.useKeys = stringArgs(keys)
+	return b
+}
+
+// Set adds assignments to be made to each document, see Set and SetPath.
+func (b *UpdateBuilder) Set(assignments ...Assignment) *UpdateBuilder {
+	b.set = append(b.set, assignments...)
+	return b
+}
+
+// Unset adds a nested field to be removed from each document, the path is escaped using Path.
+func (b *UpdateBuilder) Unset(path ...string) *UpdateBuilder {
+	b.unset = append(b.unset, Path(path...))
+	return b
+}
+
+// Where adds a condition which documents must satisfy, multiple conditions are combined using AND.
+func (b *UpdateBuilder) Where(expr string, args ...interface{}) *UpdateBuilder {
+	b.where = append(b.where, expression{text: expr, args: args})
+	return b
+}
+
+// Limit sets the maximum number of documents to update.
+func (b *UpdateBuilder) Limit(limit int) *UpdateBuilder {
+	b.limit = limit
+	return b
+}
+
+// Returning sets the expressions to return for each document updated.
+func (b *UpdateBuilder) Returning(exprs ...string) *UpdateBuilder {
+	b.returning = newExpressions(exprs)
+	return b
+}
+
+// Build builds the statement.
+func (b *UpdateBuilder) Build() (*Statement, error) {
+	return buildStatement(b.write)
+}
+
+func (b *UpdateBuilder) write(w *statementWriter) {
+	w.writeKeyspace("UPDATE ", b.keyspace)
+	w.writeUseKeys(b.useKeys)
+	if len(b.set) == 0 && len(b.unset) == 0 {
+		w.fail(errIncomplete("UPDATE", "at least one Set or Unset"))
+	}
+	w.writeAssignments(" SET ", b.set)
+	w.writeExpressions(" UNSET ", newExpressions(b.unset), ", ")
+	w.writeConditions(" WHERE ", b.where)
+	w.writeLimit(" LIMIT ", b.limit)
+	w.writeExpressions(" RETURNING ", b.returning, ", ")
+}
+
+// DeleteBuilder builds a DELETE statement.
+type DeleteBuilder struct {
+	keyspace  *Keyspace
+	useKeys   []interface{}
+	where     []expression
+	limit     int
+	returning []expression
+}
+
+// NewDelete creates a new DeleteBuilder which deletes documents from keyspace.
+func NewDelete(keyspace *Keyspace) *DeleteBuilder {
+	return &DeleteBuilder{
+		keyspace: keyspace,
+		limit:    -1,
+	}
+}
+
+// UseKeys limits the statement to the documents with the given keys.
+func (b *DeleteBuilder) UseKeys(keys ...string) *DeleteBuilder {
+	b.useKeys = stringArgs(keys)
+	return b
+}
+
+// Where adds a condition which documents must satisfy, multiple conditions are combined using AND.
+func (b *DeleteBuilder) Where(expr string, args ...interface{}) *DeleteBuilder {
+	b.where = append(b.where, expression{text: expr, args: args})
+	return b
+}
+
+// Limit sets the maximum number of documents to delete.
+func (b *DeleteBuilder) Limit(limit int) *DeleteBuilder {
+	b.limit = limit
+	return b
+}
+
+// Returning sets the expressions to return for each document deleted.
+func (b *DeleteBuilder) Returning(exprs ...string) *DeleteBuilder {
+	b.returning = newExpressions(exprs)
+	return b
+}
+
+// Build builds the statement.
+func (b *DeleteBuilder) Build() (*Statement, error) {
+	return buildStatement(b.write)
+}
+
+func (b *DeleteBuilder) write(w *statementWriter) {
+	w.writeKeyspace("DELETE FROM ", b.keyspace)
+	w.writeUseKeys(b.useKeys)
+	w.writeConditions(" WHERE ", b.where)
+	w.writeLimit(" LIMIT ", b.limit)
+	w.writeExpressions(" RETURNING ", b.returning, ", ")
+}
+
+// MergeBuilder builds a MERGE statement.
+type MergeBuilder struct {
+	target         *Keyspace
+	source         *Keyspace
+	sourceSelect   *SelectBuilder
+	sourceAlias    string
+	on             *expression
+	matchedSet     []Assignment
+	matchedUnset   []string
+	matchedWhere   *expression
+	matchedDelete  bool
+	deleteWhere    *expression
+	notMatchedKey  *expression
+	notMatchedVal  *expression
+	notMatchedCond *expression
+	limit          int
+	returning      []expression
+}
+
+// NewMerge creates a new MergeBuilder which merges documents into the target keyspace.
+func NewMerge(target *Keyspace) *MergeBuilder {
+	return &MergeBuilder{
+		target: target,
+		limit:  -1,
+	}
+}
+
+// Using sets the keyspace which is merged into the target.
+func (b *MergeBuilder) Using(source *Keyspace) *MergeBuilder {
+	b.source = source
+	b.sourceSelect = nil
+	return b
+}
+
+// UsingSelect sets a subquery whose results are merged into the target, referred to as alias.
+func (b *MergeBuilder) UsingSelect(source *SelectBuilder, alias string) *MergeBuilder {
+	b.sourceSelect = source
+	b.sourceAlias = alias
+	b.source = nil
+	return b
+}
+
+// On sets the condition which matches source rows to target documents.
+func (b *MergeBuilder) On(expr string, args ...interface{}) *MergeBuilder {
+	b.on = &expression{text: expr, args: args}
+	return b
+}
+
+// WhenMatchedUpdate updates matched target documents with assignments, see Set and SetPath.
+func (b *MergeBuilder) WhenMatchedUpdate(assignments ...Assignment) *MergeBuilder {
+	b.matchedSet = append(b.matchedSet, assignments...)
+	return b
+}
+
+// WhenMatchedUnset removes a nested field from matched target documents, the path is escaped using Path.
+func (b *MergeBuilder) WhenMatchedUnset(path ...string) *MergeBuilder {
+	b.matchedUnset = append(b.matchedUnset, Path(path...))
+	return b
+}
+
+// WhenMatchedUpdateWhere limits the matched target documents which are updated, it requires
+// WhenMatchedUpdate or WhenMatchedUnset.
+func (b *MergeBuilder) WhenMatchedUpdateWhere(expr string, args ...interface{}) *MergeBuilder {
+	b.matchedWhere = &expression{text: expr, args: args}
+	return b
+}
+
+// WhenMatchedDelete deletes matched target documents.  If expr is not empty then only matched
+// documents which satisfy it are deleted.
+func (b *MergeBuilder) WhenMatchedDelete(expr string, args ...interface{}) *MergeBuilder {
+	b.matchedDelete = true
+	if expr != "" {
+		b.deleteWhere = &expression{text: expr, args: args}
+	}
+	return b
+}
+
+// WhenNotMatchedInsert inserts a document for each source row which did not match a target document,
+// with the key and value given by the keyExpr and valueExpr expressions.  If where is not empty then
+// only rows which satisfy it are inserted.
+func (b *MergeBuilder) WhenNotMatchedInsert(keyExpr, valueExpr, where string, args ...interface{}) *MergeBuilder {
+	b.notMatchedKey = &expression{text: keyExpr}
+	b.notMatchedVal = &expression{text: valueExpr}
+	b.notMatchedCond = nil
+	if where != "" {
+		b.notMatchedCond = &expression{text: where, args: args}
+	}
+	return b
+}
+
+// Limit sets the maximum number of documents to merge.
+func (b *MergeBuilder) Limit(limit int) *MergeBuilder {
+	b.limit = limit
+	return b
+}
+
+// Returning sets the expressions to return for each document merged.
+func (b *MergeBuilder) Returning(exprs ...string) *MergeBuilder {
+	b.returning = newExpressions(exprs)
+	return b
+}
+
+// Build builds the statement.
+func (b *MergeBuilder) Build() (*Statement, error) {
+	return buildStatement(b.write)
+}
+
+func (b *MergeBuilder) write(w *statementWriter) {
+	w.writeKeyspace("MERGE INTO ", b.target)
+
+	switch {
+	case b.sourceSelect != nil:
+		if b.sourceAlias == "" {
+			w.fail(errIncomplete("MERGE", "an alias for the source subquery"))
+		}
+		w.writeString(" USING (")
+		b.sourceSelect.write(w)
+		w.writeString(") AS " + Identifier(b.sourceAlias))
+	case b.source != nil:
+		w.writeKeyspace(" USING ", b.source)
+	default:
+		w.fail(errIncomplete("MERGE", "a source"))
+	}
+
+	if b.on == nil {
+		w.fail(errIncomplete("MERGE", "an On condition"))
+	} else {
+		w.writeString(" ON ")
+		w.writeExpression(*b.on)
+	}
+
+	if len(b.matchedSet) == 0 && len(b.matchedUnset) == 0 && !b.matchedDelete && b.notMatchedKey == nil {
+		w.fail(errIncomplete("MERGE", "at least one WHEN clause"))
+	}
+
+	if b.matchedWhere != nil && len(b.matchedSet) == 0 && len(b.matchedUnset) == 0 {
+		w.fail(errIncomplete("MERGE", "WhenMatchedUpdate or WhenMatchedUnset to use WhenMatchedUpdateWhere"))
+	}
+
+	if len(b.matchedSet) > 0 || len(b.matchedUnset) > 0 {
+		w.writeString(" WHEN MATCHED THEN UPDATE")
+		w.writeAssignments(" SET ", b.matchedSet)
+		w.writeExpressions(" UNSET ", newExpressions(b.matchedUnset), ", ")
+		if b.matchedWhere != nil {
+			w.writeString(" WHERE ")
+			w.writeExpression(*b.matchedWhere)
+		}
+	}
+
+	if b.matchedDelete {
+		w.writeString(" WHEN MATCHED THEN DELETE")
+		if b.deleteWhere != nil {
+			w.writeString(" WHERE ")
+			w.writeExpression(*b.deleteWhere)
+		}
+	}
+
+	if b.notMatchedKey != nil {
+		w.writeString(" WHEN NOT MATCHED THEN INSERT (KEY ")
+		w.writeExpression(*b.notMatchedKey)
+		w.writeString(", VALUE ")
+		w.writeExpression(*b.notMatchedVal)
+		w.writeString(")")
+		if b.notMatchedCond != nil {
+			w.writeString(" WHERE ")
+			w.writeExpression(*b.notMatchedCond)
+		}
+	}
+
+	w.writeLimit(" LIMIT ", b.limit)
+	w.writeExpressions(" RETURNING ", b.returning, ", ")
+}
+
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+
+	return args
+}
+
+func errIncomplete(verb, missing string) error {
+	return errors.New(verb + " statement requires " + missing)
+}
@@ -0,0 +1,244 @@
+package n1ql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentifierEscaping(t *testing.T) {
+	if id := Identifier("travel`sample"); id != "`travel``sample`" {
+		t.Fatalf("Unexpected identifier: %s", id)
+	}
+
+	ks := NewCollection("travel-sample", "inventory", "air`line").As("a")
+	if ks.String() != "`travel-sample`.`inventory`.`air``line` AS `a`" {
+		t.Fatalf("Unexpected keyspace: %s", ks.String())
+	}
+}
+
+func TestKeyspaceAsCopies(t *testing.T) {
+	ks := NewKeyspace("default")
+	aliased := ks.As("a")
+	if ks.String() != "`default`" || aliased.String() != "`default` AS `a`" {
+		t.Fatalf("Unexpected keyspaces: %s, %s", ks.String(), aliased.String())
+	}
+}
+
+func TestSelectBuild(t *testing.T) {
+	stmt, err := NewSelect("a.name", "COUNT(*) AS total").
+		From(NewCollection("travel-sample", "inventory", "airline").As("a")).
+		Join("LEFT", NewCollection("travel-sample", "inventory", "route").As("r"), "r.airlineid = META(a).id").
+		Where("a.country = ? AND a.name != '?'", "France").
+		Where("a.id > ?", 10).
+		GroupBy("a.name").
+		Having("COUNT(*) > ?", 2).
+		OrderBy("total DESC").
+		Limit(5).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+
+	expected := "SELECT a.name, COUNT(*) AS total FROM `travel-sample`.`inventory`.`airline` AS `a` " +
+		"LEFT JOIN `travel-sample`.`inventory`.`route` AS `r` ON r.airlineid = META(a).id " +
+		"WHERE (a.country = $1 AND a.name != '?') AND (a.id > $2) GROUP BY a.name HAVING (COUNT(*) > $3) " +
+		"ORDER BY total DESC LIMIT 5"
+	if stmt.Text != expected {
+		t.Fatalf("Unexpected statement:\n%s\nexpected:\n%s", stmt.Text, expected)
+	}
+	if !reflect.DeepEqual(stmt.PositionalParameters, []interface{}{"France", 10, 2}) {
+		t.Fatalf("Unexpected parameters: %v", stmt.PositionalParameters)
+	}
+
+	opts := stmt.QueryOptions()
+	if len(opts.PositionalParameters) != 3 || opts.NamedParameters != nil {
+		t.Fatalf("Unexpected query options: %v", opts)
+	}
+}
+
+func TestSelectNamedArgs(t *testing.T) {
+	stmt, err := NewSelect().
+		From(NewKeyspace("default")).
+		UseKeys("k1", "k2").
+		Where("type = $type", Named("type", "hotel")).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	if stmt.Text != "SELECT * FROM `default` USE KEYS $_v1 WHERE (type = $type)" {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+	if !reflect.DeepEqual(stmt.NamedParameters, map[string]interface{}{
+		"_v1":  []interface{}{"k1", "k2"},
+		"type": "hotel",
+	}) || stmt.PositionalParameters != nil {
+		t.Fatalf("Unexpected parameters: %v %v", stmt.NamedParameters, stmt.PositionalParameters)
+	}
+
+	_, err = NewUpdate(NewKeyspace("default")).
+		UseKeys("k1").
+		Set(Set("name", "a")).
+		Where("a = ? AND type = $type", 1, Named("type", "hotel")).
+		Build()
+	if err == nil {
+		t.Fatalf("Expected mixing positional and named arguments to fail")
+	}
+
+	_, err = NewUpdate(NewKeyspace("default")).
+		UseKeys("k1").
+		Set(Set("name", "a")).
+		Where("type = $_v1", Named("_v1", "hotel")).
+		Build()
+	if err == nil {
+		t.Fatalf("Expected reusing a builder argument name to fail")
+	}
+
+	stmt, err = NewSelect().
+		From(NewKeyspace("default")).
+		Where("type = $type", Named("$type", "hotel")).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	if stmt.Text != "SELECT * FROM `default` WHERE (type = $type)" {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+	if !reflect.DeepEqual(stmt.NamedParameters, map[string]interface{}{"type": "hotel"}) {
+		t.Fatalf("Unexpected parameters: %v", stmt.NamedParameters)
+	}
+}
+
+func TestPlaceholdersInLiteralsAndComments(t *testing.T) {
+	stmt, err := NewSelect().
+		From(NewKeyspace("default")).
+		Where("a = 'it\\'s ?' /* b = ? */ AND c = ? -- d = ?\nAND e = \"?\"", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	expected := "SELECT * FROM `default` WHERE (a = 'it\\'s ?' /* b = ? */ AND c = $1 -- d = ?\nAND e = \"?\")"
+	if stmt.Text != expected {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+	if !reflect.DeepEqual(stmt.PositionalParameters, []interface{}{1}) {
+		t.Fatalf("Unexpected parameters: %v", stmt.PositionalParameters)
+	}
+}
+
+func TestConditionsAreParenthesized(t *testing.T) {
+	stmt, err := NewSelect().From(NewKeyspace("default")).Where("a = 1 OR b = 2").Where("c = 3").Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	if stmt.Text != "SELECT * FROM `default` WHERE (a = 1 OR b = 2) AND (c = 3)" {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+}
+
+func TestTrailingComments(t *testing.T) {
+	stmt, err := NewSelect().From(NewKeyspace("default")).Where("a = ? -- first", 1).Where("b = ?", 2).Limit(1).Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	if stmt.Text != "SELECT * FROM `default` WHERE (a = $1 -- first\n) AND (b = $2) LIMIT 1" {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+
+	if _, err := NewSelect().From(NewKeyspace("default")).Where("a = 1 /* open").Build(); err == nil {
+		t.Fatalf("Expected an unterminated block comment to fail")
+	}
+	if _, err := NewSelect().From(NewKeyspace("default")).Where("a = 'open").Build(); err == nil {
+		t.Fatalf("Expected an unterminated string literal to fail")
+	}
+}
+
+func TestPlaceholderMismatch(t *testing.T) {
+	if _, err := NewSelect().From(NewKeyspace("default")).Where("a = ? AND b = ?", 1).Build(); err == nil {
+		t.Fatalf("Expected too few arguments to fail")
+	}
+	if _, err := NewSelect().From(NewKeyspace("default")).Where("a = ?", 1, 2).Build(); err == nil {
+		t.Fatalf("Expected too many arguments to fail")
+	}
+	if _, err := NewDelete(NewCollection("default", "", "users")).Build(); err == nil {
+		t.Fatalf("Expected incomplete keyspace to fail")
+	}
+}
+
+func TestMutationBuild(t *testing.T) {
+	ks := NewCollection("default", "app", "users")
+
+	stmt, err := NewUpsert(ks).Value("u1", map[string]string{"name": "a"}).Value("u2", 2).Returning("META().id").Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	if stmt.Text != "UPSERT INTO `default`.`app`.`users` (KEY, VALUE) VALUES ($1, $2), ($3, $4) RETURNING META().id" {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+
+	stmt, err = NewUpdate(ks).
+		UseKeys("u1").
+		Set(Set("na`me", "b"), SetPath([]string{"address", "city"}, "Paris")).
+		Unset("legacy").
+		Where("age > ?", 18).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	expected := "UPDATE `default`.`app`.`users` USE KEYS $1 SET `na``me` = $2, `address`.`city` = $3 " +
+		"UNSET `legacy` WHERE (age > $4)"
+	if stmt.Text != expected {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+	if !reflect.DeepEqual(stmt.PositionalParameters, []interface{}{"u1", "b", "Paris", 18}) {
+		t.Fatalf("Unexpected parameters: %v", stmt.PositionalParameters)
+	}
+
+	stmt, err = NewDelete(ks).Where("expired = ?", true).Limit(100).Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+	if stmt.Text != "DELETE FROM `default`.`app`.`users` WHERE (expired = $1) LIMIT 100" {
+		t.Fatalf("Unexpected statement: %s", stmt.Text)
+	}
+
+	if _, err := NewUpdate(ks).Build(); err == nil {
+		t.Fatalf("Expected update without assignments to fail")
+	}
+}
+
+func TestMergeBuild(t *testing.T) {
+	stmt, err := NewMerge(NewCollection("default", "app", "users").As("t")).
+		UsingSelect(NewSelect("s.*").From(NewKeyspace("staging").As("s")).Where("s.batch = ?", 7), "src").
+		On("META(t).id = src.id").
+		WhenMatchedUpdate(Set("name", "x")).
+		WhenMatchedDelete("src.deleted = ?", true).
+		WhenNotMatchedInsert("src.id", "src", "").
+		Build()
+	if err != nil {
+		t.Fatalf("Expected build to succeed: %v", err)
+	}
+
+	expected := "MERGE INTO `default`.`app`.`users` AS `t` USING (SELECT s.* FROM `staging` AS `s` WHERE (s.batch = $1)) " +
+		"AS `src` ON META(t).id = src.id WHEN MATCHED THEN UPDATE SET `name` = $2 " +
+		"WHEN MATCHED THEN DELETE WHERE src.deleted = $3 WHEN NOT MATCHED THEN INSERT (KEY src.id, VALUE src)"
+	if stmt.Text != expected {
+		t.Fatalf("Unexpected statement:\n%s\nexpected:\n%s", stmt.Text, expected)
+	}
+	if !reflect.DeepEqual(stmt.PositionalParameters, []interface{}{7, "x", true}) {
+		t.Fatalf("Unexpected parameters: %v", stmt.PositionalParameters)
+	}
+
+	if _, err := NewMerge(NewKeyspace("default")).Using(NewKeyspace("staging")).Build(); err == nil {
+		t.Fatalf("Expected merge without on condition to fail")
+	}
+
+	_, err = NewMerge(NewKeyspace("default")).
+		Using(NewKeyspace("staging")).
+		On("true").
+		WhenMatchedUpdateWhere("a = 1").
+		WhenMatchedDelete("").
+		Build()
+	if err == nil {
+		t.Fatalf("Expected merge with an update condition but no update to fail")
+	}
+}
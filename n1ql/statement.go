@@ -0,0 +1,437 @@
+// Package n1ql provides builders for N1QL statements which escape identifiers and bind values as
+// query parameters rather than concatenating them into the statement.
+//
+// Expressions passed to the builders are N1QL text in which each ? is bound to the next positional
+// argument and each $name is bound by a NamedArg argument, for example:
+//
+//	stmt, err := n1ql.NewSelect("name", "country").
+//		From(n1ql.NewCollection("travel-sample", "inventory", "airline")).
+//		Where("country = ? AND callsign IS NOT MISSING", "France").
+//		Limit(10).
+//		Build()
+//	result, err := cluster.Query(stmt.Text, stmt.QueryOptions())
+//
+// Values which the builders bind themselves, such as keys and assigned values, are bound positionally
+// unless the expressions of the statement use named arguments, in which case they are bound as $_v1,
+// $_v2 and so on.
+//
+// UNCOMMITTED: This API may change in the future.
+package n1ql
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	gocb "github.com/couchbase/gocb/v2"
+)
+
+// Statement is a built N1QL statement along with the values of its parameters.
+type Statement struct {
+	Text string
+
+	// PositionalParameters and NamedParameters are the values bound to the statement, only one of
+	// them is ever set.
+	PositionalParameters []interface{}
+	NamedParameters      map[string]interface{}
+}
+
+// QueryOptions returns QueryOptions with the parameters of the statement set.
+func (s *Statement) QueryOptions() *gocb.QueryOptions {
+	opts := &gocb.QueryOptions{}
+	s.Apply(opts)
+	return opts
+}
+
+// Apply sets the parameters of the statement on opts, replacing any parameters already set.
+func (s *Statement) Apply(opts *gocb.QueryOptions) {
+	opts.PositionalParameters = s.PositionalParameters
+	opts.NamedParameters = s.NamedParameters
+}
+
+// String returns the text of the statement.
+func (s *Statement) String() string {
+	return s.Text
+}
+
+// NamedArg is an argument bound to the $name placeholder of an expression.
+type NamedArg struct {
+	Name  string
+	Value interface{}
+}
+
+// Named returns a NamedArg binding value to the $name placeholder.
+func Named(name string, value interface{}) NamedArg {
+	return NamedArg{
+		Name:  strings.TrimPrefix(name, "$"),
+		Value: value,
+	}
+}
+
+// Identifier escapes name so that it can be used as an identifier within a statement, any backticks
+// within name are doubled.
+func Identifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// Path escapes each of parts as an identifier and joins them into a path, e.g. `address`.`city`.
+func Path(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = Identifier(part)
+	}
+
+	return strings.Join(escaped, ".")
+}
+
+// Keyspace identifies a bucket, or a collection within a bucket, which a statement operates on.
+type Keyspace struct {
+	bucket     string
+	scope      string
+	collection string
+	alias      string
+}
+
+// NewKeyspace returns a Keyspace for the default collection of bucket.
+func NewKeyspace(bucket string) *Keyspace {
+	return &Keyspace{
+		bucket: bucket,
+	}
+}
+
+// NewCollection returns a Keyspace for a collection.
+func NewCollection(bucket, scope, collection string) *Keyspace {
+	return &Keyspace{
+		bucket:     bucket,
+		scope:      scope,
+		collection: collection,
+	}
+}
+
+// As returns a copy of the keyspace which is referred to by alias within the statement. The receiver
+// is left unchanged so that a keyspace can be shared between statements using different aliases.
+func (k *Keyspace) As(alias string) *Keyspace {
+	keyspace := *k
+	keyspace.alias = alias
+	return &keyspace
+}
+
+func (k *Keyspace) validate() error {
+	if k == nil {
+		return errors.New("a keyspace must be specified")
+	}
+	if k.bucket == "" {
+		return errors.New("keyspace bucket name cannot be empty")
+	}
+	if (k.scope == "") != (k.collection == "") {
+		return errors.New("keyspace scope and collection names must be specified together")
+	}
+
+	return nil
+}
+
+// String returns the escaped keyspace, including its alias.
+func (k *Keyspace) String() string {
+	var keyspace string
+	if k.scope == "" {
+		keyspace = Identifier(k.bucket)
+	} else {
+		keyspace = Path(k.bucket, k.scope, k.collection)
+	}
+
+	if k.alias != "" {
+		keyspace += " AS " + Identifier(k.alias)
+	}
+
+	return keyspace
+}
+
+// Assignment sets the field at a path to a value, it is created using Set.
+type Assignment struct {
+	path  string
+	value interface{}
+}
+
+// Set returns an Assignment of value to a top level field.
+func Set(field string, value interface{}) Assignment {
+	return SetPath([]string{field}, value)
+}
+
+// SetPath returns an Assignment of value to the nested field at path, e.g. []string{"address", "city"}.
+func SetPath(path []string, value interface{}) Assignment {
+	return Assignment{
+		path:  Path(path...),
+		value: value,
+	}
+}
+
+type expression struct {
+	text string
+	args []interface{}
+}
+
+func newExpressions(exprs []string) []expression {
+	expressions := make([]expression, len(exprs))
+	for i, expr := range exprs {
+		expressions[i] = expression{text: expr}
+	}
+
+	return expressions
+}
+
+// statementWriter renders a statement, numbering positional placeholders in the order that they
+// are written.
+type statementWriter struct {
+	text       strings.Builder
+	positional []interface{}
+	named      map[string]interface{}
+	err        error
+
+	// namedValues binds the values which builders write themselves, such as keys and assigned values,
+	// as named arguments so that they can be used alongside named arguments within expressions.
+	namedValues bool
+	values      int
+	generated   map[string]bool
+}
+
+// buildStatement renders a statement using write.  Values which the builder writes itself are bound
+// positionally, unless the expressions of the statement use named arguments in which case the statement
+// is rendered again binding them as named arguments.
+func buildStatement(write func(w *statementWriter)) (*Statement, error) {
+	w := &statementWriter{}
+	write(w)
+	if w.err == nil && w.values > 0 && len(w.named) > 0 {
+		w = &statementWriter{namedValues: true}
+		write(w)
+	}
+
+	return w.build()
+}
+
+func (w *statementWriter) fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *statementWriter) writeString(text string) {
+	w.text.WriteString(text)
+}
+
+// writeValue writes a placeholder bound to a value which the builder writes itself, in the parameter
+// style of the statement.
+func (w *statementWriter) writeValue(value interface{}) {
+	if named, ok := value.(NamedArg); ok {
+		w.bindNamed(named)
+		w.text.WriteString("$" + named.Name)
+		return
+	}
+
+	w.values++
+	if !w.namedValues {
+		w.writePositional(value)
+		return
+	}
+
+	name := "_v" + strconv.Itoa(w.values)
+	if _, ok := w.named[name]; ok {
+		w.fail(errors.New("named argument " + name + " is reserved for values bound by the builder"))
+		return
+	}
+	w.bindNamed(NamedArg{Name: name, Value: value})
+	if w.generated == nil {
+		w.generated = make(map[string]bool)
+	}
+	w.generated[name] = true
+	w.text.WriteString("$" + name)
+}
+
+// writePositional writes a positional placeholder bound to value.
+func (w *statementWriter) writePositional(value interface{}) {
+	w.positional = append(w.positional, value)
+	w.text.WriteString("$" + strconv.Itoa(len(w.positional)))
+}
+
+func (w *statementWriter) bindNamed(named NamedArg) {
+	if named.Name == "" {
+		w.fail(errors.New("named argument names cannot be empty"))
+		return
+	}
+	if w.generated[named.Name] {
+		w.fail(errors.New("named argument " + named.Name + " is reserved for values bound by the builder"))
+		return
+	}
+	if w.named == nil {
+		w.named = make(map[string]interface{})
+	}
+	w.named[named.Name] = named.Value
+}
+
+// writeExpression writes expr, binding each ? outside of a string literal, escaped identifier or
+// comment to the next positional argument. An expression ending inside a line comment is terminated
+// with a newline so that the comment cannot swallow the clauses written after it.
+func (w *statementWriter) writeExpression(expr expression) {
+	var positional []interface{}
+	for _, arg := range expr.args {
+		if named, ok := arg.(NamedArg); ok {
+			w.bindNamed(named)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	text := []rune(expr.text)
+	var quote rune
+	var comment string
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		var next rune
+		if i+1 < len(text) {
+			next = text[i+1]
+		}
+
+		switch {
+		case comment == "--":
+			if c == '\n' {
+				comment = ""
+			}
+		case comment == "/*":
+			if c == '*' && next == '/' {
+				comment = ""
+				w.text.WriteRune(c)
+				c = next
+				i++
+			}
+		case quote != 0:
+			if c == '\\' && next != 0 {
+				// Escaped characters, including quotes, never end the string.
+				w.text.WriteRune(c)
+				c = next
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && next == '-', c == '/' && next == '*':
+			comment = string([]rune{c, next})
+			w.text.WriteRune(c)
+			c = next
+			i++
+		case c == '?':
+			if len(positional) == 0 {
+				w.fail(errors.New("expression has more ? placeholders than positional arguments: " + expr.text))
+				continue
+			}
+			w.writePositional(positional[0])
+			positional = positional[1:]
+			continue
+		}
+		w.text.WriteRune(c)
+	}
+
+	switch {
+	case comment == "--":
+		w.text.WriteRune('\n')
+	case comment == "/*":
+		w.fail(errors.New("expression has an unterminated block comment: " + expr.text))
+	case quote != 0:
+		w.fail(errors.New("expression has an unterminated string literal or identifier: " + expr.text))
+	}
+
+	if len(positional) > 0 {
+		w.fail(errors.New("expression has fewer ? placeholders than positional arguments: " + expr.text))
+	}
+}
+
+func (w *statementWriter) writeExpressions(prefix string, exprs []expression, separator string) {
+	if len(exprs) == 0 {
+		return
+	}
+
+	w.text.WriteString(prefix)
+	for i, expr := range exprs {
+		if i > 0 {
+			w.text.WriteString(separator)
+		}
+		w.writeExpression(expr)
+	}
+}
+
+// writeConditions writes each of conds in parentheses, joined by AND, so that operators of lower
+// precedence within one condition cannot combine with its neighbours.
+func (w *statementWriter) writeConditions(prefix string, conds []expression) {
+	if len(conds) == 0 {
+		return
+	}
+
+	w.text.WriteString(prefix)
+	for i, cond := range conds {
+		if i > 0 {
+			w.text.WriteString(" AND ")
+		}
+		w.text.WriteString("(")
+		w.writeExpression(cond)
+		w.text.WriteString(")")
+	}
+}
+
+func (w *statementWriter) writeAssignments(prefix string, assignments []Assignment) {
+	if len(assignments) == 0 {
+		return
+	}
+
+	w.text.WriteString(prefix)
+	for i, assignment := range assignments {
+		if i > 0 {
+			w.text.WriteString(", ")
+		}
+		w.text.WriteString(assignment.path + " = ")
+		w.writeValue(assignment.value)
+	}
+}
+
+func (w *statementWriter) writeKeyspace(prefix string, keyspace *Keyspace) {
+	if err := keyspace.validate(); err != nil {
+		w.fail(err)
+		return
+	}
+
+	w.text.WriteString(prefix + keyspace.String())
+}
+
+func (w *statementWriter) writeUseKeys(keys []interface{}) {
+	if len(keys) == 0 {
+		return
+	}
+
+	w.text.WriteString(" USE KEYS ")
+	if len(keys) == 1 {
+		w.writeValue(keys[0])
+		return
+	}
+
+	w.writeValue(keys)
+}
+
+func (w *statementWriter) writeLimit(prefix string, value int) {
+	if value >= 0 {
+		w.text.WriteString(prefix + strconv.Itoa(value))
+	}
+}
+
+func (w *statementWriter) build() (*Statement, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	if len(w.positional) > 0 && len(w.named) > 0 {
+		return nil, errors.New("positional and named arguments cannot be used in the same statement")
+	}
+
+	return &Statement{
+		Text:                 w.text.String(),
+		PositionalParameters: w.positional,
+		NamedParameters:      w.named,
+	}, nil
+}
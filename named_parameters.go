@@ -0,0 +1,39 @@
+package gocb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// namedParametersFromStruct returns the fields of value, which must be a struct or pointer to a struct,
+// encoded as they would be by json.Marshal and keyed by their encoded names.
+func namedParametersFromStruct(value interface{}) (map[string]json.RawMessage, error) {
+	structVal := reflect.ValueOf(value)
+	if structVal.Kind() == reflect.Ptr && !structVal.IsNil() {
+		structVal = structVal.Elem()
+	}
+	if structVal.Kind() != reflect.Struct {
+		return nil, makeInvalidArgumentsError("NamedParametersStruct must be a struct or pointer to a struct")
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func namedParameterKey(name string) string {
+	if !strings.HasPrefix(name, "$") {
+		return "$" + name
+	}
+
+	return name
+}
@@ -0,0 +1,310 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+)
+
+// OrphanedResponse describes a response which the SDK received for a request that was no longer
+// in the system, usually because it had already timed out.  Orphaned responses are read from the
+// log messages of the underlying client, see OrphanReporterConfig for the limits this imposes.
+// UNCOMMITTED: This API may change in the future.
+type OrphanedResponse struct {
+	Service ServiceType
+
+	// Operation is the name of the command which the response was for, e.g. CMD_GET.
+	Operation      string
+	OperationID    string
+	Endpoint       string
+	ConnectionID   string
+	ServerDuration time.Duration
+
+	// TimeSinceTimeout is the time between the SDK returning a timeout for the request and the
+	// orphaned response being reported.  It is only set if TimeoutObserved is true, orphaned
+	// responses are reported at the end of each OrphanReporterConfig.ReportInterval.
+	TimeSinceTimeout time.Duration
+	TimeoutObserved  bool
+}
+
+// OrphanStats are the aggregated statistics of the orphaned responses reported so far.  Only a
+// sample of orphaned responses is reported, the slowest OrphanReporterConfig.SampleSize responses
+// of each ReportInterval for each bucket, so the statistics undercount the orphaned responses which
+// the SDK received.  No responses are counted if the logger of the underlying client has been
+// replaced by calling gocbcore.SetLogger directly, see OrphanReporterConfig.
+// UNCOMMITTED: This API may change in the future.
+type OrphanStats struct {
+	Total             uint64
+	ByEndpoint        map[string]uint64
+	ByOperation       map[string]uint64
+	MaxServerDuration time.Duration
+	LastReported      time.Time
+}
+
+type orphanReporter struct {
+	callback func(response OrphanedResponse)
+	channel  chan<- OrphanedResponse
+
+	lock      sync.Mutex
+	stats     OrphanStats
+	clientIDs map[string]struct{}
+}
+
+func newOrphanReporter(config OrphanReporterConfig) *orphanReporter {
+	return &orphanReporter{
+		callback: config.Callback,
+		channel:  config.Channel,
+		stats: OrphanStats{
+			ByEndpoint:  make(map[string]uint64),
+			ByOperation: make(map[string]uint64),
+		},
+		clientIDs: make(map[string]struct{}),
+	}
+}
+
+// addClientID adds the id of an agent of the underlying client whose orphaned responses are
+// delivered to the reporter.
+func (r *orphanReporter) addClientID(clientID string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	r.clientIDs[clientID] = struct{}{}
+	r.lock.Unlock()
+}
+
+// ownsConnection returns whether connectionID belongs to one of the agents of the reporter.  The
+// underlying client prefixes the id of each connection with the id of the agent which owns it.
+func (r *orphanReporter) ownsConnection(connectionID string) bool {
+	clientID := connectionID
+	if idx := strings.IndexByte(connectionID, '/'); idx >= 0 {
+		clientID = connectionID[:idx]
+	}
+
+	r.lock.Lock()
+	_, ok := r.clientIDs[clientID]
+	r.lock.Unlock()
+
+	return ok
+}
+
+func (r *orphanReporter) report(response OrphanedResponse, reportedAt time.Time) {
+	r.lock.Lock()
+	r.stats.Total++
+	r.stats.ByEndpoint[response.Endpoint]++
+	r.stats.ByOperation[response.Operation]++
+	if response.ServerDuration > r.stats.MaxServerDuration {
+		r.stats.MaxServerDuration = response.ServerDuration
+	}
+	r.stats.LastReported = reportedAt
+	r.lock.Unlock()
+
+	if r.callback != nil {
+		r.callback(response)
+	}
+
+	if r.channel != nil {
+		select {
+		case r.channel <- response:
+		default:
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Dropped orphaned response as the channel was not ready")
+		}
+	}
+}
+
+func (r *orphanReporter) Stats() OrphanStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	stats := r.stats
+	stats.ByEndpoint = make(map[string]uint64, len(r.stats.ByEndpoint))
+	for endpoint, count := range r.stats.ByEndpoint {
+		stats.ByEndpoint[endpoint] = count
+	}
+	stats.ByOperation = make(map[string]uint64, len(r.stats.ByOperation))
+	for operation, count := range r.stats.ByOperation {
+		stats.ByOperation[operation] = count
+	}
+
+	return stats
+}
+
+// The underlying client only surfaces orphaned responses through its process wide logger, so
+// reports are intercepted from the log and each response is delivered to the reporter of the
+// cluster which owns the connection that received it.
+var orphanReporters struct {
+	lock      sync.Mutex
+	count     int32
+	reporters []*orphanReporter
+}
+
+func registerOrphanReporter(reporter *orphanReporter) {
+	orphanReporters.lock.Lock()
+	orphanReporters.reporters = append(orphanReporters.reporters, reporter)
+	atomic.StoreInt32(&orphanReporters.count, int32(len(orphanReporters.reporters)))
+	orphanReporters.lock.Unlock()
+}
+
+func unregisterOrphanReporter(reporter *orphanReporter) {
+	orphanReporters.lock.Lock()
+	for i, r := range orphanReporters.reporters {
+		if r == reporter {
+			orphanReporters.reporters = append(orphanReporters.reporters[:i], orphanReporters.reporters[i+1:]...)
+			break
+		}
+	}
+	atomic.StoreInt32(&orphanReporters.count, int32(len(orphanReporters.reporters)))
+	orphanReporters.lock.Unlock()
+}
+
+func hasOrphanReporters() bool {
+	return atomic.LoadInt32(&orphanReporters.count) > 0
+}
+
+// orphanTimeouts remembers when recent key-value timeouts occurred so that the time between a timeout
+// and its orphaned response can be reported.
+var orphanTimeouts = newRecentKeys(1024)
+
+// recentKeys remembers when each of the most recently recorded keys was recorded, forgetting the
+// oldest keys once it is full.
+type recentKeys struct {
+	lock  sync.Mutex
+	times map[string]time.Time
+	keys  []string
+	next  int
+}
+
+func newRecentKeys(size int) *recentKeys {
+	return &recentKeys{
+		times: make(map[string]time.Time, size),
+		keys:  make([]string, size),
+	}
+}
+
+func (t *recentKeys) record(key string, at time.Time) {
+	t.lock.Lock()
+	if oldKey := t.keys[t.next]; oldKey != "" {
+		delete(t.times, oldKey)
+	}
+	t.keys[t.next] = key
+	t.next = (t.next + 1) % len(t.keys)
+	t.times[key] = at
+	t.lock.Unlock()
+}
+
+func (t *recentKeys) lookup(key string) (time.Time, bool) {
+	t.lock.Lock()
+	at, ok := t.times[key]
+	t.lock.Unlock()
+
+	return at, ok
+}
+
+func orphanTimeoutKey(connectionID, opaque string) string {
+	return connectionID + "|" + opaque
+}
+
+// maybeRecordOrphanTimeout remembers err if it is a key-value timeout which may later produce an
+// orphaned response.
+func maybeRecordOrphanTimeout(err error) {
+	if err == nil || !hasOrphanReporters() {
+		return
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) && timeoutErr.LastConnectionID != "" && timeoutErr.Opaque != "" {
+		orphanTimeouts.record(orphanTimeoutKey(timeoutErr.LastConnectionID, timeoutErr.Opaque), time.Now())
+	}
+}
+
+// coreOrphanLogPrefix is the start of the message which the underlying client logs its orphaned
+// responses reports with, the report itself is the JSON argument of the message.
+const coreOrphanLogPrefix = "Orphaned responses observed"
+
+func init() {
+	// Without a logger the underlying client would drop the orphaned responses reports.  This is
+	// installed before an application can set a logger of its own on the underlying client, which
+	// replaces it and disables orphan reporting.
+	gocbcore.SetLogger(&orphanLogger{})
+}
+
+type jsonOrphanItem struct {
+	ConnectionID     string `json:"c"`
+	OperationID      string `json:"i"`
+	Endpoint         string `json:"r"`
+	ServerDurationUs uint64 `json:"d"`
+	ServiceType      string `json:"s"`
+}
+
+type jsonOrphanReport struct {
+	Service string           `json:"service"`
+	Count   int              `json:"count"`
+	Top     []jsonOrphanItem `json:"top"`
+}
+
+func handleCoreOrphanReport(data []byte) {
+	var report jsonOrphanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to parse orphaned responses report: %s", err)
+		return
+	}
+
+	orphanReporters.lock.Lock()
+	reporters := make([]*orphanReporter, len(orphanReporters.reporters))
+	copy(reporters, orphanReporters.reporters)
+	orphanReporters.lock.Unlock()
+
+	now := time.Now()
+	for _, item := range report.Top {
+		response := OrphanedResponse{
+			Service:        ServiceTypeKeyValue,
+			Operation:      strings.TrimPrefix(item.ServiceType, "kv:"),
+			OperationID:    item.OperationID,
+			Endpoint:       item.Endpoint,
+			ConnectionID:   item.ConnectionID,
+			ServerDuration: time.Duration(item.ServerDurationUs) * time.Microsecond,
+		}
+
+		if timedOutAt, ok := orphanTimeouts.lookup(orphanTimeoutKey(item.ConnectionID, item.OperationID)); ok {
+			response.TimeSinceTimeout = now.Sub(timedOutAt)
+			response.TimeoutObserved = true
+		}
+
+		for _, reporter := range reporters {
+			if reporter.ownsConnection(item.ConnectionID) {
+				reporter.report(response, now)
+			}
+		}
+	}
+}
+
+// orphanLogger intercepts the orphaned responses reports logged by the underlying client before
+// passing every message enabled for LogSubsystemCore on to the wrapped logger.
+type orphanLogger struct {
+	wrapped gocbcore.Logger
+}
+
+func (l *orphanLogger) Log(level gocbcore.LogLevel, offset int, format string, v ...interface{}) error {
+	if strings.HasPrefix(format, coreOrphanLogPrefix) {
+		for _, arg := range v {
+			switch data := arg.(type) {
+			case []byte:
+				handleCoreOrphanReport(data)
+			case string:
+				handleCoreOrphanReport([]byte(data))
+			}
+		}
+	}
+
+	if l.wrapped == nil || !logLevelEnabled(LogSubsystemCore, LogLevel(level)) {
+		return nil
+	}
+
+	return l.wrapped.Log(level, offset+1, format, v...)
+}
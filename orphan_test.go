@@ -0,0 +1,102 @@
+package gocb
+
+import (
+	"go/build"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+)
+
+func (suite *UnitTestSuite) TestOrphanReporter() {
+	var called []OrphanedResponse
+	ch := make(chan OrphanedResponse, 1)
+	reporter := newOrphanReporter(OrphanReporterConfig{
+		Callback: func(response OrphanedResponse) {
+			called = append(called, response)
+		},
+		Channel: ch,
+	})
+	reporter.addClientID("a1b2c3")
+	registerOrphanReporter(reporter)
+	defer unregisterOrphanReporter(reporter)
+
+	// Reporters only receive the orphaned responses of their own connections.
+	other := newOrphanReporter(OrphanReporterConfig{})
+	other.addClientID("f6e5d4")
+	registerOrphanReporter(other)
+	defer unregisterOrphanReporter(other)
+
+	maybeRecordOrphanTimeout(&TimeoutError{
+		InnerError:       ErrUnambiguousTimeout,
+		Opaque:           "0x1a",
+		LastConnectionID: "a1b2c3/d4e5f6",
+	})
+
+	report := []byte(`{"service":"kv","count":2,"top":[` +
+		`{"c":"a1b2c3/d4e5f6","i":"0x1a","r":"10.112.191.102:11210","d":1500,"s":"kv:CMD_GET"},` +
+		`{"c":"a1b2c3/d4e5f6","i":"0x1b","r":"10.112.191.102:11210","d":250,"s":"kv:CMD_SET"}]}`)
+
+	logger := &orphanLogger{}
+	err := logger.Log(gocbcore.LogWarn, 0, "Orphaned responses observed:\n %s", report)
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(called, 2)
+	suite.Assert().Equal(ServiceTypeKeyValue, called[0].Service)
+	suite.Assert().Equal("CMD_GET", called[0].Operation)
+	suite.Assert().Equal("0x1a", called[0].OperationID)
+	suite.Assert().Equal("10.112.191.102:11210", called[0].Endpoint)
+	suite.Assert().Equal("a1b2c3/d4e5f6", called[0].ConnectionID)
+	suite.Assert().Equal(1500*time.Microsecond, called[0].ServerDuration)
+	suite.Assert().True(called[0].TimeoutObserved)
+	suite.Assert().False(called[1].TimeoutObserved)
+
+	// The channel only has room for the first response, the second is dropped.
+	suite.Require().Len(ch, 1)
+	suite.Assert().Equal("CMD_GET", (<-ch).Operation)
+
+	stats := reporter.Stats()
+	suite.Assert().Equal(uint64(2), stats.Total)
+	suite.Assert().Equal(uint64(2), stats.ByEndpoint["10.112.191.102:11210"])
+	suite.Assert().Equal(uint64(1), stats.ByOperation["CMD_GET"])
+	suite.Assert().Equal(uint64(1), stats.ByOperation["CMD_SET"])
+	suite.Assert().Equal(1500*time.Microsecond, stats.MaxServerDuration)
+	suite.Assert().False(stats.LastReported.IsZero())
+
+	suite.Assert().Zero(other.Stats().Total)
+}
+
+func (suite *UnitTestSuite) TestOrphanLoggerForwards() {
+	logger := &testCaptureLogger{}
+	orphans := &orphanLogger{wrapped: getCoreLogger(logger)}
+
+	err := orphans.Log(gocbcore.LogInfo, 0, "hello %s", "world")
+	suite.Require().Nil(err, err)
+	suite.Assert().Len(logger.messages, 1)
+}
+
+// The orphaned responses are scraped from the log message of the underlying client, so make sure
+// that the version of the underlying client in use still logs the message and fields which we parse.
+func (suite *UnitTestSuite) TestOrphanLogFormatMatchesCore() {
+	const coreFormat = "Orphaned responses observed:\n %s"
+	suite.Assert().True(strings.HasPrefix(coreFormat, coreOrphanLogPrefix))
+
+	pkg, err := build.Import("github.com/couchbase/gocbcore/v9", ".", build.FindOnly)
+	suite.Require().Nil(err, err)
+
+	src, err := ioutil.ReadFile(filepath.Join(pkg.Dir, "zombielogger_component.go"))
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Contains(string(src), "logWarnf("+strconv.Quote(coreFormat)+", jsonBytes)")
+
+	for _, typ := range []reflect.Type{reflect.TypeOf(jsonOrphanReport{}), reflect.TypeOf(jsonOrphanItem{})} {
+		for i := 0; i < typ.NumField(); i++ {
+			tag := typ.Field(i).Tag.Get("json")
+			suite.Assert().Contains(string(src), `json:"`+tag+`"`)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package gocb
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrometheusHandlerOptions is the set of options available when creating a Prometheus handler.
+type PrometheusHandlerOptions struct {
+	// Meter is the meter from which operation counters and latency histograms are rendered, it must be
+	// an AggregatingMeter or a LoggingMeter.  If not specified then the meter configured on the cluster
+	// is used.  The rendered counters are cumulative, they are not reset when the meter is reset or
+	// when a LoggingMeter logs its metrics.
+	Meter Meter
+
+	// Namespace is the prefix applied to every metric name, the default is couchbase.
+	Namespace string
+
+	// DisableDiagnostics prevents the handler from running Diagnostics on every scrape, omitting the
+	// cluster and endpoint state metrics.
+	DisableDiagnostics bool
+}
+
+// totalsMeter is implemented by meters whose cumulative metrics can be rendered.
+type totalsMeter interface {
+	totalsSnapshot() []OperationMetrics
+}
+
+type prometheusHandler struct {
+	cluster            *Cluster
+	meter              totalsMeter
+	namespace          string
+	disableDiagnostics bool
+}
+
+// NewPrometheusHandler returns an http.Handler which renders the operation counters, latency
+// histograms, endpoint states and circuit breaker metrics of a cluster in the Prometheus text
+// exposition format.
+// UNCOMMITTED: This API may change in the future.
+func NewPrometheusHandler(cluster *Cluster, opts *PrometheusHandlerOptions) http.Handler {
+	if opts == nil {
+		opts = &PrometheusHandlerOptions{}
+	}
+
+	var meter totalsMeter
+	if opts.Meter != nil {
+		meter, _ = opts.Meter.(totalsMeter)
+	} else {
+		meter, _ = cluster.meter.(totalsMeter)
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "couchbase"
+	}
+
+	return &prometheusHandler{
+		cluster:            cluster,
+		meter:              meter,
+		namespace:          namespace,
+		disableDiagnostics: opts.DisableDiagnostics,
+	}
+}
+
+func (h *prometheusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	var buf bytes.Buffer
+	h.writeMetrics(&buf)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to write prometheus metrics: %s", err)
+	}
+}
+
+func (h *prometheusHandler) writeMetrics(w *bytes.Buffer) {
+	if h.meter != nil {
+		h.writeOperationMetrics(w, h.meter.totalsSnapshot())
+	}
+
+	h.writeCircuitBreakerMetrics(w)
+
+	if !h.disableDiagnostics {
+		report, err := h.cluster.Diagnostics(nil)
+		if err != nil {
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to fetch diagnostics for prometheus metrics: %s", err)
+		} else {
+			h.writeDiagnosticsMetrics(w, report)
+		}
+	}
+}
+
+func (h *prometheusHandler) writeOperationMetrics(w *bytes.Buffer, snapshot []OperationMetrics) {
+	name := h.namespace + "_operations_total"
+	writePrometheusHeader(w, name, "counter", "Total number of operations by outcome.")
+	for _, metrics := range snapshot {
+		labels := operationLabels(metrics)
+		writePrometheusSample(w, name, append(labels, "outcome", "success"), float64(metrics.Successes))
+
+		classes := make([]string, 0, len(metrics.Errors))
+		for class := range metrics.Errors {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			writePrometheusSample(w, name, append(labels, "outcome", class), float64(metrics.Errors[class]))
+		}
+	}
+
+	name = h.namespace + "_operation_timeouts_total"
+	writePrometheusHeader(w, name, "counter", "Total number of operations which timed out.")
+	for _, metrics := range snapshot {
+		writePrometheusSample(w, name, operationLabels(metrics), float64(metrics.Timeouts))
+	}
+
+	name = h.namespace + "_operation_retries_total"
+	writePrometheusHeader(w, name, "counter", "Total number of retry attempts made by operations.")
+	for _, metrics := range snapshot {
+		writePrometheusSample(w, name, operationLabels(metrics), float64(metrics.Retries))
+	}
+
+	name = h.namespace + "_operation_duration_seconds"
+	writePrometheusHeader(w, name, "histogram", "Latency of operations.")
+	for _, metrics := range snapshot {
+		labels := operationLabels(metrics)
+		latency := metrics.Latency
+		for _, bucket := range latency.Buckets {
+			le := strconv.FormatFloat(bucket.UpperBound.Seconds(), 'g', -1, 64)
+			writePrometheusSample(w, name+"_bucket", append(labels, "le", le), float64(bucket.Count))
+		}
+		writePrometheusSample(w, name+"_bucket", append(labels, "le", "+Inf"), float64(latency.Count))
+		writePrometheusSample(w, name+"_sum", labels, latency.Sum.Seconds())
+		writePrometheusSample(w, name+"_count", labels, float64(latency.Count))
+	}
+
+	name = h.namespace + "_retry_budget_exhausted_total"
+	writePrometheusHeader(w, name, "counter", "Total number of operations which failed because the retry budget was exhausted.")
+	for _, metrics := range snapshot {
+		writePrometheusSample(w, name, operationLabels(metrics), float64(metrics.RetryBudgetExhausted))
+	}
+}
+
+func (h *prometheusHandler) writeCircuitBreakerMetrics(w *bytes.Buffer) {
+	enabled := 1.0
+	if h.cluster.circuitBreakerConfig.Disabled {
+		enabled = 0
+	}
+
+	name := h.namespace + "_circuit_breaker_enabled"
+	writePrometheusHeader(w, name, "gauge", "Whether circuit breakers are enabled.")
+	writePrometheusSample(w, name, nil, enabled)
+
+	name = h.namespace + "_circuit_breaker_estimated_state"
+	writePrometheusHeader(w, name, "gauge",
+		"The state of the key-value circuit breakers as estimated by the SDK from the responses of every connection.")
+	writePrometheusSample(w, name, []string{"state", circuitBreakerStateToString(h.cluster.breakers.state())}, 1)
+}
+
+func (h *prometheusHandler) writeDiagnosticsMetrics(w *bytes.Buffer, report *DiagnosticsResult) {
+	name := h.namespace + "_cluster_state"
+	writePrometheusHeader(w, name, "gauge", "The current state of the cluster connection.")
+	writePrometheusSample(w, name, []string{"state", clusterStateToString(report.State)}, 1)
+
+	services := make([]string, 0, len(report.Services))
+	for service := range report.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	name = h.namespace + "_endpoint_state"
+	writePrometheusHeader(w, name, "gauge", "The current state of each endpoint.")
+	for _, service := range services {
+		for _, endpoint := range report.Services[service] {
+			writePrometheusSample(w, name, []string{
+				"service", service,
+				"id", endpoint.ID,
+				"local", endpoint.Local,
+				"remote", endpoint.Remote,
+				"state", endpointStateToString(endpoint.State),
+			}, 1)
+		}
+	}
+
+	name = h.namespace + "_endpoint_last_activity_seconds"
+	writePrometheusHeader(w, name, "gauge", "Time since the last activity on each endpoint.")
+	for _, service := range services {
+		for _, endpoint := range report.Services[service] {
+			writePrometheusSample(w, name, []string{
+				"service", service,
+				"id", endpoint.ID,
+			}, time.Since(endpoint.LastActivity).Seconds())
+		}
+	}
+}
+
+func operationLabels(metrics OperationMetrics) []string {
+	return []string{
+		"service", serviceTypeToString(metrics.Service),
+		"operation", metrics.Operation,
+	}
+}
+
+func writePrometheusHeader(w *bytes.Buffer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// writePrometheusSample writes a single sample, labels is a list of alternating label names and values.
+func writePrometheusSample(w *bytes.Buffer, name string, labels []string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(labels[i])
+			w.WriteString(`="`)
+			w.WriteString(prometheusLabelEscaper.Replace(labels[i+1]))
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.WriteByte('\n')
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
@@ -0,0 +1,128 @@
+package gocb
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestPrometheusHandler() {
+	info := &gocbcore.DiagnosticInfo{
+		ConfigRev: 1,
+		State:     gocbcore.ClusterStateOnline,
+		MemdConns: []gocbcore.MemdConnInfo{
+			{
+				LastActivity: time.Now(),
+				LocalAddr:    "10.112.191.101",
+				RemoteAddr:   "10.112.191.102",
+				Scope:        "bucket",
+				State:        gocbcore.EndpointStateConnected,
+				ID:           "0xc000094120",
+			},
+		},
+	}
+
+	provider := new(mockDiagnosticsProvider)
+	provider.
+		On("Diagnostics", mock.AnythingOfType("gocbcore.DiagnosticsOptions")).
+		Return(info, nil)
+
+	cli := new(mockConnectionManager)
+	cli.On("getDiagnosticsProvider", "").Return(provider, nil)
+
+	meter := NewAggregatingMeter(&AggregatingMeterOptions{
+		Buckets: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+	})
+	meter.RecordOperation(&OperationMetric{
+		Service:   ServiceTypeKeyValue,
+		Operation: "Get",
+		Duration:  500 * time.Microsecond,
+	})
+	meter.RecordOperation(&OperationMetric{
+		Service:       ServiceTypeKeyValue,
+		Operation:     "Get",
+		Duration:      20 * time.Millisecond,
+		Err:           ErrUnambiguousTimeout,
+		RetryAttempts: 4,
+	})
+
+	cluster := suite.newCluster(cli)
+	cluster.meter = meter
+	cluster.breakers = newCircuitBreakerMonitor(CircuitBreakerConfig{VolumeThreshold: 1})
+	cluster.breakers.complete(ErrTimeout)
+
+	rec := httptest.NewRecorder()
+	NewPrometheusHandler(cluster, nil).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(rec.Body)
+	suite.Require().Nil(err, err)
+	out := string(body)
+
+	suite.Assert().Equal("text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	suite.Assert().Contains(out, "# TYPE couchbase_operations_total counter\n")
+	suite.Assert().Contains(out, `couchbase_operations_total{service="kv",operation="Get",outcome="success"} 1`+"\n")
+	suite.Assert().Contains(out, `couchbase_operations_total{service="kv",operation="Get",outcome="timeout"} 1`+"\n")
+	suite.Assert().Contains(out, `couchbase_operation_timeouts_total{service="kv",operation="Get"} 1`+"\n")
+	suite.Assert().Contains(out, `couchbase_operation_retries_total{service="kv",operation="Get"} 4`+"\n")
+	suite.Assert().Contains(out, "# TYPE couchbase_operation_duration_seconds histogram\n")
+	suite.Assert().Contains(out, `couchbase_operation_duration_seconds_bucket{service="kv",operation="Get",le="0.001"} 1`+"\n")
+	suite.Assert().Contains(out, `couchbase_operation_duration_seconds_bucket{service="kv",operation="Get",le="0.01"} 1`+"\n")
+	suite.Assert().Contains(out, `couchbase_operation_duration_seconds_bucket{service="kv",operation="Get",le="+Inf"} 2`+"\n")
+	suite.Assert().Contains(out, `couchbase_operation_duration_seconds_sum{service="kv",operation="Get"} 0.0205`+"\n")
+	suite.Assert().Contains(out, `couchbase_operation_duration_seconds_count{service="kv",operation="Get"} 2`+"\n")
+	suite.Assert().Contains(out, "couchbase_circuit_breaker_enabled 1\n")
+	suite.Assert().Contains(out, `couchbase_circuit_breaker_estimated_state{state="open"} 1`+"\n")
+	suite.Assert().Contains(out, `couchbase_cluster_state{state="online"} 1`+"\n")
+	suite.Assert().Contains(out,
+		`couchbase_endpoint_state{service="kv",id="0xc000094120",local="10.112.191.101",remote="10.112.191.102",state="connected"} 1`+"\n")
+}
+
+func (suite *UnitTestSuite) TestPrometheusLabelEscaping() {
+	meter := NewAggregatingMeter(nil)
+	meter.RecordOperation(&OperationMetric{
+		Service:   ServiceTypeQuery,
+		Operation: "say \"hi\"\\\n",
+	})
+
+	cluster := suite.newCluster(new(mockConnectionManager))
+	handler := NewPrometheusHandler(cluster, &PrometheusHandlerOptions{
+		Meter:              meter,
+		Namespace:          "app",
+		DisableDiagnostics: true,
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	suite.Assert().Contains(rec.Body.String(), `app_operations_total{service="query",operation="say \"hi\"\\\n",outcome="success"} 1`)
+	suite.Assert().NotContains(rec.Body.String(), "cluster_state")
+}
+
+func (suite *UnitTestSuite) TestPrometheusHandlerLoggingMeterIsCumulative() {
+	meter := NewLoggingMeter(nil)
+	meter.RecordOperation(&OperationMetric{
+		Service:   ServiceTypeKeyValue,
+		Operation: "Get",
+	})
+
+	cluster := suite.newCluster(new(mockConnectionManager))
+	cluster.meter = meter
+	handler := NewPrometheusHandler(cluster, &PrometheusHandlerOptions{DisableDiagnostics: true})
+
+	// Logging the metrics of an interval resets the meter but must not reset the exported counters.
+	meter.logRecordedMetrics()
+	meter.RecordOperation(&OperationMetric{
+		Service:   ServiceTypeKeyValue,
+		Operation: "Get",
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	suite.Assert().Contains(rec.Body.String(), `couchbase_operations_total{service="kv",operation="Get",outcome="success"} 2`+"\n")
+	suite.Require().Len(meter.Snapshot(), 1)
+	suite.Assert().Equal(uint64(1), meter.Snapshot()[0].Count)
+}
@@ -2,7 +2,6 @@ package gocb
 
 import (
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -45,7 +44,14 @@ type QueryOptions struct {
 	ClientContextID      string
 	PositionalParameters []interface{}
 	NamedParameters      map[string]interface{}
-	Metrics              bool
+
+	// NamedParametersStruct is a struct, or pointer to a struct, whose fields are used as named
+	// parameters, named according to their json tags.  It may be combined with NamedParameters, in
+	// which case NamedParameters takes precedence.
+	// UNCOMMITTED: This API may change in the future.
+	NamedParametersStruct interface{}
+
+	Metrics bool
 
 	// Raw provides a way to provide extra parameters in the request body for the query.
 	Raw map[string]interface{}
@@ -58,7 +64,7 @@ type QueryOptions struct {
 	//  UNCOMMITTED: This API may change in the future.
 	FlexIndex bool
 
-	parentSpan requestSpanContext
+	ParentSpan RequestSpanContext
 }
 
 func (opts *QueryOptions) toMap() (map[string]interface{}, error) {
@@ -91,7 +97,7 @@ func (opts *QueryOptions) toMap() (map[string]interface{}, error) {
 		execOpts["readonly"] = opts.Readonly
 	}
 
-	if opts.PositionalParameters != nil && opts.NamedParameters != nil {
+	if opts.PositionalParameters != nil && (opts.NamedParameters != nil || opts.NamedParametersStruct != nil) {
 		return nil, makeInvalidArgumentsError("Positional and named parameters must be used exclusively")
 	}
 
@@ -99,12 +105,19 @@ func (opts *QueryOptions) toMap() (map[string]interface{}, error) {
 		execOpts["args"] = opts.PositionalParameters
 	}
 
+	if opts.NamedParametersStruct != nil {
+		params, err := namedParametersFromStruct(opts.NamedParametersStruct)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range params {
+			execOpts[namedParameterKey(key)] = value
+		}
+	}
+
 	if opts.NamedParameters != nil {
 		for key, value := range opts.NamedParameters {
-			if !strings.HasPrefix(key, "$") {
-				key = "$" + key
-			}
-			execOpts[key] = value
+			execOpts[namedParameterKey(key)] = value
 		}
 	}
 
@@ -0,0 +1,287 @@
+package gocb
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// QueryProfile is the profiling information returned by a query executed with QueryProfileModePhases
+// or QueryProfileModeTimings.
+// UNCOMMITTED: This API may change in the future.
+type QueryProfile struct {
+	// PhaseTimes is the time spent in each phase of the query, e.g. parse, plan, indexScan or fetch.
+	PhaseTimes map[string]time.Duration
+
+	// PhaseCounts is the number of items processed by each phase of the query.
+	PhaseCounts map[string]uint64
+
+	// PhaseOperators is the number of operators executing each phase of the query.
+	PhaseOperators map[string]uint64
+
+	RequestTime   string
+	ServicingHost string
+
+	// ExecutionTimings is the executed plan with the statistics of each operator, it is only returned
+	// by queries executed with QueryProfileModeTimings.
+	ExecutionTimings *QueryPlanOperator
+}
+
+// ParseProfile returns the typed form of Profile, or nil if the query did not return a profile.
+// UNCOMMITTED: This API may change in the future.
+func (meta *QueryMetaData) ParseProfile() (*QueryProfile, error) {
+	if meta.Profile == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(meta.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile QueryProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+type jsonQueryProfile struct {
+	PhaseTimes       map[string]string  `json:"phaseTimes,omitempty"`
+	PhaseCounts      map[string]uint64  `json:"phaseCounts,omitempty"`
+	PhaseOperators   map[string]uint64  `json:"phaseOperators,omitempty"`
+	RequestTime      string             `json:"requestTime,omitempty"`
+	ServicingHost    string             `json:"servicingHost,omitempty"`
+	ExecutionTimings *QueryPlanOperator `json:"executionTimings,omitempty"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface.
+func (profile *QueryProfile) UnmarshalJSON(data []byte) error {
+	var jsonProfile jsonQueryProfile
+	if err := json.Unmarshal(data, &jsonProfile); err != nil {
+		return err
+	}
+
+	profile.PhaseTimes = make(map[string]time.Duration, len(jsonProfile.PhaseTimes))
+	for phase, phaseTime := range jsonProfile.PhaseTimes {
+		profile.PhaseTimes[phase] = parseQueryDuration(phaseTime)
+	}
+	profile.PhaseCounts = jsonProfile.PhaseCounts
+	profile.PhaseOperators = jsonProfile.PhaseOperators
+	profile.RequestTime = jsonProfile.RequestTime
+	profile.ServicingHost = jsonProfile.ServicingHost
+	profile.ExecutionTimings = jsonProfile.ExecutionTimings
+
+	return nil
+}
+
+// SlowestOperator returns the operator which spent the longest executing and waiting on services,
+// or nil if the profile does not include execution timings.
+func (profile *QueryProfile) SlowestOperator() *QueryPlanOperator {
+	if profile.ExecutionTimings == nil {
+		return nil
+	}
+
+	var slowest *QueryPlanOperator
+	profile.ExecutionTimings.Walk(func(op *QueryPlanOperator) bool {
+		if op.Stats != nil && (slowest == nil || op.Stats.ActiveTime() > slowest.Stats.ActiveTime()) {
+			slowest = op
+		}
+		return true
+	})
+
+	return slowest
+}
+
+// ScannedIndexes returns the indexes which were scanned by the query, or nil if the profile does not
+// include execution timings.
+func (profile *QueryProfile) ScannedIndexes() []QueryScannedIndex {
+	if profile.ExecutionTimings == nil {
+		return nil
+	}
+
+	return profile.ExecutionTimings.ScannedIndexes()
+}
+
+// QueryPlanOperator is a single operator within a query plan, along with the operators which feed it.
+// UNCOMMITTED: This API may change in the future.
+type QueryPlanOperator struct {
+	// Operator is the name of the operator, e.g. IndexScan3, Fetch or Filter.
+	Operator string
+
+	Index     string
+	Keyspace  string
+	Namespace string
+	Bucket    string
+	Scope     string
+	Using     string
+
+	// Stats are the execution statistics of the operator, they are only returned by queries executed
+	// with QueryProfileModeTimings.
+	Stats *QueryOperatorStats
+
+	Children []*QueryPlanOperator
+
+	// Properties contains every other field of the operator, e.g. spans, covers or condition.
+	Properties map[string]interface{}
+}
+
+type jsonQueryPlanOperator struct {
+	Operator  string                  `json:"#operator"`
+	Index     string                  `json:"index,omitempty"`
+	Keyspace  string                  `json:"keyspace,omitempty"`
+	Namespace string                  `json:"namespace,omitempty"`
+	Bucket    string                  `json:"bucket,omitempty"`
+	Scope     string                  `json:"scope,omitempty"`
+	Using     string                  `json:"using,omitempty"`
+	Stats     *jsonQueryOperatorStats `json:"#stats,omitempty"`
+	Children  []*QueryPlanOperator    `json:"~children,omitempty"`
+	Child     *QueryPlanOperator      `json:"~child,omitempty"`
+	Scans     []*QueryPlanOperator    `json:"scans,omitempty"`
+	Scan      *QueryPlanOperator      `json:"scan,omitempty"`
+}
+
+// queryPlanOperatorFields are the fields which are not included in QueryPlanOperator.Properties.
+var queryPlanOperatorFields = []string{
+	"#operator", "index", "keyspace", "namespace", "bucket", "scope", "using", "#stats", "~children", "~child",
+	"scans", "scan",
+}
+
+// UnmarshalJSON implements the Unmarshaler interface.
+func (op *QueryPlanOperator) UnmarshalJSON(data []byte) error {
+	var jsonOp jsonQueryPlanOperator
+	if err := json.Unmarshal(data, &jsonOp); err != nil {
+		return err
+	}
+
+	var properties map[string]interface{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	for _, field := range queryPlanOperatorFields {
+		delete(properties, field)
+	}
+
+	op.Operator = jsonOp.Operator
+	op.Index = jsonOp.Index
+	op.Keyspace = jsonOp.Keyspace
+	op.Namespace = jsonOp.Namespace
+	op.Bucket = jsonOp.Bucket
+	op.Scope = jsonOp.Scope
+	op.Using = jsonOp.Using
+	op.Properties = properties
+
+	if jsonOp.Stats != nil {
+		op.Stats = &QueryOperatorStats{
+			ItemsIn:       jsonOp.Stats.ItemsIn,
+			ItemsOut:      jsonOp.Stats.ItemsOut,
+			PhaseSwitches: jsonOp.Stats.PhaseSwitches,
+			ExecTime:      parseQueryDuration(jsonOp.Stats.ExecTime),
+			KernTime:      parseQueryDuration(jsonOp.Stats.KernTime),
+			ServTime:      parseQueryDuration(jsonOp.Stats.ServTime),
+		}
+	}
+
+	op.Children = append(op.Children, jsonOp.Children...)
+	if jsonOp.Child != nil {
+		op.Children = append(op.Children, jsonOp.Child)
+	}
+	op.Children = append(op.Children, jsonOp.Scans...)
+	if jsonOp.Scan != nil {
+		op.Children = append(op.Children, jsonOp.Scan)
+	}
+
+	return nil
+}
+
+// Walk calls fn for the operator and then each operator beneath it, depth first, stopping as soon as
+// fn returns false.
+func (op *QueryPlanOperator) Walk(fn func(op *QueryPlanOperator) bool) bool {
+	if !fn(op) {
+		return false
+	}
+
+	for _, child := range op.Children {
+		if !child.Walk(fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ScannedIndexes returns the indexes scanned by the operator and the operators beneath it.
+func (op *QueryPlanOperator) ScannedIndexes() []QueryScannedIndex {
+	var indexes []QueryScannedIndex
+	op.Walk(func(op *QueryPlanOperator) bool {
+		if op.Index != "" && strings.Contains(op.Operator, "Scan") {
+			indexes = append(indexes, QueryScannedIndex{
+				Name:      op.Index,
+				Keyspace:  op.Keyspace,
+				Namespace: op.Namespace,
+				Bucket:    op.Bucket,
+				Scope:     op.Scope,
+				Using:     op.Using,
+				IsPrimary: strings.HasPrefix(op.Operator, "PrimaryScan"),
+			})
+		}
+		return true
+	})
+
+	return indexes
+}
+
+// QueryOperatorStats are the execution statistics of a single query plan operator.
+// UNCOMMITTED: This API may change in the future.
+type QueryOperatorStats struct {
+	ItemsIn       uint64
+	ItemsOut      uint64
+	PhaseSwitches uint64
+
+	// ExecTime is the time spent executing the operator, KernTime is the time spent waiting to be
+	// scheduled and ServTime is the time spent waiting on other services such as the index or data
+	// service.
+	ExecTime time.Duration
+	KernTime time.Duration
+	ServTime time.Duration
+}
+
+// ActiveTime returns the time that the operator spent executing or waiting on other services.
+func (stats *QueryOperatorStats) ActiveTime() time.Duration {
+	return stats.ExecTime + stats.ServTime
+}
+
+type jsonQueryOperatorStats struct {
+	ItemsIn       uint64 `json:"#itemsIn,omitempty"`
+	ItemsOut      uint64 `json:"#itemsOut,omitempty"`
+	PhaseSwitches uint64 `json:"#phaseSwitches,omitempty"`
+	ExecTime      string `json:"execTime,omitempty"`
+	KernTime      string `json:"kernTime,omitempty"`
+	ServTime      string `json:"servTime,omitempty"`
+}
+
+// QueryScannedIndex describes an index which was scanned by a query.
+// UNCOMMITTED: This API may change in the future.
+type QueryScannedIndex struct {
+	Name      string
+	Keyspace  string
+	Namespace string
+	Bucket    string
+	Scope     string
+	Using     string
+	IsPrimary bool
+}
+
+func parseQueryDuration(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to parse query profile duration: %s", err)
+	}
+
+	return duration
+}
@@ -0,0 +1,125 @@
+package gocb
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const testQueryProfileTimings = `{
+	"phaseCounts": {"fetch": 16, "indexScan": 16},
+	"phaseOperators": {"authorize": 1, "fetch": 1, "indexScan": 1},
+	"phaseTimes": {"authorize": "12.5µs", "fetch": "2.5ms", "indexScan": "10ms", "parse": "250µs"},
+	"requestTime": "2020-05-13T10:31:02.123Z",
+	"servicingHost": "10.112.191.101:8091",
+	"executionTimings": {
+		"#operator": "Authorize",
+		"#stats": {"#phaseSwitches": 3, "execTime": "1µs", "servTime": "12µs"},
+		"~child": {
+			"#operator": "Sequence",
+			"~children": [
+				{
+					"#operator": "IndexScan3",
+					"#stats": {"#itemsOut": 16, "#phaseSwitches": 67, "execTime": "120µs", "kernTime": "2ms", "servTime": "9.8ms"},
+					"index": "def_type",
+					"keyspace": "travel-sample",
+					"namespace": "default",
+					"using": "gsi",
+					"spans": [{"range": [{"high": "\"airline\"", "inclusion": 3, "low": "\"airline\""}]}]
+				},
+				{
+					"#operator": "Fetch",
+					"#stats": {"#itemsIn": 16, "#itemsOut": 16, "execTime": "400µs", "servTime": "2ms"},
+					"keyspace": "travel-sample",
+					"namespace": "default"
+				},
+				{
+					"#operator": "IntersectScan",
+					"scans": [
+						{"#operator": "PrimaryScan3", "index": "#primary", "keyspace": "travel-sample", "using": "gsi"}
+					]
+				}
+			]
+		}
+	}
+}`
+
+func (suite *UnitTestSuite) TestQueryProfileUnmarshal() {
+	var profile QueryProfile
+	suite.Require().Nil(json.Unmarshal([]byte(testQueryProfileTimings), &profile))
+
+	suite.Assert().Equal(10*time.Millisecond, profile.PhaseTimes["indexScan"])
+	suite.Assert().Equal(12500*time.Nanosecond, profile.PhaseTimes["authorize"])
+	suite.Assert().Equal(uint64(16), profile.PhaseCounts["fetch"])
+	suite.Assert().Equal(uint64(1), profile.PhaseOperators["indexScan"])
+	suite.Assert().Equal("10.112.191.101:8091", profile.ServicingHost)
+
+	root := profile.ExecutionTimings
+	suite.Require().NotNil(root)
+	suite.Assert().Equal("Authorize", root.Operator)
+	suite.Require().Len(root.Children, 1)
+
+	sequence := root.Children[0]
+	suite.Require().Len(sequence.Children, 3)
+
+	scan := sequence.Children[0]
+	suite.Assert().Equal("IndexScan3", scan.Operator)
+	suite.Assert().Equal("def_type", scan.Index)
+	suite.Assert().Equal("gsi", scan.Using)
+	suite.Assert().Contains(scan.Properties, "spans")
+	suite.Assert().NotContains(scan.Properties, "#stats")
+	suite.Require().NotNil(scan.Stats)
+	suite.Assert().Equal(uint64(16), scan.Stats.ItemsOut)
+	suite.Assert().Equal(uint64(67), scan.Stats.PhaseSwitches)
+	suite.Assert().Equal(2*time.Millisecond, scan.Stats.KernTime)
+	suite.Assert().Equal(9920*time.Microsecond, scan.Stats.ActiveTime())
+}
+
+func (suite *UnitTestSuite) TestQueryProfileHelpers() {
+	var profile QueryProfile
+	suite.Require().Nil(json.Unmarshal([]byte(testQueryProfileTimings), &profile))
+
+	slowest := profile.SlowestOperator()
+	suite.Require().NotNil(slowest)
+	suite.Assert().Equal("IndexScan3", slowest.Operator)
+
+	indexes := profile.ScannedIndexes()
+	suite.Require().Len(indexes, 2)
+	suite.Assert().Equal(QueryScannedIndex{
+		Name:      "def_type",
+		Keyspace:  "travel-sample",
+		Namespace: "default",
+		Using:     "gsi",
+	}, indexes[0])
+	suite.Assert().Equal("#primary", indexes[1].Name)
+	suite.Assert().True(indexes[1].IsPrimary)
+
+	var operators []string
+	profile.ExecutionTimings.Walk(func(op *QueryPlanOperator) bool {
+		operators = append(operators, op.Operator)
+		return op.Operator != "IndexScan3"
+	})
+	suite.Assert().Equal([]string{"Authorize", "Sequence", "IndexScan3"}, operators)
+
+	phasesOnly := QueryProfile{}
+	suite.Assert().Nil(phasesOnly.SlowestOperator())
+	suite.Assert().Nil(phasesOnly.ScannedIndexes())
+}
+
+func (suite *UnitTestSuite) TestQueryMetaDataParseProfile() {
+	var resp jsonQueryResponse
+	suite.Require().Nil(json.Unmarshal([]byte(`{"requestID": "1", "status": "success", "profile": `+
+		testQueryProfileTimings+`}`), &resp))
+
+	var meta QueryMetaData
+	suite.Require().Nil(meta.fromData(resp))
+
+	profile, err := meta.ParseProfile()
+	suite.Require().Nil(err)
+	suite.Require().NotNil(profile)
+	suite.Assert().Equal(2500*time.Microsecond, profile.PhaseTimes["fetch"])
+	suite.Assert().Equal("IndexScan3", profile.SlowestOperator().Operator)
+
+	profile, err = (&QueryMetaData{}).ParseProfile()
+	suite.Require().Nil(err)
+	suite.Assert().Nil(profile)
+}
@@ -0,0 +1,132 @@
+package gocb
+
+import (
+	"sync"
+	"time"
+)
+
+// RecordedSpan is a snapshot of a span captured by a RecordingTracer.
+// UNCOMMITTED: This API may change in the future.
+type RecordedSpan struct {
+	Name      string
+	StartTime time.Time
+
+	// Duration is only set once the span has finished.
+	Duration time.Duration
+	Finished bool
+
+	Tags     map[string]interface{}
+	Children []*RecordedSpan
+}
+
+// RecordingTracer is a RequestTracer implementation which captures every span, along with its tags
+// and children, in memory.  It is intended to be used for testing and debugging, it should not be
+// used in production as recorded spans are only released by Reset.
+// UNCOMMITTED: This API may change in the future.
+type RecordingTracer struct {
+	lock  sync.Mutex
+	roots []*recordingSpan
+}
+
+// NewRecordingTracer returns a new RecordingTracer.
+// UNCOMMITTED: This API may change in the future.
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+// StartSpan belongs to the RequestTracer interface.  Spans whose parent was not created by this
+// tracer are recorded as root spans.
+func (t *RecordingTracer) StartSpan(operationName string, parentContext RequestSpanContext) RequestSpan {
+	span := &recordingSpan{
+		tracer:    t,
+		name:      operationName,
+		startTime: time.Now(),
+		tags:      make(map[string]interface{}),
+	}
+
+	t.lock.Lock()
+	if context, ok := parentContext.(*recordingSpanContext); ok && context.span.tracer == t {
+		context.span.children = append(context.span.children, span)
+	} else {
+		t.roots = append(t.roots, span)
+	}
+	t.lock.Unlock()
+
+	return span
+}
+
+// Spans returns a snapshot of the root spans recorded so far, in the order that they were started.
+func (t *RecordingTracer) Spans() []*RecordedSpan {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	spans := make([]*RecordedSpan, len(t.roots))
+	for i, span := range t.roots {
+		spans[i] = span.snapshot()
+	}
+
+	return spans
+}
+
+// Reset discards all of the spans recorded so far.
+func (t *RecordingTracer) Reset() {
+	t.lock.Lock()
+	t.roots = nil
+	t.lock.Unlock()
+}
+
+type recordingSpan struct {
+	tracer    *RecordingTracer
+	name      string
+	startTime time.Time
+	duration  time.Duration
+	finished  bool
+	tags      map[string]interface{}
+	children  []*recordingSpan
+}
+
+type recordingSpanContext struct {
+	span *recordingSpan
+}
+
+func (s *recordingSpan) Context() RequestSpanContext {
+	return &recordingSpanContext{s}
+}
+
+func (s *recordingSpan) SetTag(key string, value interface{}) RequestSpan {
+	s.tracer.lock.Lock()
+	s.tags[key] = value
+	s.tracer.lock.Unlock()
+
+	return s
+}
+
+func (s *recordingSpan) Finish() {
+	s.tracer.lock.Lock()
+	if !s.finished {
+		s.duration = time.Since(s.startTime)
+		s.finished = true
+	}
+	s.tracer.lock.Unlock()
+}
+
+// snapshot must be called with the tracer lock held.
+func (s *recordingSpan) snapshot() *RecordedSpan {
+	recorded := &RecordedSpan{
+		Name:      s.name,
+		StartTime: s.startTime,
+		Duration:  s.duration,
+		Finished:  s.finished,
+		Tags:      make(map[string]interface{}, len(s.tags)),
+	}
+
+	for key, value := range s.tags {
+		recorded.Tags[key] = value
+	}
+
+	for _, child := range s.children {
+		recorded.Children = append(recorded.Children, child.snapshot())
+	}
+
+	return recorded
+}
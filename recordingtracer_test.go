@@ -0,0 +1,106 @@
+package gocb
+
+import (
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestRecordingTracerKVParentSpan() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{Cas: 123}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	tracer := NewRecordingTracer()
+	col := suite.interceptedCollection(provider)
+	col.tracer = tracer
+
+	parent := tracer.StartSpan("app", nil)
+	_, err := col.Upsert("key", "value", &UpsertOptions{ParentSpan: parent.Context()})
+	suite.Require().Nil(err, err)
+	parent.Finish()
+
+	spans := tracer.Spans()
+	suite.Require().Len(spans, 1)
+	suite.Assert().Equal("app", spans[0].Name)
+	suite.Assert().True(spans[0].Finished)
+	suite.Require().Len(spans[0].Children, 1)
+
+	upsert := spans[0].Children[0]
+	suite.Assert().Equal("Upsert", upsert.Name)
+	suite.Assert().True(upsert.Finished)
+	suite.Assert().Equal("kv", upsert.Tags["couchbase.service"])
+	suite.Assert().Equal("_default", upsert.Tags["couchbase.collection"])
+	suite.Require().Len(upsert.Children, 1)
+	suite.Assert().Equal("encode", upsert.Children[0].Name)
+
+	tracer.Reset()
+	suite.Assert().Empty(tracer.Spans())
+}
+
+func (suite *UnitTestSuite) TestRecordingTracerQueryParentSpan() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, func(args mock.Arguments) {
+		opts := args.Get(0).(gocbcore.N1QLQueryOptions)
+		suite.Assert().IsType(&recordingSpanContext{}, opts.TraceContext)
+	})
+
+	tracer := NewRecordingTracer()
+	cluster.tracer = tracer
+
+	parent := tracer.StartSpan("app", nil)
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{
+		Adhoc:      true,
+		ParentSpan: parent.Context(),
+	})
+	suite.Require().Nil(err, err)
+	suite.assertQueryBeerResult(dataset, result)
+
+	spans := tracer.Spans()
+	suite.Require().Len(spans, 1)
+	suite.Assert().False(spans[0].Finished)
+	suite.Assert().Zero(spans[0].Duration)
+	suite.Require().Len(spans[0].Children, 1)
+
+	query := spans[0].Children[0]
+	suite.Assert().Equal("Query", query.Name)
+	suite.Assert().Equal("query", query.Tags["couchbase.service"])
+	suite.Require().Len(query.Children, 1)
+	suite.Assert().Equal("request_encoding", query.Children[0].Name)
+	suite.Assert().True(query.Children[0].Finished)
+}
+
+func (suite *UnitTestSuite) TestRecordingTracerForeignParent() {
+	tracer := NewRecordingTracer()
+	other := NewRecordingTracer()
+
+	foreign := other.StartSpan("foreign", nil)
+	span := tracer.StartSpan("op", foreign.Context())
+	time.Sleep(time.Millisecond)
+	span.Finish()
+
+	spans := tracer.Spans()
+	suite.Require().Len(spans, 1)
+	suite.Assert().Equal("op", spans[0].Name)
+	suite.Assert().True(spans[0].Duration > 0)
+	suite.Assert().Empty(other.Spans()[0].Children)
+}
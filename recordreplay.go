@@ -0,0 +1,746 @@
+package gocb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+	"github.com/pkg/errors"
+)
+
+// RecordReplayMode specifies whether service responses should be recorded to, or replayed from, disk.
+type RecordReplayMode uint
+
+const (
+	// RecordReplayModeDisabled indicates that responses are neither recorded nor replayed.
+	RecordReplayModeDisabled RecordReplayMode = iota
+
+	// RecordReplayModeRecord indicates that query, search, analytics and view responses received
+	// from the cluster should be written to disk.
+	RecordReplayModeRecord
+
+	// RecordReplayModeReplay indicates that query, search, analytics and view responses should be
+	// served from previously recorded files rather than from a cluster.  No network connections
+	// are made in this mode and all other services are unavailable.
+	RecordReplayModeReplay
+)
+
+// RecordReplayConfig specifies options for recording query, search, analytics and view
+// responses, or for replaying previously recorded responses.
+// UNCOMMITTED: This API may change in the future.
+type RecordReplayConfig struct {
+	// Mode specifies whether responses are recorded or replayed.  Defaults to RecordReplayModeDisabled.
+	Mode RecordReplayMode
+
+	// Directory is the directory that recordings are written to and read from.
+	Directory string
+}
+
+const (
+	recordedServiceQuery     = "query"
+	recordedServiceAnalytics = "analytics"
+	recordedServiceSearch    = "search"
+	recordedServiceView      = "view"
+)
+
+type jsonRecordedResponse struct {
+	Service      string             `json:"service"`
+	Request      json.RawMessage    `json:"request"`
+	Rows         []json.RawMessage  `json:"rows"`
+	MetaData     json.RawMessage    `json:"metadata,omitempty"`
+	Error        *jsonRecordedError `json:"error,omitempty"`
+	PreparedName string             `json:"prepared_name,omitempty"`
+}
+
+// jsonRecordedError is an error response, returned either when the request was dispatched or whilst
+// its rows were streamed.
+type jsonRecordedError struct {
+	Dispatch bool `json:"dispatch,omitempty"`
+
+	// Cause is the message of the innermost error, which is restored to the matching SDK error.
+	Cause string `json:"cause"`
+
+	// Type and Details hold the service error which wrapped the cause, if any.
+	Type    string          `json:"type,omitempty"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// recordedErrorCauses are the errors which can be restored when replaying an error response.
+var recordedErrorCauses = []error{
+	ErrServiceNotAvailable,
+	ErrInternalServerFailure,
+	ErrAuthenticationFailure,
+	ErrTemporaryFailure,
+	ErrParsingFailure,
+	ErrCasMismatch,
+	ErrFeatureNotAvailable,
+	ErrBucketNotFound,
+	ErrScopeNotFound,
+	ErrCollectionNotFound,
+	ErrIndexNotFound,
+	ErrIndexExists,
+	ErrPlanningFailure,
+	ErrIndexFailure,
+	ErrPreparedStatementFailure,
+	ErrCompilationFailure,
+	ErrJobQueueFull,
+	ErrDatasetNotFound,
+	ErrDataverseNotFound,
+	ErrDatasetExists,
+	ErrDataverseExists,
+	ErrLinkNotFound,
+	ErrViewNotFound,
+	ErrDesignDocumentNotFound,
+}
+
+// newRecordedError builds the recording of an error response, or returns nil if err was not returned by
+// the cluster, e.g. because the request timed out.
+func newRecordedError(err error, dispatch bool) *jsonRecordedError {
+	if errors.Is(err, ErrTimeout) || errors.Is(err, ErrRequestCanceled) {
+		return nil
+	}
+
+	cause := err
+	for unwrapped := errors.Unwrap(cause); unwrapped != nil; unwrapped = errors.Unwrap(cause) {
+		cause = unwrapped
+	}
+
+	recorded := &jsonRecordedError{
+		Dispatch: dispatch,
+		Cause:    cause.Error(),
+	}
+
+	// Retry reasons are not recorded as they cannot be decoded again.
+	var details interface{}
+	var queryErr *gocbcore.N1QLError
+	var analyticsErr *gocbcore.AnalyticsError
+	var searchErr *gocbcore.SearchError
+	var viewErr *gocbcore.ViewError
+	switch {
+	case errors.As(err, &queryErr):
+		coreErr := *queryErr
+		coreErr.RetryReasons = nil
+		recorded.Type, details = recordedServiceQuery, coreErr
+	case errors.As(err, &analyticsErr):
+		coreErr := *analyticsErr
+		coreErr.RetryReasons = nil
+		recorded.Type, details = recordedServiceAnalytics, coreErr
+	case errors.As(err, &searchErr):
+		coreErr := *searchErr
+		coreErr.RetryReasons = nil
+		recorded.Type, details = recordedServiceSearch, coreErr
+	case errors.As(err, &viewErr):
+		coreErr := *viewErr
+		coreErr.RetryReasons = nil
+		recorded.Type, details = recordedServiceView, coreErr
+	}
+
+	if details != nil {
+		detailBytes, err := json.Marshal(details)
+		if err != nil {
+			logFieldsf(LogSubsystemQuery, LogDebug, nil, "Failed to encode recorded %s error: %s", recorded.Type, err)
+			recorded.Type = ""
+		} else {
+			recorded.Details = detailBytes
+		}
+	}
+
+	return recorded
+}
+
+// restore rebuilds the error which was recorded.
+func (e *jsonRecordedError) restore() error {
+	cause := errors.New(e.Cause)
+	for _, knownErr := range recordedErrorCauses {
+		if knownErr.Error() == e.Cause {
+			cause = knownErr
+			break
+		}
+	}
+
+	var err error
+	switch e.Type {
+	case recordedServiceQuery:
+		coreErr := &gocbcore.N1QLError{}
+		err = json.Unmarshal(e.Details, coreErr)
+		coreErr.InnerError = cause
+		cause = coreErr
+	case recordedServiceAnalytics:
+		coreErr := &gocbcore.AnalyticsError{}
+		err = json.Unmarshal(e.Details, coreErr)
+		coreErr.InnerError = cause
+		cause = coreErr
+	case recordedServiceSearch:
+		coreErr := &gocbcore.SearchError{}
+		err = json.Unmarshal(e.Details, coreErr)
+		coreErr.InnerError = cause
+		cause = coreErr
+	case recordedServiceView:
+		coreErr := &gocbcore.ViewError{}
+		err = json.Unmarshal(e.Details, coreErr)
+		coreErr.InnerError = cause
+		cause = coreErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return cause
+}
+
+type jsonRecordedViewRequest struct {
+	DesignDocumentName string `json:"ddoc"`
+	ViewType           string `json:"view_type"`
+	ViewName           string `json:"view_name"`
+	Options            string `json:"options"`
+}
+
+type jsonRecordedSearchRequest struct {
+	IndexName string          `json:"index_name"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// recordingKey builds a stable identifier for a request.  Any client context id is removed
+// from the request as it is randomly generated when not supplied by the user.
+func recordingKey(service string, request []byte) (string, []byte, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(request, &fields); err != nil {
+		return "", nil, err
+	}
+
+	delete(fields, "client_context_id")
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash := sha256.Sum256(append([]byte(service+":"), normalized...))
+	return service + "-" + hex.EncodeToString(hash[:]), normalized, nil
+}
+
+func searchRecordingRequest(opts gocbcore.SearchQueryOptions) ([]byte, error) {
+	return json.Marshal(jsonRecordedSearchRequest{
+		IndexName: opts.IndexName,
+		Payload:   opts.Payload,
+	})
+}
+
+func viewRecordingRequest(opts gocbcore.ViewQueryOptions) ([]byte, error) {
+	return json.Marshal(jsonRecordedViewRequest{
+		DesignDocumentName: opts.DesignDocumentName,
+		ViewType:           opts.ViewType,
+		ViewName:           opts.ViewName,
+		Options:            opts.Options.Encode(),
+	})
+}
+
+func recordingPath(dir, key string) string {
+	return filepath.Join(dir, key+".json")
+}
+
+func writeRecording(dir, key string, recorded jsonRecordedResponse) {
+	if recorded.Rows == nil {
+		recorded.Rows = []json.RawMessage{}
+	}
+
+	recordedBytes, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to encode recorded %s response: %s", recorded.Service, err)
+		return
+	}
+
+	err = ioutil.WriteFile(recordingPath(dir, key), recordedBytes, 0644)
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to write recorded %s response: %s", recorded.Service, err)
+	}
+}
+
+// recordDispatchError records a request which failed before any results were returned.
+func recordDispatchError(dir, service string, request []byte, err error) {
+	recordedErr := newRecordedError(err, true)
+	if recordedErr == nil {
+		return
+	}
+
+	key, normalized, keyErr := recordingKey(service, request)
+	if keyErr != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record %s error: %s", service, keyErr)
+		return
+	}
+
+	writeRecording(dir, key, jsonRecordedResponse{
+		Service: service,
+		Request: normalized,
+		Error:   recordedErr,
+	})
+}
+
+type streamingRowReader interface {
+	NextRow() []byte
+	Err() error
+	MetaData() ([]byte, error)
+	Close() error
+}
+
+type recordingRowReader struct {
+	reader  streamingRowReader
+	dir     string
+	key     string
+	service string
+	request []byte
+
+	rows     []json.RawMessage
+	finished bool
+}
+
+func (r *recordingRowReader) NextRow() []byte {
+	rowBytes := r.reader.NextRow()
+	if rowBytes == nil {
+		if !r.finished {
+			r.finished = true
+			r.write()
+		}
+		return nil
+	}
+
+	rowCopy := make([]byte, len(rowBytes))
+	copy(rowCopy, rowBytes)
+	r.rows = append(r.rows, rowCopy)
+
+	return rowBytes
+}
+
+func (r *recordingRowReader) Err() error {
+	return r.reader.Err()
+}
+
+func (r *recordingRowReader) MetaData() ([]byte, error) {
+	return r.reader.MetaData()
+}
+
+func (r *recordingRowReader) Close() error {
+	return r.reader.Close()
+}
+
+func (r *recordingRowReader) write() {
+	recorded := jsonRecordedResponse{
+		Service: r.service,
+		Request: r.request,
+		Rows:    r.rows,
+	}
+
+	// The meta-data of a failed stream is recorded when available, as it holds the errors.
+	streamErr := r.reader.Err()
+	metaBytes, metaErr := r.reader.MetaData()
+	if streamErr == nil {
+		streamErr = metaErr
+	}
+	if metaErr == nil {
+		recorded.MetaData = metaBytes
+	}
+	if streamErr != nil {
+		recorded.Error = newRecordedError(streamErr, false)
+		if recorded.Error == nil {
+			logFieldsf(LogSubsystemQuery, LogDebug, nil,
+				"Not recording %s response due to stream error: %s", r.service, streamErr)
+			return
+		}
+	}
+
+	if preparedReader, ok := r.reader.(interface {
+		PreparedName() (string, error)
+	}); ok {
+		recorded.PreparedName, _ = preparedReader.PreparedName()
+	}
+
+	writeRecording(r.dir, r.key, recorded)
+}
+
+type recordingQueryRowReader struct {
+	*recordingRowReader
+	queryReader queryRowReader
+}
+
+func (r *recordingQueryRowReader) PreparedName() (string, error) {
+	return r.queryReader.PreparedName()
+}
+
+func newRecordingRowReader(reader streamingRowReader, dir, service string, request []byte) (*recordingRowReader, error) {
+	key, normalized, err := recordingKey(service, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingRowReader{
+		reader:  reader,
+		dir:     dir,
+		key:     key,
+		service: service,
+		request: normalized,
+	}, nil
+}
+
+type recordingQueryProvider struct {
+	provider queryProvider
+	dir      string
+}
+
+func (p *recordingQueryProvider) N1QLQuery(opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	reader, err := p.provider.N1QLQuery(opts)
+	if err != nil {
+		recordDispatchError(p.dir, recordedServiceQuery, opts.Payload, err)
+		return nil, err
+	}
+
+	return p.wrap(reader, opts.Payload), nil
+}
+
+func (p *recordingQueryProvider) PreparedN1QLQuery(opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	reader, err := p.provider.PreparedN1QLQuery(opts)
+	if err != nil {
+		recordDispatchError(p.dir, recordedServiceQuery, opts.Payload, err)
+		return nil, err
+	}
+
+	return p.wrap(reader, opts.Payload), nil
+}
+
+func (p *recordingQueryProvider) wrap(reader queryRowReader, payload []byte) queryRowReader {
+	recorder, err := newRecordingRowReader(reader, p.dir, recordedServiceQuery, payload)
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record query response: %s", err)
+		return reader
+	}
+
+	return &recordingQueryRowReader{
+		recordingRowReader: recorder,
+		queryReader:        reader,
+	}
+}
+
+type recordingAnalyticsProvider struct {
+	provider analyticsProvider
+	dir      string
+}
+
+func (p *recordingAnalyticsProvider) AnalyticsQuery(opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error) {
+	reader, err := p.provider.AnalyticsQuery(opts)
+	if err != nil {
+		recordDispatchError(p.dir, recordedServiceAnalytics, opts.Payload, err)
+		return nil, err
+	}
+
+	recorder, err := newRecordingRowReader(reader, p.dir, recordedServiceAnalytics, opts.Payload)
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record analytics response: %s", err)
+		return reader, nil
+	}
+
+	return recorder, nil
+}
+
+type recordingSearchProvider struct {
+	provider searchProvider
+	dir      string
+}
+
+func (p *recordingSearchProvider) SearchQuery(opts gocbcore.SearchQueryOptions) (searchRowReader, error) {
+	reader, err := p.provider.SearchQuery(opts)
+	request, recordErr := searchRecordingRequest(opts)
+	if err != nil {
+		if recordErr == nil {
+			recordDispatchError(p.dir, recordedServiceSearch, request, err)
+		}
+		return nil, err
+	}
+	if recordErr != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record search response: %s", recordErr)
+		return reader, nil
+	}
+
+	recorder, err := newRecordingRowReader(reader, p.dir, recordedServiceSearch, request)
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record search response: %s", err)
+		return reader, nil
+	}
+
+	return recorder, nil
+}
+
+type recordingViewProvider struct {
+	provider viewProvider
+	dir      string
+}
+
+func (p *recordingViewProvider) ViewQuery(opts gocbcore.ViewQueryOptions) (viewRowReader, error) {
+	reader, err := p.provider.ViewQuery(opts)
+	request, recordErr := viewRecordingRequest(opts)
+	if err != nil {
+		if recordErr == nil {
+			recordDispatchError(p.dir, recordedServiceView, request, err)
+		}
+		return nil, err
+	}
+	if recordErr != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record view response: %s", recordErr)
+		return reader, nil
+	}
+
+	recorder, err := newRecordingRowReader(reader, p.dir, recordedServiceView, request)
+	if err != nil {
+		logFieldsf(LogSubsystemQuery, LogWarn, nil, "Failed to record view response: %s", err)
+		return reader, nil
+	}
+
+	return recorder, nil
+}
+
+// recordingConnectionMgr wraps a connectionManager so that any query, analytics, search or view
+// responses which are fully read, or which fail, are also written to disk.
+type recordingConnectionMgr struct {
+	connectionManager
+	dir string
+}
+
+func newRecordingConnectionMgr(mgr connectionManager, dir string) (*recordingConnectionMgr, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingConnectionMgr{
+		connectionManager: mgr,
+		dir:               dir,
+	}, nil
+}
+
+func (c *recordingConnectionMgr) getViewProvider() (viewProvider, error) {
+	provider, err := c.connectionManager.getViewProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingViewProvider{provider: provider, dir: c.dir}, nil
+}
+
+func (c *recordingConnectionMgr) getQueryProvider() (queryProvider, error) {
+	provider, err := c.connectionManager.getQueryProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingQueryProvider{provider: provider, dir: c.dir}, nil
+}
+
+func (c *recordingConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
+	provider, err := c.connectionManager.getAnalyticsProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingAnalyticsProvider{provider: provider, dir: c.dir}, nil
+}
+
+func (c *recordingConnectionMgr) getSearchProvider() (searchProvider, error) {
+	provider, err := c.connectionManager.getSearchProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingSearchProvider{provider: provider, dir: c.dir}, nil
+}
+
+type replayRowReader struct {
+	rows         []json.RawMessage
+	metaData     []byte
+	err          error
+	preparedName string
+
+	idx int
+}
+
+func (r *replayRowReader) NextRow() []byte {
+	if r.idx >= len(r.rows) {
+		return nil
+	}
+
+	row := r.rows[r.idx]
+	r.idx++
+
+	return row
+}
+
+func (r *replayRowReader) Err() error {
+	if r.idx < len(r.rows) {
+		return nil
+	}
+
+	return r.err
+}
+
+func (r *replayRowReader) MetaData() ([]byte, error) {
+	if r.idx < len(r.rows) {
+		return nil, errors.New("the result must be fully read before fetching the meta-data")
+	}
+	if r.metaData == nil && r.err != nil {
+		return nil, r.err
+	}
+
+	return r.metaData, nil
+}
+
+func (r *replayRowReader) Close() error {
+	r.idx = len(r.rows)
+	return r.err
+}
+
+func (r *replayRowReader) PreparedName() (string, error) {
+	if r.preparedName == "" {
+		return "", errors.New("prepared name not found in metadata")
+	}
+
+	return r.preparedName, nil
+}
+
+type replayProvider struct {
+	dir string
+}
+
+func (p *replayProvider) load(service string, request []byte) (*replayRowReader, error) {
+	key, _, err := recordingKey(service, request)
+	if err != nil {
+		return nil, err
+	}
+
+	recordedBytes, err := ioutil.ReadFile(recordingPath(p.dir, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrRecordingNotFound
+		}
+		return nil, err
+	}
+
+	var recorded jsonRecordedResponse
+	err = json.Unmarshal(recordedBytes, &recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &replayRowReader{
+		rows:         recorded.Rows,
+		metaData:     recorded.MetaData,
+		preparedName: recorded.PreparedName,
+	}
+	if recorded.Error != nil {
+		reader.err = recorded.Error.restore()
+		if recorded.Error.Dispatch {
+			return nil, reader.err
+		}
+	}
+
+	return reader, nil
+}
+
+func (p *replayProvider) N1QLQuery(opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	return p.load(recordedServiceQuery, opts.Payload)
+}
+
+func (p *replayProvider) PreparedN1QLQuery(opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	return p.load(recordedServiceQuery, opts.Payload)
+}
+
+func (p *replayProvider) AnalyticsQuery(opts gocbcore.AnalyticsQueryOptions) (analyticsRowReader, error) {
+	return p.load(recordedServiceAnalytics, opts.Payload)
+}
+
+func (p *replayProvider) SearchQuery(opts gocbcore.SearchQueryOptions) (searchRowReader, error) {
+	request, err := searchRecordingRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.load(recordedServiceSearch, request)
+}
+
+func (p *replayProvider) ViewQuery(opts gocbcore.ViewQueryOptions) (viewRowReader, error) {
+	request, err := viewRecordingRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.load(recordedServiceView, request)
+}
+
+func (p *replayProvider) WaitUntilReady(deadline time.Time, opts gocbcore.WaitUntilReadyOptions) error {
+	return nil
+}
+
+// replayConnectionMgr is a connectionManager which serves query, analytics, search and view
+// responses from previously recorded files without connecting to a cluster.
+type replayConnectionMgr struct {
+	provider *replayProvider
+}
+
+func newReplayConnectionMgr(dir string) *replayConnectionMgr {
+	return &replayConnectionMgr{
+		provider: &replayProvider{dir: dir},
+	}
+}
+
+func (c *replayConnectionMgr) connect() error {
+	return nil
+}
+
+func (c *replayConnectionMgr) openBucket(bucketName string) error {
+	return nil
+}
+
+func (c *replayConnectionMgr) buildConfig(cluster *Cluster) error {
+	return nil
+}
+
+func (c *replayConnectionMgr) getKvProvider(bucketName string) (kvProvider, error) {
+	return nil, wrapError(ErrUnsupportedOperation, "key-value operations are not available in replay mode")
+}
+
+func (c *replayConnectionMgr) getViewProvider() (viewProvider, error) {
+	return c.provider, nil
+}
+
+func (c *replayConnectionMgr) getQueryProvider() (queryProvider, error) {
+	return c.provider, nil
+}
+
+func (c *replayConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
+	return c.provider, nil
+}
+
+func (c *replayConnectionMgr) getSearchProvider() (searchProvider, error) {
+	return c.provider, nil
+}
+
+func (c *replayConnectionMgr) getHTTPProvider() (httpProvider, error) {
+	return nil, wrapError(ErrUnsupportedOperation, "management operations are not available in replay mode")
+}
+
+func (c *replayConnectionMgr) getDiagnosticsProvider(bucketName string) (diagnosticsProvider, error) {
+	return nil, wrapError(ErrUnsupportedOperation, "diagnostics are not available in replay mode")
+}
+
+func (c *replayConnectionMgr) getWaitUntilReadyProvider(bucketName string) (waitUntilReadyProvider, error) {
+	return c.provider, nil
+}
+
+func (c *replayConnectionMgr) connection(bucketName string) (*gocbcore.Agent, error) {
+	return nil, wrapError(ErrUnsupportedOperation, "connections are not available in replay mode")
+}
+
+func (c *replayConnectionMgr) close() error {
+	return nil
+}
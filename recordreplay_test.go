@@ -0,0 +1,278 @@
+package gocb
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/couchbase/gocb/v2/search"
+	gocbcore "github.com/couchbase/gocbcore/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) recordReplayDir() string {
+	dir, err := ioutil.TempDir("", "gocb-recordings")
+	suite.Require().Nil(err, err)
+
+	return dir
+}
+
+func (suite *UnitTestSuite) TestRecordReplayQuery() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	dir := suite.recordReplayDir()
+	defer os.RemoveAll(dir)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+			PName: dataset.jsonQueryResponse.Prepared,
+		},
+	}
+
+	queryProvider, _ := suite.newMockQueryProvider(false, reader)
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	recordingMgr, err := newRecordingConnectionMgr(cli, dir)
+	suite.Require().Nil(err, err)
+
+	statement := "SELECT * FROM dataset WHERE service=?"
+	opts := &QueryOptions{
+		Adhoc:                true,
+		PositionalParameters: []interface{}{"query"},
+	}
+
+	cluster := suite.newCluster(recordingMgr)
+	result, err := cluster.Query(statement, opts)
+	suite.Require().Nil(err, err)
+	suite.assertQueryBeerResult(dataset, result)
+
+	files, err := ioutil.ReadDir(dir)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(files, 1)
+
+	// Client context ids are generated per request so must not affect which recording is replayed.
+	replayCluster := suite.newCluster(newReplayConnectionMgr(dir))
+	result, err = replayCluster.Query(statement, opts)
+	suite.Require().Nil(err, err)
+	suite.assertQueryBeerResult(dataset, result)
+
+	_, err = replayCluster.Query("SELECT 1=1", opts)
+	suite.Assert().True(errors.Is(err, ErrRecordingNotFound))
+}
+
+func (suite *UnitTestSuite) TestRecordReplayQueryPartialRead() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	dir := suite.recordReplayDir()
+	defer os.RemoveAll(dir)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	queryProvider, _ := suite.newMockQueryProvider(false, reader)
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	recordingMgr, err := newRecordingConnectionMgr(cli, dir)
+	suite.Require().Nil(err, err)
+
+	cluster := suite.newCluster(recordingMgr)
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+	suite.Require().True(result.Next())
+	suite.Require().Nil(result.Close())
+
+	files, err := ioutil.ReadDir(dir)
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(files)
+}
+
+func (suite *UnitTestSuite) TestRecordReplaySearchQuery() {
+	var dataset testSearchDataset
+	err := loadJSONTestDataset("beer_sample_search_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	dir := suite.recordReplayDir()
+	defer os.RemoveAll(dir)
+
+	reader := &mockSearchRowReader{
+		Dataset: dataset.Hits,
+		Meta:    suite.mustConvertToBytes(dataset.jsonSearchResponse),
+		Suite:   suite,
+	}
+
+	provider := new(mockSearchProvider)
+	provider.
+		On("SearchQuery", mock.AnythingOfType("gocbcore.SearchQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	cli := new(mockConnectionManager)
+	cli.On("getSearchProvider").Return(provider, nil)
+
+	recordingMgr, err := newRecordingConnectionMgr(cli, dir)
+	suite.Require().Nil(err, err)
+
+	query := search.NewTermQuery("term").Field("field")
+
+	cluster := suite.newCluster(recordingMgr)
+	result, err := cluster.SearchQuery("searchy", query, nil)
+	suite.Require().Nil(err, err)
+
+	var recordedIDs []string
+	for result.Next() {
+		recordedIDs = append(recordedIDs, result.Row().ID)
+	}
+	suite.Require().Nil(result.Err())
+	suite.Require().Len(recordedIDs, len(dataset.Hits))
+
+	replayCluster := suite.newCluster(newReplayConnectionMgr(dir))
+	result, err = replayCluster.SearchQuery("searchy", query, nil)
+	suite.Require().Nil(err, err)
+
+	var replayedIDs []string
+	for result.Next() {
+		replayedIDs = append(replayedIDs, result.Row().ID)
+	}
+	suite.Require().Nil(result.Err())
+	suite.Assert().Equal(recordedIDs, replayedIDs)
+
+	metadata, err := result.MetaData()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(dataset.TotalHits, metadata.Metrics.TotalRows)
+
+	_, err = replayCluster.SearchQuery("otherindex", query, nil)
+	suite.Assert().True(errors.Is(err, ErrRecordingNotFound))
+}
+
+func (suite *UnitTestSuite) TestRecordReplayReplayModeKvUnsupported() {
+	dir := suite.recordReplayDir()
+	defer os.RemoveAll(dir)
+
+	replayCluster := suite.newCluster(newReplayConnectionMgr(dir))
+	b := replayCluster.Bucket("default")
+
+	_, err := b.DefaultCollection().Get("key", nil)
+	suite.Assert().True(errors.Is(err, ErrUnsupportedOperation))
+}
+
+func (suite *UnitTestSuite) TestRecordReplayQueryDispatchError() {
+	dir := suite.recordReplayDir()
+	defer os.RemoveAll(dir)
+
+	queryProvider := new(mockQueryProvider)
+	queryProvider.
+		On("N1QLQuery", mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(nil, &gocbcore.N1QLError{
+			InnerError:    gocbcore.ErrParsingFailure,
+			Statement:     "SELEC 1",
+			Errors:        []gocbcore.N1QLErrorDesc{{Code: 3000, Message: "syntax error - at SELEC"}},
+			RetryAttempts: 1,
+		}).
+		Once()
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	recordingMgr, err := newRecordingConnectionMgr(cli, dir)
+	suite.Require().Nil(err, err)
+
+	_, err = suite.newCluster(recordingMgr).Query("SELEC 1", &QueryOptions{Adhoc: true})
+	suite.Require().True(errors.Is(err, ErrParsingFailure))
+
+	replayCluster := suite.newCluster(newReplayConnectionMgr(dir))
+	_, err = replayCluster.Query("SELEC 1", &QueryOptions{Adhoc: true})
+	suite.Require().True(errors.Is(err, ErrParsingFailure))
+
+	var queryErr *QueryError
+	suite.Require().True(errors.As(err, &queryErr))
+	suite.Assert().Equal([]QueryErrorDesc{{Code: 3000, Message: "syntax error - at SELEC"}}, queryErr.Errors)
+	suite.Assert().Equal("SELEC 1", queryErr.Statement)
+}
+
+func (suite *UnitTestSuite) TestRecordReplayQueryStreamError() {
+	dir := suite.recordReplayDir()
+	defer os.RemoveAll(dir)
+
+	meta := []byte(`{"errors":[{"code":5000,"msg":"internal error"}],"status":"errors"}`)
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`{"id":1}`)},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta: meta,
+			RowsErr: &gocbcore.N1QLError{
+				InnerError: gocbcore.ErrInternalServerFailure,
+				Errors:     []gocbcore.N1QLErrorDesc{{Code: 5000, Message: "internal error"}},
+			},
+		},
+	}
+
+	queryProvider, _ := suite.newMockQueryProvider(false, reader)
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+
+	recordingMgr, err := newRecordingConnectionMgr(cli, dir)
+	suite.Require().Nil(err, err)
+
+	result, err := suite.newCluster(recordingMgr).Query("SELECT 1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+	for result.Next() {
+	}
+
+	replayCluster := suite.newCluster(newReplayConnectionMgr(dir))
+	result, err = replayCluster.Query("SELECT 1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	var rows []map[string]int
+	for result.Next() {
+		var row map[string]int
+		suite.Require().Nil(result.Row(&row))
+		rows = append(rows, row)
+	}
+	suite.Assert().Equal([]map[string]int{{"id": 1}}, rows)
+	suite.Assert().True(errors.Is(result.Err(), ErrInternalServerFailure))
+
+	metaBytes, err := result.reader.MetaData()
+	suite.Require().Nil(err, err)
+	suite.Assert().JSONEq(string(meta), string(metaBytes))
+	suite.Assert().True(errors.Is(result.Close(), ErrInternalServerFailure))
+}
+
+type refCountingMeter struct {
+	refs int32
+}
+
+func (m *refCountingMeter) RecordOperation(metric *OperationMetric) {}
+
+func (m *refCountingMeter) AddRef() int32 {
+	m.refs++
+	return m.refs
+}
+
+func (m *refCountingMeter) DecRef() int32 {
+	m.refs--
+	return m.refs
+}
+
+func (suite *UnitTestSuite) TestRecordReplayConnectErrorReleasesMeter() {
+	meter := &refCountingMeter{}
+	_, err := Connect("couchbase://localhost", ClusterOptions{
+		Tracer:             &noopTracer{},
+		Meter:              meter,
+		RecordReplayConfig: RecordReplayConfig{Mode: RecordReplayModeReplay},
+	})
+	suite.Require().True(errors.Is(err, ErrInvalidArgument))
+	suite.Assert().Zero(meter.refs)
+}
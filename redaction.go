@@ -0,0 +1,67 @@
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// redactableError is implemented by errors which can produce a copy of themselves with user data,
+// metadata and system data wrapped in redaction tags.
+type redactableError interface {
+	redacted() interface{}
+}
+
+func redactUserData(v interface{}) string {
+	if globalLogRedactionLevel == RedactNone {
+		return fmt.Sprintf("%v", v)
+	}
+	return fmt.Sprintf("<ud>%v</ud>", v)
+}
+
+func redactMetaData(v interface{}) string {
+	if globalLogRedactionLevel != RedactFull {
+		return fmt.Sprintf("%v", v)
+	}
+	return fmt.Sprintf("<md>%v</md>", v)
+}
+
+func redactSystemData(v interface{}) string {
+	if globalLogRedactionLevel != RedactFull {
+		return fmt.Sprintf("%v", v)
+	}
+	return fmt.Sprintf("<sd>%v</sd>", v)
+}
+
+// redactOptional redacts v using redactFn unless it is empty, so that omitempty fields stay omitted.
+func redactOptional(v string, redactFn func(v interface{}) string) string {
+	if v == "" {
+		return v
+	}
+	return redactFn(v)
+}
+
+// redactUserDataJSON redacts a value which is serialized as JSON, such as a search query.
+func redactUserDataJSON(v interface{}) interface{} {
+	if v == nil || globalLogRedactionLevel == RedactNone {
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redactUserData(v)
+	}
+	return redactUserData(string(data))
+}
+
+// marshalRedactedJSON marshals v without escaping the angle brackets of redaction tags.
+func marshalRedactedJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,111 @@
+package gocb
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+)
+
+func (suite *UnitTestSuite) setTestRedactionLevel(level LogRedactLevel) func() {
+	oldLevel := globalLogRedactionLevel
+	SetLogRedactionLevel(level)
+	return func() {
+		SetLogRedactionLevel(oldLevel)
+	}
+}
+
+func (suite *UnitTestSuite) TestKeyValueErrorRedaction() {
+	coreErr := &gocbcore.KeyValueError{
+		InnerError:       gocbcore.ErrDocumentNotFound,
+		BucketName:       "travel",
+		ScopeName:        "inventory",
+		CollectionName:   "airline",
+		LastDispatchedTo: "10.112.191.102:11210",
+	}
+	err := maybeEnhanceKVErr(coreErr, "", "", "", "airline_10")
+
+	var kvErr *KeyValueError
+	suite.Require().True(errors.As(err, &kvErr))
+	suite.Assert().Equal("airline_10", kvErr.DocumentID)
+	suite.Assert().NotContains(err.Error(), "airline_10")
+
+	restore := suite.setTestRedactionLevel(RedactPartial)
+	msg := err.Error()
+	suite.Assert().Contains(msg, `"document_id":"<ud>airline_10</ud>"`)
+	suite.Assert().Contains(msg, `"bucket":"travel"`)
+	suite.Assert().Contains(msg, `"last_dispatched_to":"10.112.191.102:11210"`)
+	restore()
+
+	restore = suite.setTestRedactionLevel(RedactFull)
+	defer restore()
+	msg = err.Error()
+	suite.Assert().Contains(msg, `"bucket":"<md>travel</md>"`)
+	suite.Assert().Contains(msg, `"collection":"<md>airline</md>"`)
+	suite.Assert().Contains(msg, `"last_dispatched_to":"<sd>10.112.191.102:11210</sd>"`)
+
+	// The error itself must not be modified by redaction.
+	suite.Assert().Equal("airline_10", kvErr.DocumentID)
+}
+
+func (suite *UnitTestSuite) TestServiceErrorRedaction() {
+	defer suite.setTestRedactionLevel(RedactFull)()
+
+	queryErr := QueryError{
+		InnerError: ErrParsingFailure,
+		Statement:  "SELECT * FROM travel WHERE email = 'someone@example.com'",
+		Endpoint:   "10.112.191.101:8093",
+	}
+	msg := queryErr.Error()
+	suite.Assert().Contains(msg, `"statement":"<ud>SELECT`)
+	suite.Assert().Contains(msg, `"endpoint":"<sd>10.112.191.101:8093</sd>"`)
+
+	searchErr := SearchError{
+		InnerError: ErrIndexNotFound,
+		Query:      map[string]string{"match": "someone"},
+		IndexName:  "travel-index",
+	}
+	msg = searchErr.Error()
+	suite.Assert().Contains(msg, `"query":"<ud>{\"match\":\"someone\"}</ud>"`)
+	suite.Assert().Contains(msg, `"index_name":"<md>travel-index</md>"`)
+
+	timeoutErr := &TimeoutError{
+		InnerError:       ErrUnambiguousTimeout,
+		LastDispatchedTo: "10.112.191.102:11210",
+	}
+	suite.Assert().Contains(timeoutErr.Error(), "<sd>10.112.191.102:11210</sd>")
+}
+
+func (suite *UnitTestSuite) TestLogRedaction() {
+	defer suite.setTestRedactionLevel(RedactPartial)()
+
+	logger := &testStructuredLogger{}
+	defer suite.setTestStructuredLogger(logger)()
+
+	coll := newCollection(&Scope{bucket: &Bucket{bucketName: "travel"}, scopeName: "_default"}, "_default")
+	logFieldsf(LogSubsystemKeyValue, LogDebug, coll.logFields("Get", "airline_10"), "failed")
+
+	suite.Require().Len(logger.entries, 1)
+	for _, field := range logger.entries[0].Fields {
+		if field.Key == LogFieldDocumentID {
+			suite.Assert().Equal("<ud>airline_10</ud>", field.Value)
+		}
+		if field.Key == LogFieldBucket {
+			suite.Assert().Equal("travel", field.Value)
+		}
+	}
+
+	jsonBytes, err := thresholdLogReportJSON(ThresholdLogReport{
+		Service: "kv",
+		Top: []ThresholdLogItem{{
+			OperationName:     "Get",
+			TotalTime:         time.Second,
+			LastRemoteAddress: "10.112.191.102:11210",
+			DocumentKey:       "airline_10",
+		}},
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().True(strings.Contains(string(jsonBytes), `<ud>airline_10</ud>`), string(jsonBytes))
+	suite.Assert().Contains(string(jsonBytes), `"last_remote_address":"10.112.191.102:11210"`)
+}
@@ -0,0 +1,131 @@
+package gocb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// DefaultResultMaxRows is the maximum number of rows which All will decode when ResultAllOptions.MaxRows
+// is not set.
+const DefaultResultMaxRows = 10000
+
+// ResultAllOptions is the set of options available when decoding all of the rows of a result.
+// UNCOMMITTED: This API may change in the future.
+type ResultAllOptions struct {
+	// MaxRows is the maximum number of rows to decode, protecting against unexpectedly large results
+	// being held in memory.  If the result contains more rows then All fails with ErrTooManyRows.
+	// Defaults to DefaultResultMaxRows.
+	MaxRows uint32
+}
+
+// All decodes every remaining row of the results into the slice pointed to by slicePtr, appending one
+// element per row, and then closes the results.  The slice is left unchanged if any row fails.
+// UNCOMMITTED: This API may change in the future.
+func (r *QueryResult) All(slicePtr interface{}, opts *ResultAllOptions) error {
+	return decodeAllRows(r.reader, slicePtr, nil, opts)
+}
+
+// All decodes every remaining row of the results into the slice pointed to by slicePtr, appending one
+// element per row, and then closes the results.  The slice is left unchanged if any row fails.
+// UNCOMMITTED: This API may change in the future.
+func (r *AnalyticsResult) All(slicePtr interface{}, opts *ResultAllOptions) error {
+	return decodeAllRows(r.reader, slicePtr, nil, opts)
+}
+
+// All decodes every remaining row of the results into the slice pointed to by slicePtr, appending one
+// element per row, and then closes the results.  The slice is left unchanged if any row fails.  If the
+// elements of the slice are SearchRow then each row is appended as is, otherwise the stored fields of each
+// row are decoded into the element.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) All(slicePtr interface{}, opts *ResultAllOptions) error {
+	if _, ok := slicePtr.(*[]SearchRow); ok {
+		return decodeAllRows(r.reader, slicePtr, func(rowBytes []byte, valuePtr interface{}) error {
+			var rowData jsonSearchRow
+			if err := json.Unmarshal(rowBytes, &rowData); err != nil {
+				return err
+			}
+
+			*valuePtr.(*SearchRow) = newSearchRow(rowData)
+			return nil
+		}, opts)
+	}
+
+	return decodeAllRows(r.reader, slicePtr, func(rowBytes []byte, valuePtr interface{}) error {
+		var rowData jsonSearchRow
+		if err := json.Unmarshal(rowBytes, &rowData); err != nil {
+			return err
+		}
+		if len(rowData.Fields) == 0 || string(rowData.Fields) == "null" {
+			return wrapError(ErrNoResult, "search row "+rowData.ID+" has no stored fields")
+		}
+
+		return json.Unmarshal(rowData.Fields, valuePtr)
+	}, opts)
+}
+
+type allRowsReader interface {
+	NextRow() []byte
+	Err() error
+	Close() error
+}
+
+func decodeAllRows(reader allRowsReader, slicePtr interface{}, decode func([]byte, interface{}) error,
+	opts *ResultAllOptions) error {
+	if opts == nil {
+		opts = &ResultAllOptions{}
+	}
+	maxRows := int(opts.MaxRows)
+	if maxRows == 0 {
+		maxRows = DefaultResultMaxRows
+	}
+
+	sliceVal := reflect.ValueOf(slicePtr)
+	if sliceVal.Kind() != reflect.Ptr || sliceVal.IsNil() || sliceVal.Elem().Kind() != reflect.Slice {
+		return makeInvalidArgumentsError("slicePtr must be a non-nil pointer to a slice")
+	}
+	if decode == nil {
+		decode = json.Unmarshal
+	}
+
+	// Rows are decoded into a separate slice so that the caller never sees a partial result.
+	slice := sliceVal.Elem()
+	elemType := slice.Type().Elem()
+	rows := reflect.MakeSlice(slice.Type(), 0, 0)
+	decoded := 0
+	for rowBytes := reader.NextRow(); rowBytes != nil; rowBytes = reader.NextRow() {
+		if decoded == maxRows {
+			closeErr := reader.Close()
+			if closeErr != nil {
+				logFieldsf(LogSubsystemQuery, LogDebug, nil,
+					"Failed to close results after exceeding the row limit: %v", closeErr)
+			}
+			return wrapError(ErrTooManyRows, "result contains more than "+strconv.Itoa(maxRows)+" rows")
+		}
+
+		elem := reflect.New(elemType)
+		if err := decode(rowBytes, elem.Interface()); err != nil {
+			closeErr := reader.Close()
+			if closeErr != nil {
+				logFieldsf(LogSubsystemQuery, LogDebug, nil,
+					"Failed to close results after failing to decode a row: %v", closeErr)
+			}
+			return err
+		}
+
+		rows = reflect.Append(rows, elem.Elem())
+		decoded++
+	}
+
+	// A stream failure ends the rows early, so it must be reported ahead of any close error.
+	closeErr := reader.Close()
+	if err := reader.Err(); err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	slice.Set(reflect.AppendSlice(slice, rows))
+	return nil
+}
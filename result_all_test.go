@@ -0,0 +1,147 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+func (suite *UnitTestSuite) TestQueryResultAll() {
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`{"name":"a"}`), []byte(`{"name":"b"}`), []byte(`{"name":"c"}`)},
+	}
+	result := newQueryResult(reader)
+
+	type row struct {
+		Name string `json:"name"`
+	}
+	var rows []row
+	err := result.All(&rows, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]row{{Name: "a"}, {Name: "b"}, {Name: "c"}}, rows)
+
+	var raw []json.RawMessage
+	err = newQueryResult(&mockQueryRowReaderRows{Rows: reader.Rows}).All(&raw, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Len(raw, 3)
+
+	err = newQueryResult(&mockQueryRowReaderRows{Rows: reader.Rows}).All(rows, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestQueryResultAllTooManyRows() {
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`1`), []byte(`2`), []byte(`3`)},
+	}
+
+	rows := []int{0}
+	err := newQueryResult(reader).All(&rows, &ResultAllOptions{MaxRows: 2})
+	suite.Require().True(errors.Is(err, ErrTooManyRows), err)
+	suite.Assert().Equal([]int{0}, rows)
+
+	reader = &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`1`), []byte(`2`)},
+	}
+	rows = nil
+	err = newQueryResult(reader).All(&rows, &ResultAllOptions{MaxRows: 2})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]int{1, 2}, rows)
+}
+
+func (suite *UnitTestSuite) TestQueryResultAllCloseError() {
+	reader := &mockQueryRowReaderRows{
+		Rows:                   [][]byte{[]byte(`1`)},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{CloseErr: ErrTimeout},
+	}
+
+	var rows []int
+	err := newQueryResult(reader).All(&rows, nil)
+	suite.Assert().True(errors.Is(err, ErrTimeout))
+	suite.Assert().Nil(rows)
+}
+
+func (suite *UnitTestSuite) TestQueryResultAllStreamError() {
+	reader := &mockQueryRowReaderRows{
+		Rows:                   [][]byte{[]byte(`1`)},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{RowsErr: ErrRequestCanceled},
+	}
+
+	rows := []int{0}
+	err := newQueryResult(reader).All(&rows, nil)
+	suite.Assert().True(errors.Is(err, ErrRequestCanceled))
+	suite.Assert().Equal([]int{0}, rows)
+}
+
+func (suite *UnitTestSuite) TestQueryResultAllDecodeError() {
+	reader := &mockQueryRowReaderRows{
+		Rows: [][]byte{[]byte(`1`), []byte(`"two"`)},
+	}
+
+	rows := []int{0}
+	err := newQueryResult(reader).All(&rows, nil)
+	suite.Assert().NotNil(err)
+	suite.Assert().Equal([]int{0}, rows)
+}
+
+func (suite *UnitTestSuite) TestSearchResultAll() {
+	dataset := []jsonSearchRow{
+		{Index: "idx", ID: "a", Score: 1, Fields: json.RawMessage(`{"name":"a"}`)},
+		{Index: "idx", ID: "b", Score: 0.5, Fields: json.RawMessage(`{"name":"b"}`)},
+	}
+
+	var rows []SearchRow
+	err := newSearchResult(&mockSearchRowReader{Dataset: dataset, Suite: suite}).All(&rows, nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(rows, 2)
+	suite.Assert().Equal("b", rows[1].ID)
+
+	var fields []map[string]string
+	err = newSearchResult(&mockSearchRowReader{Dataset: dataset, Suite: suite}).All(&fields, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]map[string]string{{"name": "a"}, {"name": "b"}}, fields)
+
+	dataset[1].Fields = nil
+	fields = nil
+	err = newSearchResult(&mockSearchRowReader{Dataset: dataset, Suite: suite}).All(&fields, nil)
+	suite.Assert().True(errors.Is(err, ErrNoResult))
+}
+
+func (suite *UnitTestSuite) TestQueryOptionsNamedParametersStruct() {
+	type params struct {
+		Type    string `json:"type"`
+		Country string `json:"$country"`
+		Limit   uint64 `json:"limit,omitempty"`
+		Ignored string `json:"-"`
+	}
+
+	opts := &QueryOptions{
+		NamedParametersStruct: &params{Type: "airline", Country: "France", Limit: 1 << 60},
+		NamedParameters:       map[string]interface{}{"type": "hotel"},
+	}
+	execOpts, err := opts.toMap()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("hotel", execOpts["$type"])
+	suite.Assert().Equal(json.RawMessage(`"France"`), execOpts["$country"])
+	suite.Assert().Equal(json.RawMessage(`1152921504606846976`), execOpts["$limit"])
+	suite.Assert().NotContains(execOpts, "$Ignored")
+
+	opts = &QueryOptions{
+		NamedParametersStruct: params{},
+		PositionalParameters:  []interface{}{1},
+	}
+	_, err = opts.toMap()
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	analyticsOpts := &AnalyticsOptions{
+		NamedParametersStruct: map[string]interface{}{"type": "airline"},
+	}
+	_, err = analyticsOpts.toMap()
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	analyticsOpts = &AnalyticsOptions{
+		NamedParametersStruct: params{Type: "airline"},
+	}
+	execOpts, err = analyticsOpts.toMap()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(json.RawMessage(`"airline"`), execOpts["$type"])
+	suite.Assert().NotContains(execOpts, "$limit")
+}
@@ -184,7 +184,8 @@ func (d *GetResult) set(paths []subdocPath, content interface{}, value interface
 			} else if _, ok := content.([]interface{}); ok {
 				content = append(content.([]interface{}), arr)
 			} else {
-				logErrorf("Projections encountered a non-array or object content assigning an array")
+				logFieldsf(LogSubsystemKeyValue, LogError, nil,
+					"Projections encountered a non-array or object content assigning an array")
 			}
 		} else {
 			if _, ok := content.([]interface{}); ok {
@@ -209,7 +210,8 @@ func (d *GetResult) set(paths []subdocPath, content interface{}, value interface
 			return content
 
 		} else {
-			logErrorf("Projections encountered a non-array or object content assigning an array")
+			logFieldsf(LogSubsystemKeyValue, LogError, nil,
+				"Projections encountered a non-array or object content assigning an array")
 		}
 	} else {
 		if arr, ok := content.([]interface{}); ok {
@@ -222,7 +224,7 @@ func (d *GetResult) set(paths []subdocPath, content interface{}, value interface
 		cMap, ok := content.(map[string]interface{})
 		if !ok {
 			// this isn't possible but the linter won't play nice without it
-			logErrorf("Failed to assert projection content to a map")
+			logFieldsf(LogSubsystemKeyValue, LogError, nil, "Failed to assert projection content to a map")
 		}
 		cMap[path.path] = make(map[string]interface{})
 		return d.set(paths[1:], cMap[path.path], value)
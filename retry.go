@@ -12,7 +12,8 @@ func translateCoreRetryReasons(reasons []gocbcore.RetryReason) []RetryReason {
 	for _, retryReason := range reasons {
 		gocbReason, ok := retryReason.(RetryReason)
 		if !ok {
-			logErrorf("Failed to assert gocbcore retry reason to gocb retry reason: %v", retryReason)
+			logFieldsf(LogSubsystemSDK, LogError, nil,
+				"Failed to assert gocbcore retry reason to gocb retry reason: %v", retryReason)
 			continue
 		}
 		reasonsOut = append(reasonsOut, gocbReason)
@@ -153,7 +154,48 @@ func newRetryStrategyWrapper(strategy RetryStrategy) *retryStrategyWrapper {
 }
 
 type retryStrategyWrapper struct {
-	wrapped RetryStrategy
+	wrapped  RetryStrategy
+	budget   *RetryBudget
+	observer RetryObserver
+	history  *retryHistory
+}
+
+// forOperation returns a wrapper for a single operation which records the retry history of the
+// operation.  If strategy is not nil then it is used in place of the wrapped strategy.  Operations
+// dispatched using a wrapper returned by withHistory share its history.
+func (rs *retryStrategyWrapper) forOperation(strategy RetryStrategy) *retryStrategyWrapper {
+	wrapper := rs.withStrategy(strategy)
+	if wrapper.history == nil {
+		wrapper.history = &retryHistory{}
+	}
+	return wrapper
+}
+
+// withHistory returns a copy of the wrapper which records the retries of every operation that it
+// is used for into history.
+func (rs *retryStrategyWrapper) withHistory(history *retryHistory) *retryStrategyWrapper {
+	wrapper := rs.withStrategy(nil)
+	wrapper.history = history
+	return wrapper
+}
+
+// withStrategy returns a wrapper which uses strategy in place of the wrapped strategy, if it is not nil.
+func (rs *retryStrategyWrapper) withStrategy(strategy RetryStrategy) *retryStrategyWrapper {
+	if strategy == nil {
+		strategy = rs.wrapped
+	}
+
+	// The budget is applied by the wrapper, so a strategy wrapped with the same budget would pay twice.
+	if budgeted, ok := strategy.(*retryBudgetStrategy); ok && budgeted.budget == rs.budget {
+		strategy = budgeted.wrapped
+	}
+
+	return &retryStrategyWrapper{
+		wrapped:  strategy,
+		budget:   rs.budget,
+		observer: rs.observer,
+		history:  rs.history,
+	}
 }
 
 // RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
@@ -162,6 +204,29 @@ func (rs *retryStrategyWrapper) RetryAfter(req gocbcore.RetryRequest, reason goc
 		req: req,
 	}
 	wrappedAction := rs.wrapped.RetryAfter(wreq, RetryReason(reason))
+	if rs.budget != nil && wrappedAction != nil && wrappedAction.Duration() > 0 && !rs.budget.withdraw(wreq) {
+		wrappedAction = &NoRetryRetryAction{}
+	}
+
+	if rs.history != nil || rs.observer != nil {
+		decision := RetryDecision{
+			Identifier: req.Identifier(),
+			Reason:     RetryReason(reason),
+			Attempt:    req.RetryAttempts() + 1,
+			Time:       time.Now(),
+		}
+		if wrappedAction != nil {
+			decision.Delay = wrappedAction.Duration()
+		}
+
+		if rs.history != nil {
+			rs.history.record(decision)
+		}
+		if rs.observer != nil {
+			rs.observer(decision)
+		}
+	}
+
 	return gocbcore.RetryAction(wrappedAction)
 }
 
@@ -0,0 +1,181 @@
+package gocb
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// FullJitterBackoff returns a BackoffCalculator which picks a random duration between zero and a capped
+// exponential backoff, base * factor ^ attempts, limited to max.  If base or max are zero then 1ms and 500ms
+// are used and if factor is not greater than 1 then 2 is used.
+// UNCOMMITTED: This API may change in the future.
+func FullJitterBackoff(base, max time.Duration, factor float64) BackoffCalculator {
+	base, max = defaultBackoffBounds(base, max)
+	if factor <= 1 {
+		factor = 2
+	}
+
+	return func(retryAttempts uint32) time.Duration {
+		ceiling := cappedExponentialBackoff(base, max, factor, retryAttempts)
+
+		// A zero duration would indicate not to retry so the shortest backoff is 1ns.  The jitter does
+		// not need to be cryptographically secure.
+		return time.Duration(rand.Int63n(int64(ceiling))) + 1 // nolint: gosec
+	}
+}
+
+// DecorrelatedJitterBackoff returns a BackoffCalculator which picks a random duration between base and
+// three times the previous backoff, limited to max.  As a BackoffCalculator only sees the number of attempts
+// the previous backoff is taken as the upper bound of the previous attempt, base * 3 ^ attempts.  If base
+// or max are zero then 1ms and 500ms are used.
+// UNCOMMITTED: This API may change in the future.
+func DecorrelatedJitterBackoff(base, max time.Duration) BackoffCalculator {
+	base, max = defaultBackoffBounds(base, max)
+
+	return func(retryAttempts uint32) time.Duration {
+		ceiling := cappedExponentialBackoff(base, max, 3, retryAttempts+1)
+		if ceiling <= base {
+			return ceiling
+		}
+
+		// The jitter does not need to be cryptographically secure.
+		return base + time.Duration(rand.Int63n(int64(ceiling-base)+1)) // nolint: gosec
+	}
+}
+
+func cappedExponentialBackoff(base, max time.Duration, factor float64, retryAttempts uint32) time.Duration {
+	backoff := float64(base) * math.Pow(factor, float64(retryAttempts))
+	if backoff > float64(max) || math.IsInf(backoff, 1) {
+		return max
+	}
+
+	return time.Duration(backoff)
+}
+
+// NewFullJitterRetryStrategy returns a BestEffortRetryStrategy which backs off exponentially from base up to
+// max using full jitter.  If base or max are zero then 1ms and 500ms are used.
+// UNCOMMITTED: This API may change in the future.
+func NewFullJitterRetryStrategy(base, max time.Duration) *BestEffortRetryStrategy {
+	return NewBestEffortRetryStrategy(FullJitterBackoff(base, max, 2))
+}
+
+// NewDecorrelatedJitterRetryStrategy returns a BestEffortRetryStrategy which backs off from base up to max
+// using decorrelated jitter.  If base or max are zero then 1ms and 500ms are used.
+// UNCOMMITTED: This API may change in the future.
+func NewDecorrelatedJitterRetryStrategy(base, max time.Duration) *BestEffortRetryStrategy {
+	return NewBestEffortRetryStrategy(DecorrelatedJitterBackoff(base, max))
+}
+
+func defaultBackoffBounds(base, max time.Duration) (time.Duration, time.Duration) {
+	if base <= 0 {
+		base = 1 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 500 * time.Millisecond
+	}
+	if max < base {
+		max = base
+	}
+
+	return base, max
+}
+
+// FailFastRetryStrategy represents a strategy that never retries an operation, failing it with the
+// error which caused the retry instead.  Reasons which report AlwaysRetry, such as
+// KVNotMyVBucketRetryReason, are retried by the SDK without consulting the strategy.
+// UNCOMMITTED: This API may change in the future.
+type FailFastRetryStrategy struct {
+}
+
+// NewFailFastRetryStrategy returns a new FailFastRetryStrategy.
+// UNCOMMITTED: This API may change in the future.
+func NewFailFastRetryStrategy() *FailFastRetryStrategy {
+	return &FailFastRetryStrategy{}
+}
+
+// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
+func (rs *FailFastRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	return &NoRetryRetryAction{}
+}
+
+// RetryPolicy describes how a PolicyRetryStrategy handles a single RetryReason.
+// UNCOMMITTED: This API may change in the future.
+type RetryPolicy struct {
+	// FailFast indicates that operations should not be retried for this reason.
+	FailFast bool
+
+	// MaxAttempts limits the number of times that an operation can have been retried, for any reason,
+	// before it is no longer retried for this reason.  A value of 0 indicates no limit.
+	MaxAttempts uint32
+
+	// BackoffCalculator calculates the duration to wait before retrying, if nil then the backoff
+	// calculator of the strategy is used.
+	BackoffCalculator BackoffCalculator
+
+	// RetryNonIdempotent allows non-idempotent operations to be retried for this reason, even if
+	// the reason would not normally allow it.
+	RetryNonIdempotent bool
+}
+
+// PolicyRetryStrategy represents a strategy which applies a distinct RetryPolicy to each RetryReason.
+// UNCOMMITTED: This API may change in the future.
+type PolicyRetryStrategy struct {
+	// Policies are the policies for each reason.  Reasons which report AlwaysRetry, such as
+	// KVNotMyVBucketRetryReason and KVCollectionOutdatedRetryReason, are not configurable: the SDK
+	// always retries them with controlled backoff without consulting the retry strategy.
+	Policies map[RetryReason]RetryPolicy
+
+	// DefaultPolicy is applied to any reason which does not have a policy.
+	DefaultPolicy RetryPolicy
+
+	// BackoffCalculator is used by policies which do not specify a BackoffCalculator, if nil then the
+	// same controlled backoff as used by BestEffortRetryStrategy is used.
+	BackoffCalculator BackoffCalculator
+}
+
+// NewPolicyRetryStrategy returns a new PolicyRetryStrategy which will apply the supplied policies, reasons
+// without a policy are retried until the operation times out.  An ErrInvalidArgument error is returned
+// if a policy is supplied for a reason which reports AlwaysRetry, as such reasons are not configurable.
+// UNCOMMITTED: This API may change in the future.
+func NewPolicyRetryStrategy(policies map[RetryReason]RetryPolicy) (*PolicyRetryStrategy, error) {
+	for reason := range policies {
+		if reason.AlwaysRetry() {
+			return nil, makeInvalidArgumentsError("reason " + reason.Description() + " is always retried and cannot have a policy")
+		}
+	}
+
+	return &PolicyRetryStrategy{
+		Policies: policies,
+	}, nil
+}
+
+// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
+func (rs *PolicyRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	policy, ok := rs.Policies[reason]
+	if !ok {
+		policy = rs.DefaultPolicy
+	}
+
+	if policy.FailFast {
+		return &NoRetryRetryAction{}
+	}
+
+	if policy.MaxAttempts > 0 && req.RetryAttempts() >= policy.MaxAttempts {
+		return &NoRetryRetryAction{}
+	}
+
+	if !req.Idempotent() && !reason.AllowsNonIdempotentRetry() && !policy.RetryNonIdempotent {
+		return &NoRetryRetryAction{}
+	}
+
+	calculator := policy.BackoffCalculator
+	if calculator == nil {
+		calculator = rs.BackoffCalculator
+	}
+	if calculator == nil {
+		calculator = NewBestEffortRetryStrategy(nil).BackoffCalculator
+	}
+
+	return &WithDurationRetryAction{WithDuration: calculator(req.RetryAttempts())}
+}
@@ -1,25 +1,13 @@
 package gocb
 
 import (
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/couchbase/gocbcore/v9"
 )
 
-// failFastRetryStrategy represents a strategy that will never retry.
-type failFastRetryStrategy struct {
-}
-
-// newFailFastRetryStrategy returns a new FailFastRetryStrategy.
-func newFailFastRetryStrategy() *failFastRetryStrategy {
-	return &failFastRetryStrategy{}
-}
-
-// RetryAfter calculates and returns a RetryAction describing how long to wait before retrying an operation.
-func (rs *failFastRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
-	return &NoRetryRetryAction{}
-}
-
 type mockGocbcoreRequest struct {
 	attempts   uint32
 	identifier string
@@ -147,7 +135,7 @@ func (suite *UnitTestSuite) TestBestEffortRetryStrategy_RetryAfterDefaultCalcula
 }
 
 func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterNoRetry() {
-	strategy := newFailFastRetryStrategy()
+	strategy := NewFailFastRetryStrategy()
 	action := strategy.RetryAfter(&mockRetryRequest{}, RetryReason(gocbcore.UnknownRetryReason))
 	if action.Duration() != 0 {
 		suite.T().Fatalf("Expected duration to be %d but was %d", 0, action.Duration())
@@ -155,7 +143,7 @@ func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterNoRetry() {
 }
 
 func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterAlwaysRetry() {
-	strategy := newFailFastRetryStrategy()
+	strategy := NewFailFastRetryStrategy()
 	action := strategy.RetryAfter(&mockRetryRequest{}, RetryReason(gocbcore.KVCollectionOutdatedRetryReason))
 	if action.Duration() != 0 {
 		suite.T().Fatalf("Expected duration to be %d but was %d", 0, action.Duration())
@@ -163,9 +151,118 @@ func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterAlwaysRetry() {
 }
 
 func (suite *UnitTestSuite) TestFailFastRetryStrategy_RetryAfterAllowsNonIdempotent() {
-	strategy := newFailFastRetryStrategy()
+	strategy := NewFailFastRetryStrategy()
 	action := strategy.RetryAfter(&mockRetryRequest{}, RetryReason(gocbcore.KVLockedRetryReason))
 	if action.Duration() != 0 {
 		suite.T().Fatalf("Expected duration to be %d but was %d", 0, action.Duration())
 	}
 }
+
+func (suite *UnitTestSuite) TestFullJitterBackoff() {
+	calculator := FullJitterBackoff(10*time.Millisecond, 100*time.Millisecond, 2)
+	for attempts := uint32(0); attempts < 10; attempts++ {
+		ceiling := 10 * time.Millisecond << attempts
+		if ceiling > 100*time.Millisecond {
+			ceiling = 100 * time.Millisecond
+		}
+
+		for i := 0; i < 50; i++ {
+			backoff := calculator(attempts)
+			suite.Assert().True(backoff > 0 && backoff <= ceiling, "backoff %s outside of (0, %s]", backoff, ceiling)
+		}
+	}
+}
+
+func (suite *UnitTestSuite) TestDecorrelatedJitterBackoff() {
+	calculator := DecorrelatedJitterBackoff(10*time.Millisecond, 100*time.Millisecond)
+	for attempts := uint32(0); attempts < 10; attempts++ {
+		for i := 0; i < 50; i++ {
+			backoff := calculator(attempts)
+			suite.Assert().True(backoff >= 10*time.Millisecond && backoff <= 100*time.Millisecond,
+				"backoff %s outside of [10ms, 100ms]", backoff)
+			if attempts == 0 {
+				suite.Assert().True(backoff <= 30*time.Millisecond, "backoff %s above 30ms", backoff)
+			}
+		}
+	}
+}
+
+func (suite *UnitTestSuite) TestPolicyRetryStrategy() {
+	strategy, err := NewPolicyRetryStrategy(map[RetryReason]RetryPolicy{
+		KVLockedRetryReason: {
+			MaxAttempts:       3,
+			BackoffCalculator: mockBackoffCalculator,
+		},
+		ServiceNotAvailableRetryReason: {
+			FailFast: true,
+		},
+		SocketCloseInFlightRetryReason: {
+			RetryNonIdempotent: true,
+			BackoffCalculator:  mockBackoffCalculator,
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	action := strategy.RetryAfter(&mockRetryRequest{attempts: 2}, KVLockedRetryReason)
+	suite.Assert().Equal(2*time.Millisecond, action.Duration())
+
+	action = strategy.RetryAfter(&mockRetryRequest{attempts: 3}, KVLockedRetryReason)
+	suite.Assert().Equal(time.Duration(0), action.Duration())
+
+	action = strategy.RetryAfter(&mockRetryRequest{idempotent: true}, ServiceNotAvailableRetryReason)
+	suite.Assert().Equal(time.Duration(0), action.Duration())
+
+	action = strategy.RetryAfter(&mockRetryRequest{attempts: 4}, SocketCloseInFlightRetryReason)
+	suite.Assert().Equal(4*time.Millisecond, action.Duration())
+
+	// Reasons without a policy use the default policy and controlled backoff.
+	action = strategy.RetryAfter(&mockRetryRequest{attempts: 5}, KVTemporaryFailureRetryReason)
+	suite.Assert().Equal(32*time.Millisecond, action.Duration())
+
+	action = strategy.RetryAfter(&mockRetryRequest{}, UnknownRetryReason)
+	suite.Assert().Equal(time.Duration(0), action.Duration())
+
+	strategy.DefaultPolicy = RetryPolicy{FailFast: true}
+	action = strategy.RetryAfter(&mockRetryRequest{attempts: 5}, KVTemporaryFailureRetryReason)
+	suite.Assert().Equal(time.Duration(0), action.Duration())
+
+	_, err = NewPolicyRetryStrategy(map[RetryReason]RetryPolicy{
+		KVNotMyVBucketRetryReason: {FailFast: true},
+	})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestRetryWrapper_ObserverAndHistory() {
+	var observed []RetryDecision
+	wrapper := newRetryStrategyWrapper(NewBestEffortRetryStrategy(mockBackoffCalculator))
+	wrapper.observer = func(decision RetryDecision) {
+		observed = append(observed, decision)
+	}
+
+	opWrapper := wrapper.forOperation(nil)
+	opWrapper.RetryAfter(&mockGocbcoreRequest{attempts: 2, identifier: "0x1a", idempotent: true}, gocbcore.KVLockedRetryReason)
+	opWrapper.RetryAfter(&mockGocbcoreRequest{attempts: 3, identifier: "0x1b"}, gocbcore.UnknownRetryReason)
+
+	suite.Require().Len(observed, 2)
+	suite.Assert().Equal("0x1a", observed[0].Identifier)
+	suite.Assert().Equal(KVLockedRetryReason, observed[0].Reason)
+	suite.Assert().Equal(uint32(3), observed[0].Attempt)
+	suite.Assert().Equal(2*time.Millisecond, observed[0].Delay)
+	suite.Assert().Equal(time.Duration(0), observed[1].Delay)
+
+	kvErr := opWrapper.attachHistory(&KeyValueError{InnerError: ErrDocumentLocked})
+	suite.Assert().Equal(observed, kvErr.(*KeyValueError).RetryHistory)
+
+	timeoutErr := opWrapper.attachHistory(&TimeoutError{InnerError: ErrUnambiguousTimeout})
+	suite.Assert().Equal(observed, timeoutErr.(*TimeoutError).RetryHistory)
+
+	b, err := json.Marshal(timeoutErr)
+	suite.Require().Nil(err, err)
+	suite.Assert().Contains(string(b), `"rh":[{"identifier":"0x1a","reason":"KV_LOCKED","attempt":3,"delay":2000000`)
+
+	// The shared wrapper and operations without retries do not record history.
+	wrapper.RetryAfter(&mockGocbcoreRequest{identifier: "0x1c"}, gocbcore.UnknownRetryReason)
+	suite.Assert().Len(observed, 3)
+	suite.Assert().Nil(wrapper.attachHistory(&QueryError{InnerError: ErrParsingFailure}).(*QueryError).RetryHistory)
+	suite.Assert().Nil(wrapper.forOperation(nil).attachHistory(&QueryError{InnerError: ErrParsingFailure}).(*QueryError).RetryHistory)
+}
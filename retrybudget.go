@@ -0,0 +1,175 @@
+package gocb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryBudgetOptions is the set of options available when creating a RetryBudget.
+type RetryBudgetOptions struct {
+	// Ratio is the number of retries which each successful operation earns, the default is 0.1
+	// allowing one retry for every ten successful operations.
+	Ratio float64
+
+	// MaxTokens is the maximum number of retries which can be saved up, and the number of retries
+	// initially available.  The default is 100.
+	MaxTokens float64
+}
+
+// RetryBudget is a token bucket which limits retries to a fraction of recent successful operations,
+// preventing many clients from amplifying the load on a cluster during an incident.  Every retry costs
+// a token and a retry which cannot be afforded is not made, failing the operation with an error which
+// satisfies errors.Is(err, ErrRetryBudgetExhausted).  The error keeps its usual type, e.g. *KeyValueError,
+// with RetryBudgetExhausted set.
+// UNCOMMITTED: This API may change in the future.
+type RetryBudget struct {
+	lock      sync.Mutex
+	tokens    float64
+	ratio     float64
+	maxTokens float64
+
+	exhausted uint64
+	denied    *recentKeys
+}
+
+// NewRetryBudget creates a new RetryBudget.  Set it as ClusterOptions.RetryBudget to limit the retries
+// made by a cluster.
+// UNCOMMITTED: This API may change in the future.
+func NewRetryBudget(opts *RetryBudgetOptions) *RetryBudget {
+	if opts == nil {
+		opts = &RetryBudgetOptions{}
+	}
+
+	ratio := opts.Ratio
+	if ratio <= 0 {
+		ratio = 0.1
+	}
+
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 100
+	}
+
+	return &RetryBudget{
+		tokens:    maxTokens,
+		ratio:     ratio,
+		maxTokens: maxTokens,
+		denied:    newRecentKeys(1024),
+	}
+}
+
+// Wrap returns a RetryStrategy which only retries when both strategy and the budget allow it.  The
+// cluster applies its budget to every operation, including those with their own RetryStrategy, so
+// strategies only need to be wrapped to share the budget with requests made outside of the cluster.
+func (b *RetryBudget) Wrap(strategy RetryStrategy) RetryStrategy {
+	if budgeted, ok := strategy.(*retryBudgetStrategy); ok && budgeted.budget == b {
+		return strategy
+	}
+
+	return &retryBudgetStrategy{
+		wrapped: strategy,
+		budget:  b,
+	}
+}
+
+// Tokens returns the number of retries currently available.
+func (b *RetryBudget) Tokens() float64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.tokens
+}
+
+// Exhausted returns the number of retries which were not made because the budget was exhausted.
+func (b *RetryBudget) Exhausted() uint64 {
+	return atomic.LoadUint64(&b.exhausted)
+}
+
+func (b *RetryBudget) withdraw(req RetryRequest) bool {
+	b.lock.Lock()
+	if b.tokens >= 1 {
+		b.tokens--
+		b.lock.Unlock()
+		return true
+	}
+	b.lock.Unlock()
+
+	atomic.AddUint64(&b.exhausted, 1)
+	b.denied.record(req.Identifier(), time.Now())
+	return false
+}
+
+// recordOutcome deposits tokens for successful operations.
+func (b *RetryBudget) recordOutcome(err error) {
+	if b == nil || err != nil {
+		return
+	}
+
+	b.lock.Lock()
+	b.tokens += b.ratio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+	b.lock.Unlock()
+}
+
+// maybeMarkExhausted marks err as having failed because the budget was exhausted, if a retry for the
+// request which produced it was denied.  The type of err is left unchanged.
+func (b *RetryBudget) maybeMarkExhausted(err error) error {
+	if b == nil || err == nil || atomic.LoadUint64(&b.exhausted) == 0 || errors.Is(err, ErrRetryBudgetExhausted) {
+		return err
+	}
+
+	denied := func(identifier string) bool {
+		if identifier == "" {
+			return false
+		}
+
+		_, ok := b.denied.lookup(identifier)
+		return ok
+	}
+
+	// Management requests fail with an HTTPError value rather than a pointer, so it is replaced.
+	if httpErr, ok := err.(HTTPError); ok {
+		httpErr.RetryBudgetExhausted = denied(httpErr.UniqueID)
+		return httpErr
+	}
+
+	var kvErr *KeyValueError
+	var queryErr *QueryError
+	var analyticsErr *AnalyticsError
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &kvErr):
+		kvErr.RetryBudgetExhausted = denied(fmt.Sprintf("0x%x", kvErr.Opaque))
+	case errors.As(err, &queryErr):
+		queryErr.RetryBudgetExhausted = denied(queryErr.ClientContextID)
+	case errors.As(err, &analyticsErr):
+		analyticsErr.RetryBudgetExhausted = denied(analyticsErr.ClientContextID)
+	case errors.As(err, &httpErr):
+		httpErr.RetryBudgetExhausted = denied(httpErr.UniqueID)
+	}
+
+	return err
+}
+
+type retryBudgetStrategy struct {
+	wrapped RetryStrategy
+	budget  *RetryBudget
+}
+
+func (rs *retryBudgetStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	action := rs.wrapped.RetryAfter(req, reason)
+	if action == nil || action.Duration() == 0 {
+		return action
+	}
+
+	if !rs.budget.withdraw(req) {
+		return &NoRetryRetryAction{}
+	}
+
+	return action
+}
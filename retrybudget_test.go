@@ -0,0 +1,91 @@
+package gocb
+
+import (
+	"errors"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+)
+
+func (suite *UnitTestSuite) TestRetryBudgetLimitsRetries() {
+	budget := NewRetryBudget(&RetryBudgetOptions{
+		Ratio:     0.5,
+		MaxTokens: 2,
+	})
+	strategy := budget.Wrap(NewBestEffortRetryStrategy(mockBackoffCalculator))
+	suite.Assert().Equal(strategy, budget.Wrap(strategy))
+
+	req := &mockRetryRequest{attempts: 1, identifier: "0x1a", idempotent: true}
+	suite.Assert().Equal(time.Millisecond, strategy.RetryAfter(req, KVLockedRetryReason).Duration())
+	suite.Assert().Equal(time.Millisecond, strategy.RetryAfter(req, KVLockedRetryReason).Duration())
+	suite.Assert().Equal(time.Duration(0), strategy.RetryAfter(req, KVLockedRetryReason).Duration())
+	suite.Assert().Equal(uint64(1), budget.Exhausted())
+
+	// Operations which the wrapped strategy does not retry do not cost tokens.
+	suite.Assert().Equal(time.Duration(0), strategy.RetryAfter(&mockRetryRequest{}, UnknownRetryReason).Duration())
+	suite.Assert().Equal(uint64(1), budget.Exhausted())
+
+	budget.recordOutcome(errors.New("failed"))
+	suite.Assert().Equal(float64(0), budget.Tokens())
+
+	budget.recordOutcome(nil)
+	budget.recordOutcome(nil)
+	suite.Assert().Equal(float64(1), budget.Tokens())
+	suite.Assert().Equal(time.Millisecond, strategy.RetryAfter(req, KVLockedRetryReason).Duration())
+
+	for i := 0; i < 10; i++ {
+		budget.recordOutcome(nil)
+	}
+	suite.Assert().Equal(float64(2), budget.Tokens())
+}
+
+func (suite *UnitTestSuite) TestRetryBudgetExhaustedError() {
+	budget := NewRetryBudget(&RetryBudgetOptions{MaxTokens: 1})
+	strategy := budget.Wrap(NewBestEffortRetryStrategy(mockBackoffCalculator))
+
+	strategy.RetryAfter(&mockRetryRequest{attempts: 1, identifier: "0x1a", idempotent: true}, KVLockedRetryReason)
+	strategy.RetryAfter(&mockRetryRequest{attempts: 1, identifier: "0x1b", idempotent: true}, KVLockedRetryReason)
+
+	deniedErr := budget.maybeMarkExhausted(&KeyValueError{InnerError: ErrDocumentLocked, Opaque: 0x1b})
+	suite.Assert().True(errors.Is(deniedErr, ErrRetryBudgetExhausted))
+	suite.Assert().True(errors.Is(deniedErr, ErrDocumentLocked))
+	suite.Require().IsType(&KeyValueError{}, deniedErr)
+	suite.Assert().True(deniedErr.(*KeyValueError).RetryBudgetExhausted)
+
+	httpErr := budget.maybeMarkExhausted(HTTPError{InnerError: ErrServiceNotAvailable, UniqueID: "0x1b"})
+	suite.Require().IsType(HTTPError{}, httpErr)
+	suite.Assert().True(errors.Is(httpErr, ErrRetryBudgetExhausted))
+
+	otherErr := budget.maybeMarkExhausted(&KeyValueError{InnerError: ErrDocumentLocked, Opaque: 0x1a})
+	suite.Assert().False(errors.Is(otherErr, ErrRetryBudgetExhausted))
+
+	meter := NewAggregatingMeter(nil)
+	recordOperationMetric(meter, ServiceTypeKeyValue, "Get", time.Now(), deniedErr, nil)
+	recordOperationMetric(meter, ServiceTypeKeyValue, "Get", time.Now(), otherErr, nil)
+	snapshot := meter.Snapshot()
+	suite.Require().Len(snapshot, 1)
+	suite.Assert().Equal(uint64(1), snapshot[0].RetryBudgetExhausted)
+	suite.Assert().Equal(uint64(2), snapshot[0].Errors["document_locked"])
+
+	var nilBudget *RetryBudget
+	suite.Assert().Equal(otherErr, nilBudget.maybeMarkExhausted(otherErr))
+	nilBudget.recordOutcome(nil)
+}
+
+func (suite *UnitTestSuite) TestRetryBudgetLimitsOperationStrategies() {
+	budget := NewRetryBudget(&RetryBudgetOptions{MaxTokens: 1})
+	wrapper := newRetryStrategyWrapper(NewBestEffortRetryStrategy(mockBackoffCalculator))
+	wrapper.budget = budget
+
+	// Strategies passed to individual operations are limited by the budget, and only pay once if they
+	// have already been wrapped with it.
+	opWrapper := wrapper.forOperation(budget.Wrap(NewBestEffortRetryStrategy(mockBackoffCalculator)))
+	req := &mockGocbcoreRequest{attempts: 1, identifier: "0x1a", idempotent: true}
+	suite.Assert().Equal(time.Millisecond, opWrapper.RetryAfter(req, gocbcore.KVLockedRetryReason).Duration())
+	suite.Assert().Equal(time.Duration(0), opWrapper.RetryAfter(req, gocbcore.KVLockedRetryReason).Duration())
+	suite.Assert().Equal(uint64(1), budget.Exhausted())
+
+	opWrapper = wrapper.forOperation(&mockRetryStrategy{action: &WithDurationRetryAction{WithDuration: time.Second}})
+	suite.Assert().Equal(time.Duration(0), opWrapper.RetryAfter(req, gocbcore.KVLockedRetryReason).Duration())
+	suite.Assert().Equal(uint64(2), budget.Exhausted())
+}
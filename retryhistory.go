@@ -0,0 +1,116 @@
+package gocb
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// RetryDecision describes a single decision made by the RetryStrategy of an operation.  Reasons which
+// report AlwaysRetry, such as KVNotMyVBucketRetryReason, are retried by the SDK without consulting the
+// RetryStrategy and so do not produce decisions.
+// UNCOMMITTED: This API may change in the future.
+type RetryDecision struct {
+	// Identifier is the identifier of the request at the time of the decision.  For key-value
+	// operations this is the opaque of the dispatched request, which changes with every dispatch.
+	Identifier string      `json:"identifier,omitempty"`
+	Reason     RetryReason `json:"reason,omitempty"`
+
+	// Attempt is the retry attempt which was being decided, starting at 1.
+	Attempt uint32 `json:"attempt"`
+
+	// Delay is the time which the operation waited before being retried, or 0 if it was not retried.
+	Delay time.Duration `json:"delay"`
+	Time  time.Time     `json:"time"`
+}
+
+// RetryObserver is invoked for every decision made by a RetryStrategy.  It is invoked synchronously
+// from the SDK's IO routines so must be safe for concurrent use and should not block.
+// UNCOMMITTED: This API may change in the future.
+type RetryObserver func(decision RetryDecision)
+
+type retryHistory struct {
+	lock      sync.Mutex
+	decisions []RetryDecision
+}
+
+func (h *retryHistory) record(decision RetryDecision) {
+	h.lock.Lock()
+	h.decisions = append(h.decisions, decision)
+	h.lock.Unlock()
+}
+
+func (h *retryHistory) snapshot() []RetryDecision {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.decisions) == 0 {
+		return nil
+	}
+
+	decisions := make([]RetryDecision, len(h.decisions))
+	copy(decisions, h.decisions)
+	return decisions
+}
+
+// retries returns the number of retries which were decided and the distinct reasons for them.
+func (h *retryHistory) retries() (uint32, []RetryReason) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var attempts uint32
+	var reasons []RetryReason
+	for _, decision := range h.decisions {
+		if decision.Delay <= 0 {
+			continue
+		}
+
+		attempts++
+		if !containsRetryReason(reasons, decision.Reason) {
+			reasons = append(reasons, decision.Reason)
+		}
+	}
+
+	return attempts, reasons
+}
+
+func containsRetryReason(reasons []RetryReason, reason RetryReason) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attachHistory attaches the retry history of the operation to err, if err can carry it.
+func (rs *retryStrategyWrapper) attachHistory(err error) error {
+	if err == nil || rs == nil || rs.history == nil {
+		return err
+	}
+
+	history := rs.history.snapshot()
+	if history == nil {
+		return err
+	}
+
+	var kvErr *KeyValueError
+	if errors.As(err, &kvErr) {
+		kvErr.RetryHistory = history
+		return err
+	}
+
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		queryErr.RetryHistory = history
+		return err
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		timeoutErr.RetryHistory = history
+	}
+
+	return err
+}
@@ -10,7 +10,12 @@ type Scope struct {
 
 	transcoder           Transcoder
 	retryStrategyWrapper *retryStrategyWrapper
-	tracer               requestTracer
+	tracer               RequestTracer
+	meter                Meter
+	retryBudget          *RetryBudget
+	admission            *admissionController
+	runQuery             queryRunner
+	kvInterceptors       []KVInterceptor
 
 	useMutationTokens bool
 
@@ -30,6 +35,11 @@ func newScope(bucket *Bucket, scopeName string) *Scope {
 		transcoder:           bucket.transcoder,
 		retryStrategyWrapper: bucket.retryStrategyWrapper,
 		tracer:               bucket.tracer,
+		meter:                bucket.meter,
+		retryBudget:          bucket.retryBudget,
+		admission:            bucket.admission,
+		runQuery:             bucket.runQuery,
+		kvInterceptors:       bucket.kvInterceptors,
 
 		useMutationTokens: bucket.useMutationTokens,
 
@@ -54,7 +54,7 @@ type SearchOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 
-	parentSpan requestSpanContext
+	ParentSpan RequestSpanContext
 }
 
 func (opts *SearchOptions) toMap() (map[string]interface{}, error) {
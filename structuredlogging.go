@@ -0,0 +1,240 @@
+package gocb
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	gocbcore "github.com/couchbase/gocbcore/v9"
+)
+
+// LogSubsystem identifies the part of the SDK which emitted a log message.
+// UNCOMMITTED: This API may change in the future.
+type LogSubsystem string
+
+const (
+	// LogSubsystemSDK covers messages which do not belong to a more specific subsystem.
+	LogSubsystemSDK = LogSubsystem("sdk")
+
+	// LogSubsystemCore covers messages emitted by the underlying client, such as connection and
+	// bootstrap messages.
+	LogSubsystemCore = LogSubsystem("core")
+
+	// LogSubsystemKeyValue covers messages emitted by key-value operations.
+	LogSubsystemKeyValue = LogSubsystem("kv")
+
+	// LogSubsystemQuery covers messages emitted by query, analytics, search and view requests.
+	LogSubsystemQuery = LogSubsystem("query")
+
+	// LogSubsystemManagement covers messages emitted by the management APIs.
+	LogSubsystemManagement = LogSubsystem("management")
+
+	// LogSubsystemTelemetry covers messages emitted by the tracers, meters and orphan reporter.
+	LogSubsystemTelemetry = LogSubsystem("telemetry")
+)
+
+// The keys of the typed fields attached to structured log entries.
+const (
+	LogFieldBucket       = "bucket"
+	LogFieldScope        = "scope"
+	LogFieldCollection   = "collection"
+	LogFieldDocumentID   = "document_id"
+	LogFieldEndpoint     = "endpoint"
+	LogFieldOperation    = "operation"
+	LogFieldConnectionID = "connection_id"
+)
+
+// LogField is a single typed field attached to a structured log entry.
+// UNCOMMITTED: This API may change in the future.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// LogEntry is a single structured log message.
+// UNCOMMITTED: This API may change in the future.
+type LogEntry struct {
+	Level     LogLevel
+	Subsystem LogSubsystem
+
+	// Offset is the position within the calling stack from which the message originated.
+	Offset  int
+	Message string
+	Fields  []LogField
+}
+
+// StructuredLogger defines a logging interface which receives typed fields alongside each message.
+// UNCOMMITTED: This API may change in the future.
+type StructuredLogger interface {
+	LogStructured(entry *LogEntry) error
+}
+
+// SetStructuredLogger sets a structured logger to be used by the library, replacing any logger
+// set with SetLogger.  Messages from the underlying client are delivered with LogSubsystemCore
+// and no fields.
+// UNCOMMITTED: This API may change in the future.
+func SetStructuredLogger(logger StructuredLogger) {
+	globalLogger = nil
+	globalStructuredLogger = logger
+
+	if logger == nil {
+		gocbcore.SetLogger(&orphanLogger{})
+		return
+	}
+	gocbcore.SetLogger(&orphanLogger{wrapped: &structuredCoreLogger{wrapped: logger}})
+}
+
+type printfLoggerAdapter struct {
+	wrapped Logger
+}
+
+// NewPrintfLoggerAdapter returns a StructuredLogger which formats entries into messages for a
+// printf style Logger, with the subsystem and fields appended to the message.
+// UNCOMMITTED: This API may change in the future.
+func NewPrintfLoggerAdapter(logger Logger) StructuredLogger {
+	return &printfLoggerAdapter{
+		wrapped: logger,
+	}
+}
+
+func (a *printfLoggerAdapter) LogStructured(entry *LogEntry) error {
+	return a.wrapped.Log(entry.Level, entry.Offset+1, "%s", formatLogEntry(entry.Message, entry.Subsystem, entry.Fields))
+}
+
+type structuredCoreLogger struct {
+	wrapped StructuredLogger
+}
+
+func (l *structuredCoreLogger) Log(level gocbcore.LogLevel, offset int, format string, v ...interface{}) error {
+	return l.wrapped.LogStructured(&LogEntry{
+		Level:     LogLevel(level),
+		Subsystem: LogSubsystemCore,
+		Offset:    offset + 1,
+		Message:   fmt.Sprintf(format, v...),
+	})
+}
+
+var globalStructuredLogger StructuredLogger
+
+// withErrorLogFields appends the endpoint and connection which a failed operation was last dispatched
+// on to fields, where err records them.
+func withErrorLogFields(fields []LogField, err error) []LogField {
+	var endpoint, connectionID string
+	var kvErr *KeyValueError
+	var timeoutErr *TimeoutError
+	if errors.As(err, &kvErr) {
+		endpoint, connectionID = kvErr.LastDispatchedTo, kvErr.LastConnectionID
+	} else if errors.As(err, &timeoutErr) {
+		endpoint, connectionID = timeoutErr.LastDispatchedTo, timeoutErr.LastConnectionID
+	}
+
+	if endpoint != "" {
+		fields = append(fields, LogField{Key: LogFieldEndpoint, Value: redactSystemData(endpoint)})
+	}
+	if connectionID != "" {
+		fields = append(fields, LogField{Key: LogFieldConnectionID, Value: connectionID})
+	}
+
+	return fields
+}
+
+var subsystemLogLevels struct {
+	lock   sync.RWMutex
+	count  int32
+	levels map[LogSubsystem]LogLevel
+}
+
+// SetSubsystemLogLevel limits the messages logged by a subsystem to those at level or more severe.
+// Subsystems without a level log every message.
+// UNCOMMITTED: This API may change in the future.
+func SetSubsystemLogLevel(subsystem LogSubsystem, level LogLevel) {
+	subsystemLogLevels.lock.Lock()
+	if subsystemLogLevels.levels == nil {
+		subsystemLogLevels.levels = make(map[LogSubsystem]LogLevel)
+	}
+	subsystemLogLevels.levels[subsystem] = level
+	atomic.StoreInt32(&subsystemLogLevels.count, int32(len(subsystemLogLevels.levels)))
+	subsystemLogLevels.lock.Unlock()
+}
+
+// ClearSubsystemLogLevel removes the level set for a subsystem so that it logs every message.
+// UNCOMMITTED: This API may change in the future.
+func ClearSubsystemLogLevel(subsystem LogSubsystem) {
+	subsystemLogLevels.lock.Lock()
+	delete(subsystemLogLevels.levels, subsystem)
+	atomic.StoreInt32(&subsystemLogLevels.count, int32(len(subsystemLogLevels.levels)))
+	subsystemLogLevels.lock.Unlock()
+}
+
+func logLevelEnabled(subsystem LogSubsystem, level LogLevel) bool {
+	if atomic.LoadInt32(&subsystemLogLevels.count) == 0 {
+		return true
+	}
+
+	subsystemLogLevels.lock.RLock()
+	maxLevel, ok := subsystemLogLevels.levels[subsystem]
+	subsystemLogLevels.lock.RUnlock()
+
+	return !ok || level <= maxLevel
+}
+
+func formatLogEntry(message string, subsystem LogSubsystem, fields []LogField) string {
+	if subsystem == "" && len(fields) == 0 {
+		return message
+	}
+
+	var builder strings.Builder
+	builder.WriteString(message)
+	builder.WriteString(" [")
+	if subsystem != "" {
+		builder.WriteString("subsystem=")
+		builder.WriteString(string(subsystem))
+	}
+	for i, field := range fields {
+		if i > 0 || subsystem != "" {
+			builder.WriteByte(' ')
+		}
+		fmt.Fprintf(&builder, "%s=%v", field.Key, field.Value)
+	}
+	builder.WriteByte(']')
+
+	return builder.String()
+}
+
+func logFieldsExf(subsystem LogSubsystem, level LogLevel, offset int, fields []LogField, format string, v ...interface{}) {
+	if !logLevelEnabled(subsystem, level) {
+		return
+	}
+
+	if globalStructuredLogger != nil {
+		err := globalStructuredLogger.LogStructured(&LogEntry{
+			Level:     level,
+			Subsystem: subsystem,
+			Offset:    offset + 1,
+			Message:   fmt.Sprintf(format, v...),
+			Fields:    fields,
+		})
+		if err != nil {
+			log.Printf("Logger error occurred (%s)\n", err)
+		}
+		return
+	}
+
+	if globalLogger != nil {
+		if len(fields) > 0 {
+			format += "%s"
+			v = append(v[:len(v):len(v)], formatLogEntry("", "", fields))
+		}
+		err := globalLogger.Log(level, offset+1, format, v...)
+		if err != nil {
+			log.Printf("Logger error occurred (%s)\n", err)
+		}
+	}
+}
+
+func logFieldsf(subsystem LogSubsystem, level LogLevel, fields []LogField, format string, v ...interface{}) {
+	logFieldsExf(subsystem, level, 1, fields, format, v...)
+}
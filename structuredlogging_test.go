@@ -0,0 +1,121 @@
+package gocb
+
+import (
+	gocbcore "github.com/couchbase/gocbcore/v9"
+)
+
+type testStructuredLogger struct {
+	entries []*LogEntry
+}
+
+func (l *testStructuredLogger) LogStructured(entry *LogEntry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (suite *UnitTestSuite) setTestStructuredLogger(logger StructuredLogger) func() {
+	oldLogger := globalLogger
+	oldStructuredLogger := globalStructuredLogger
+	globalLogger = nil
+	globalStructuredLogger = logger
+	return func() {
+		globalLogger = oldLogger
+		globalStructuredLogger = oldStructuredLogger
+	}
+}
+
+func (suite *UnitTestSuite) TestStructuredLoggerReceivesFields() {
+	logger := &testStructuredLogger{}
+	defer suite.setTestStructuredLogger(logger)()
+
+	fields := []LogField{
+		{Key: LogFieldBucket, Value: "default"},
+		{Key: LogFieldDocumentID, Value: "key"},
+	}
+	logFieldsf(LogSubsystemKeyValue, LogWarn, fields, "something %s", "happened")
+
+	suite.Require().Len(logger.entries, 1)
+	entry := logger.entries[0]
+	suite.Assert().Equal(LogWarn, entry.Level)
+	suite.Assert().Equal(LogSubsystemKeyValue, entry.Subsystem)
+	suite.Assert().Equal("something happened", entry.Message)
+	suite.Assert().Equal(fields, entry.Fields)
+}
+
+func (suite *UnitTestSuite) TestSubsystemLogLevels() {
+	logger := &testStructuredLogger{}
+	defer suite.setTestStructuredLogger(logger)()
+
+	SetSubsystemLogLevel(LogSubsystemQuery, LogWarn)
+	defer ClearSubsystemLogLevel(LogSubsystemQuery)
+
+	logFieldsf(LogSubsystemQuery, LogDebug, nil, "dropped")
+	logFieldsf(LogSubsystemQuery, LogError, nil, "kept")
+	logFieldsf(LogSubsystemKeyValue, LogDebug, nil, "other subsystem")
+
+	suite.Require().Len(logger.entries, 2)
+	suite.Assert().Equal("kept", logger.entries[0].Message)
+	suite.Assert().Equal("other subsystem", logger.entries[1].Message)
+
+	core := &testStructuredLogger{}
+	SetSubsystemLogLevel(LogSubsystemCore, LogInfo)
+	defer ClearSubsystemLogLevel(LogSubsystemCore)
+
+	coreLog := &orphanLogger{wrapped: &structuredCoreLogger{wrapped: core}}
+	suite.Require().Nil(coreLog.Log(gocbcore.LogDebug, 0, "dropped"))
+	suite.Require().Nil(coreLog.Log(gocbcore.LogWarn, 0, "connection %d lost", 1))
+	suite.Require().Len(core.entries, 1)
+	suite.Assert().Equal(LogSubsystemCore, core.entries[0].Subsystem)
+	suite.Assert().Equal("connection 1 lost", core.entries[0].Message)
+}
+
+func (suite *UnitTestSuite) TestPrintfLoggerAdapter() {
+	logger := &testCaptureLogger{}
+	adapter := NewPrintfLoggerAdapter(logger)
+
+	err := adapter.LogStructured(&LogEntry{
+		Level:     LogInfo,
+		Subsystem: LogSubsystemKeyValue,
+		Message:   "100% done",
+		Fields: []LogField{
+			{Key: LogFieldEndpoint, Value: "10.112.191.102:11210"},
+			{Key: LogFieldConnectionID, Value: "a1b2/c3d4"},
+		},
+	})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(logger.messages, 1)
+	suite.Assert().Equal("100% done [subsystem=kv endpoint=10.112.191.102:11210 connection_id=a1b2/c3d4]", logger.messages[0])
+}
+
+func (suite *UnitTestSuite) TestPrintfLoggerReceivesFields() {
+	logger := &testCaptureLogger{}
+	oldLogger := globalLogger
+	globalLogger = logger
+	defer func() {
+		globalLogger = oldLogger
+	}()
+
+	logFieldsf(LogSubsystemKeyValue, LogWarn, []LogField{{Key: LogFieldDocumentID, Value: "key"}}, "timeout of %d%%", 5)
+	logWarnf("plain %s", "message")
+
+	suite.Require().Len(logger.messages, 2)
+	suite.Assert().Equal("timeout of 5% [document_id=key]", logger.messages[0])
+	suite.Assert().Equal("plain message", logger.messages[1])
+}
+
+func (suite *UnitTestSuite) TestErrorLogFields() {
+	err := maybeEnhanceKVErr(&gocbcore.KeyValueError{
+		InnerError:       gocbcore.ErrDocumentNotFound,
+		LastDispatchedTo: "10.112.191.101:11210",
+		LastConnectionID: "d323bee8e92a20d6/7b3c0ac5e0af6c5e",
+	}, "", "", "", "key")
+
+	fields := withErrorLogFields([]LogField{{Key: LogFieldOperation, Value: "Get"}}, err)
+	suite.Assert().Equal([]LogField{
+		{Key: LogFieldOperation, Value: "Get"},
+		{Key: LogFieldEndpoint, Value: "10.112.191.101:11210"},
+		{Key: LogFieldConnectionID, Value: "d323bee8e92a20d6/7b3c0ac5e0af6c5e"},
+	}, fields)
+
+	suite.Assert().Empty(withErrorLogFields(nil, ErrTimeout))
+}
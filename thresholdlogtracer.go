@@ -1,7 +1,7 @@
 package gocb
 
 import (
-	"encoding/json"
+	"io"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -11,6 +11,7 @@ import (
 type thresholdLogGroup struct {
 	name  string
 	floor time.Duration
+	size  uint32
 	ops   []*thresholdLogSpan
 	lock  sync.RWMutex
 }
@@ -18,17 +19,38 @@ type thresholdLogGroup struct {
 func (g *thresholdLogGroup) init(name string, floor time.Duration, size uint32) {
 	g.name = name
 	g.floor = floor
+	g.size = size
 	g.ops = make([]*thresholdLogSpan, 0, size)
 }
 
-func (g *thresholdLogGroup) recordOp(span *thresholdLogSpan) {
-	if span.duration < g.floor {
-		return
+func (g *thresholdLogGroup) setFloor(floor time.Duration) {
+	g.lock.Lock()
+	g.floor = floor
+	g.lock.Unlock()
+}
+
+func (g *thresholdLogGroup) setSize(size uint32) {
+	g.lock.Lock()
+	ops := make([]*thresholdLogSpan, 0, size)
+	// Our ops are sorted fastest first so we keep the slowest ops that still fit.
+	if uint32(len(g.ops)) > size {
+		ops = append(ops, g.ops[uint32(len(g.ops))-size:]...)
+	} else {
+		ops = append(ops, g.ops...)
 	}
+	g.ops = ops
+	g.size = size
+	g.lock.Unlock()
+}
 
+func (g *thresholdLogGroup) recordOp(span *thresholdLogSpan) {
 	// Preemptively check that we actually need to be inserted using a read lock first
 	// this is a performance improvement measure to avoid locking the mutex all the time.
 	g.lock.RLock()
+	if span.duration < g.floor || cap(g.ops) == 0 {
+		g.lock.RUnlock()
+		return
+	}
 	if len(g.ops) == cap(g.ops) && span.duration < g.ops[0].duration {
 		// we are at capacity and we are faster than the fastest slow op
 		g.lock.RUnlock()
@@ -37,6 +59,10 @@ func (g *thresholdLogGroup) recordOp(span *thresholdLogSpan) {
 	g.lock.RUnlock()
 
 	g.lock.Lock()
+	if span.duration < g.floor || cap(g.ops) == 0 {
+		g.lock.Unlock()
+		return
+	}
 	if len(g.ops) == cap(g.ops) && span.duration < g.ops[0].duration {
 		// we are at capacity and we are faster than the fastest slow op
 		g.lock.Unlock()
@@ -68,6 +94,45 @@ func (g *thresholdLogGroup) recordOp(span *thresholdLogSpan) {
 	g.lock.Unlock()
 }
 
+// ThresholdLogItem describes a single operation which exceeded its service's threshold.
+type ThresholdLogItem struct {
+	OperationName        string
+	TotalTime            time.Duration
+	EncodeDuration       time.Duration
+	DispatchDuration     time.Duration
+	ServerDuration       time.Duration
+	LastRemoteAddress    string
+	LastLocalAddress     string
+	LastDispatchDuration time.Duration
+	LastOperationID      string
+	LastLocalID          string
+	DocumentKey          string
+}
+
+// ThresholdLogReport is the set of the slowest operations, slowest first, which exceeded the threshold
+// for a service during a single reporting interval.
+type ThresholdLogReport struct {
+	Service string
+	Top     []ThresholdLogItem
+}
+
+// ThresholdLogSink receives the reports generated by a ThresholdLoggingTracer at the end of every
+// interval.  Only services which had operations exceed their threshold are included.  Sinks are
+// invoked sequentially from the tracer's reporting routine and should not block.
+// UNCOMMITTED: This API may change in the future.
+type ThresholdLogSink interface {
+	ReportThresholdLogs(reports []ThresholdLogReport)
+}
+
+// ThresholdLogSinkFunc is an adapter which allows a function to be used as a ThresholdLogSink.
+// UNCOMMITTED: This API may change in the future.
+type ThresholdLogSinkFunc func(reports []ThresholdLogReport)
+
+// ReportThresholdLogs belongs to the ThresholdLogSink interface.
+func (f ThresholdLogSinkFunc) ReportThresholdLogs(reports []ThresholdLogReport) {
+	f(reports)
+}
+
 type thresholdLogItem struct {
 	OperationName          string `json:"operation_name,omitempty"`
 	TotalTimeUs            uint64 `json:"total_us,omitempty"`
@@ -88,56 +153,145 @@ type thresholdLogService struct {
 	Top     []thresholdLogItem `json:"top"`
 }
 
-func (g *thresholdLogGroup) logRecordedRecords(sampleSize uint32) {
-	// Preallocate space to copy the ops into...
-	oldOps := make([]*thresholdLogSpan, sampleSize)
+func thresholdLogReportJSON(report ThresholdLogReport) ([]byte, error) {
+	jsonData := thresholdLogService{
+		Service: report.Service,
+		Count:   uint64(len(report.Top)),
+	}
+
+	for _, item := range report.Top {
+		jsonData.Top = append(jsonData.Top, thresholdLogItem{
+			OperationName:          item.OperationName,
+			TotalTimeUs:            uint64(item.TotalTime / time.Microsecond),
+			DispatchDurationUs:     uint64(item.DispatchDuration / time.Microsecond),
+			ServerDurationUs:       uint64(item.ServerDuration / time.Microsecond),
+			EncodeDurationUs:       uint64(item.EncodeDuration / time.Microsecond),
+			LastRemoteAddress:      redactOptional(item.LastRemoteAddress, redactSystemData),
+			LastLocalAddress:       redactOptional(item.LastLocalAddress, redactSystemData),
+			LastDispatchDurationUs: uint64(item.LastDispatchDuration / time.Microsecond),
+			LastOperationID:        item.LastOperationID,
+			LastLocalID:            item.LastLocalID,
+			DocumentKey:            redactOptional(item.DocumentKey, redactUserData),
+		})
+	}
+
+	return marshalRedactedJSON(jsonData)
+}
+
+type thresholdLogLoggerSink struct{}
+
+// NewThresholdLogLoggerSink returns a ThresholdLogSink which writes each report to the SDK logger as
+// JSON.  This is the sink used when no sinks are configured.
+// UNCOMMITTED: This API may change in the future.
+func NewThresholdLogLoggerSink() ThresholdLogSink {
+	return thresholdLogLoggerSink{}
+}
+
+func (s thresholdLogLoggerSink) ReportThresholdLogs(reports []ThresholdLogReport) {
+	for _, report := range reports {
+		jsonBytes, err := thresholdLogReportJSON(report)
+		if err != nil {
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to generate threshold logging service JSON: %s", err)
+		}
+
+		logFieldsf(LogSubsystemTelemetry, LogInfo, nil, "Threshold Log: %s", jsonBytes)
+	}
+}
+
+type thresholdLogWriterSink struct {
+	writer io.Writer
+	lock   sync.Mutex
+}
+
+// NewThresholdLogWriterSink returns a ThresholdLogSink which writes each report to w as a single line
+// of JSON, for example to append reports to a file.
+// UNCOMMITTED: This API may change in the future.
+func NewThresholdLogWriterSink(w io.Writer) ThresholdLogSink {
+	return &thresholdLogWriterSink{
+		writer: w,
+	}
+}
 
+func (s *thresholdLogWriterSink) ReportThresholdLogs(reports []ThresholdLogReport) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, report := range reports {
+		jsonBytes, err := thresholdLogReportJSON(report)
+		if err != nil {
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to generate threshold logging service JSON: %s", err)
+			continue
+		}
+
+		_, err = s.writer.Write(append(jsonBytes, '\n'))
+		if err != nil {
+			logFieldsf(LogSubsystemTelemetry, LogWarn, nil, "Failed to write threshold log report: %s", err)
+		}
+	}
+}
+
+type thresholdLogChannelSink struct {
+	ch chan<- []ThresholdLogReport
+}
+
+// NewThresholdLogChannelSink returns a ThresholdLogSink which sends the reports for each interval to
+// ch.  Reports are dropped, rather than blocking the tracer, if ch is not ready to receive them.
+// UNCOMMITTED: This API may change in the future.
+func NewThresholdLogChannelSink(ch chan<- []ThresholdLogReport) ThresholdLogSink {
+	return &thresholdLogChannelSink{
+		ch: ch,
+	}
+}
+
+func (s *thresholdLogChannelSink) ReportThresholdLogs(reports []ThresholdLogReport) {
+	select {
+	case s.ch <- reports:
+	default:
+		logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Dropped threshold log reports as the channel was not ready")
+	}
+}
+
+func (g *thresholdLogGroup) takeRecords() *ThresholdLogReport {
 	g.lock.Lock()
-	// Escape early if we have no ops to log...
+	// Escape early if we have no ops to report...
 	if len(g.ops) == 0 {
 		g.lock.Unlock()
-		return
+		return nil
 	}
 
-	// Copy out our ops so we can cheaply print them out without blocking
+	// Copy out our ops so we can cheaply report them without blocking
 	// our ops from actually being recorded in other goroutines (which would
 	// effectively slow down the op pipeline for logging).
 
-	oldOps = oldOps[0:len(g.ops)]
+	oldOps := make([]*thresholdLogSpan, len(g.ops))
 	copy(oldOps, g.ops)
 	g.ops = g.ops[:0]
 
 	g.lock.Unlock()
 
-	jsonData := thresholdLogService{
+	report := &ThresholdLogReport{
 		Service: g.name,
 	}
 
 	for i := len(oldOps) - 1; i >= 0; i-- {
 		op := oldOps[i]
 
-		jsonData.Top = append(jsonData.Top, thresholdLogItem{
-			OperationName:          op.opName,
-			TotalTimeUs:            uint64(op.duration / time.Microsecond),
-			DispatchDurationUs:     uint64(op.totalDispatchDuration / time.Microsecond),
-			ServerDurationUs:       uint64(op.totalServerDuration / time.Microsecond),
-			EncodeDurationUs:       uint64(op.totalEncodeDuration / time.Microsecond),
-			LastRemoteAddress:      op.lastDispatchPeer,
-			LastDispatchDurationUs: uint64(op.lastDispatchDuration / time.Microsecond),
-			LastOperationID:        op.lastOperationID,
-			LastLocalID:            op.lastLocalID,
-			DocumentKey:            op.documentKey,
+		report.Top = append(report.Top, ThresholdLogItem{
+			OperationName:        op.opName,
+			TotalTime:            op.duration,
+			DispatchDuration:     op.totalDispatchDuration,
+			ServerDuration:       op.totalServerDuration,
+			EncodeDuration:       op.totalEncodeDuration,
+			LastRemoteAddress:    op.lastDispatchPeer,
+			LastLocalAddress:     op.lastDispatchLocal,
+			LastDispatchDuration: op.lastDispatchDuration,
+			LastOperationID:      op.lastOperationID,
+			LastLocalID:          op.lastLocalID,
+			DocumentKey:          op.documentKey,
 		})
 	}
 
-	jsonData.Count = uint64(len(jsonData.Top))
-
-	jsonBytes, err := json.Marshal(jsonData)
-	if err != nil {
-		logDebugf("Failed to generate threshold logging service JSON: %s", err)
-	}
-
-	logInfof("Threshold Log: %s", jsonBytes)
+	return report
 }
 
 // ThresholdLoggingOptions is the set of options available for configuring threshold logging.
@@ -151,12 +305,18 @@ type ThresholdLoggingOptions struct {
 	SearchThreshold        time.Duration
 	AnalyticsThreshold     time.Duration
 	ManagementThreshold    time.Duration
+
+	// Sinks are the destinations to which reports are delivered, the default is to write them to the
+	// SDK logger.
+	// UNCOMMITTED: This API may change in the future.
+	Sinks []ThresholdLogSink
 }
 
 // ThresholdLoggingTracer is a specialized Tracer implementation which will automatically
 // log operations which fall outside of a set of thresholds.  Note that this tracer is
 // only safe for use within the Couchbase SDK, uses by external event sources are
-// likely to fail.
+// likely to fail.  The threshold and sample size fields reflect the initial configuration,
+// SetThreshold and SetSampleSize must be used to adjust them once the tracer is created.
 type ThresholdLoggingTracer struct {
 	Interval            time.Duration
 	SampleSize          uint32
@@ -167,6 +327,7 @@ type ThresholdLoggingTracer struct {
 	AnalyticsThreshold  time.Duration
 	ManagementThreshold time.Duration
 
+	sinks           []ThresholdLogSink
 	killCh          chan struct{}
 	refCount        int32
 	nextTick        time.Time
@@ -207,6 +368,11 @@ func NewThresholdLoggingTracer(opts *ThresholdLoggingOptions) *ThresholdLoggingT
 		opts.ManagementThreshold = 1 * time.Second
 	}
 
+	sinks := opts.Sinks
+	if len(sinks) == 0 {
+		sinks = []ThresholdLogSink{NewThresholdLogLoggerSink()}
+	}
+
 	t := &ThresholdLoggingTracer{
 		Interval:            opts.Interval,
 		SampleSize:          opts.SampleSize,
@@ -216,6 +382,7 @@ func NewThresholdLoggingTracer(opts *ThresholdLoggingOptions) *ThresholdLoggingT
 		SearchThreshold:     opts.SearchThreshold,
 		AnalyticsThreshold:  opts.AnalyticsThreshold,
 		ManagementThreshold: opts.ManagementThreshold,
+		sinks:               sinks,
 	}
 
 	t.kvGroup.init("kv", t.KVThreshold, t.SampleSize)
@@ -256,13 +423,71 @@ func (t *ThresholdLoggingTracer) DecRef() int32 {
 	return newRefCount
 }
 
+func (t *ThresholdLoggingTracer) serviceGroup(service ServiceType) (*thresholdLogGroup, error) {
+	switch service {
+	case ServiceTypeKeyValue:
+		return &t.kvGroup, nil
+	case ServiceTypeViews:
+		return &t.viewsGroup, nil
+	case ServiceTypeQuery:
+		return &t.queryGroup, nil
+	case ServiceTypeSearch:
+		return &t.searchGroup, nil
+	case ServiceTypeAnalytics:
+		return &t.analyticsGroup, nil
+	case ServiceTypeManagement:
+		return &t.managementGroup, nil
+	}
+
+	return nil, makeInvalidArgumentsError("unknown service type")
+}
+
+// SetThreshold changes the threshold above which operations on a service are reported.
+// UNCOMMITTED: This API may change in the future.
+func (t *ThresholdLoggingTracer) SetThreshold(service ServiceType, threshold time.Duration) error {
+	group, err := t.serviceGroup(service)
+	if err != nil {
+		return err
+	}
+
+	group.setFloor(threshold)
+	return nil
+}
+
+// SetSampleSize changes the maximum number of operations reported for a service in each interval.
+// UNCOMMITTED: This API may change in the future.
+func (t *ThresholdLoggingTracer) SetSampleSize(service ServiceType, size uint32) error {
+	group, err := t.serviceGroup(service)
+	if err != nil {
+		return err
+	}
+
+	group.setSize(size)
+	return nil
+}
+
 func (t *ThresholdLoggingTracer) logRecordedRecords() {
-	t.kvGroup.logRecordedRecords(t.SampleSize)
-	t.viewsGroup.logRecordedRecords(t.SampleSize)
-	t.queryGroup.logRecordedRecords(t.SampleSize)
-	t.searchGroup.logRecordedRecords(t.SampleSize)
-	t.analyticsGroup.logRecordedRecords(t.SampleSize)
-	t.managementGroup.logRecordedRecords(t.SampleSize)
+	var reports []ThresholdLogReport
+	for _, group := range []*thresholdLogGroup{
+		&t.kvGroup,
+		&t.viewsGroup,
+		&t.queryGroup,
+		&t.searchGroup,
+		&t.analyticsGroup,
+		&t.managementGroup,
+	} {
+		if report := group.takeRecords(); report != nil {
+			reports = append(reports, *report)
+		}
+	}
+
+	if len(reports) == 0 {
+		return
+	}
+
+	for _, sink := range t.sinks {
+		sink.ReportThresholdLogs(reports)
+	}
 }
 
 func (t *ThresholdLoggingTracer) startLoggerRoutine() {
@@ -300,7 +525,7 @@ func (t *ThresholdLoggingTracer) recordOp(span *thresholdLogSpan) {
 }
 
 // StartSpan belongs to the Tracer interface.
-func (t *ThresholdLoggingTracer) StartSpan(operationName string, parentContext requestSpanContext) requestSpan {
+func (t *ThresholdLoggingTracer) StartSpan(operationName string, parentContext RequestSpanContext) RequestSpan {
 	span := &thresholdLogSpan{
 		tracer:    t,
 		opName:    operationName,
@@ -321,12 +546,14 @@ type thresholdLogSpan struct {
 	startTime             time.Time
 	serviceName           string
 	peerAddress           string
+	localAddress          string
 	serverDuration        time.Duration
 	duration              time.Duration
 	totalServerDuration   time.Duration
 	totalDispatchDuration time.Duration
 	totalEncodeDuration   time.Duration
 	lastDispatchPeer      string
+	lastDispatchLocal     string
 	lastDispatchDuration  time.Duration
 	lastOperationID       string
 	lastLocalID           string
@@ -334,37 +561,41 @@ type thresholdLogSpan struct {
 	lock                  sync.Mutex
 }
 
-func (n *thresholdLogSpan) Context() requestSpanContext {
+func (n *thresholdLogSpan) Context() RequestSpanContext {
 	return &thresholdLogSpanContext{n}
 }
 
-func (n *thresholdLogSpan) SetTag(key string, value interface{}) requestSpan {
+func (n *thresholdLogSpan) SetTag(key string, value interface{}) RequestSpan {
 	var ok bool
 
 	switch key {
 	case "server_duration":
 		if n.serverDuration, ok = value.(time.Duration); !ok {
-			logDebugf("Failed to cast span server_duration tag")
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span server_duration tag")
 		}
 	case "couchbase.service":
 		if n.serviceName, ok = value.(string); !ok {
-			logDebugf("Failed to cast span couchbase.service tag")
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span couchbase.service tag")
 		}
 	case "peer.address":
 		if n.peerAddress, ok = value.(string); !ok {
-			logDebugf("Failed to cast span peer.address tag")
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span peer.address tag")
+		}
+	case "local.address":
+		if n.localAddress, ok = value.(string); !ok {
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span local.address tag")
 		}
 	case "couchbase.operation_id":
 		if n.lastOperationID, ok = value.(string); !ok {
-			logDebugf("Failed to cast span couchbase.operation_id tag")
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span couchbase.operation_id tag")
 		}
 	case "couchbase.document_key":
 		if n.documentKey, ok = value.(string); !ok {
-			logDebugf("Failed to cast span couchbase.document_key tag")
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span couchbase.document_key tag")
 		}
 	case "couchbase.local_id":
 		if n.lastLocalID, ok = value.(string); !ok {
-			logDebugf("Failed to cast span couchbase.local_id tag")
+			logFieldsf(LogSubsystemTelemetry, LogDebug, nil, "Failed to cast span couchbase.local_id tag")
 		}
 	}
 	return n
@@ -377,6 +608,7 @@ func (n *thresholdLogSpan) Finish() {
 	if n.opName == "dispatch" {
 		n.totalDispatchDuration += n.duration
 		n.lastDispatchPeer = n.peerAddress
+		n.lastDispatchLocal = n.localAddress
 		n.lastDispatchDuration = n.duration
 	}
 	if n.opName == "encode" {
@@ -390,6 +622,7 @@ func (n *thresholdLogSpan) Finish() {
 		n.parent.totalEncodeDuration += n.totalEncodeDuration
 		if n.lastDispatchPeer != "" || n.lastDispatchDuration > 0 {
 			n.parent.lastDispatchPeer = n.lastDispatchPeer
+			n.parent.lastDispatchLocal = n.lastDispatchLocal
 			n.parent.lastDispatchDuration = n.lastDispatchDuration
 		}
 		if n.lastOperationID != "" {
@@ -1,6 +1,9 @@
 package gocb
 
 import (
+	"bytes"
+	"errors"
+	"strings"
 	"time"
 )
 
@@ -35,3 +38,105 @@ func (suite *UnitTestSuite) TestThresholdGroup() {
 		suite.T().Fatalf("Failed to insert in correct order (3)")
 	}
 }
+
+func (suite *UnitTestSuite) TestThresholdGroupSetSize() {
+	var grp thresholdLogGroup
+	grp.init("Test", time.Millisecond, 3)
+	grp.recordOp(&thresholdLogSpan{duration: 2 * time.Millisecond})
+	grp.recordOp(&thresholdLogSpan{duration: 4 * time.Millisecond})
+	grp.recordOp(&thresholdLogSpan{duration: 3 * time.Millisecond})
+
+	grp.setSize(2)
+	suite.Require().Len(grp.ops, 2)
+	suite.Assert().Equal(3*time.Millisecond, grp.ops[0].duration)
+	suite.Assert().Equal(4*time.Millisecond, grp.ops[1].duration)
+
+	grp.setSize(0)
+	grp.recordOp(&thresholdLogSpan{duration: 5 * time.Millisecond})
+	suite.Assert().Empty(grp.ops)
+}
+
+func (suite *UnitTestSuite) TestThresholdLoggingTracerSinks() {
+	var callbackReports []ThresholdLogReport
+	ch := make(chan []ThresholdLogReport, 1)
+	var buf bytes.Buffer
+
+	tracer := NewThresholdLoggingTracer(&ThresholdLoggingOptions{
+		KVThreshold: time.Hour,
+		Sinks: []ThresholdLogSink{
+			ThresholdLogSinkFunc(func(reports []ThresholdLogReport) {
+				callbackReports = reports
+			}),
+			NewThresholdLogChannelSink(ch),
+			NewThresholdLogWriterSink(&buf),
+		},
+	})
+
+	tracer.recordOp(&thresholdLogSpan{serviceName: "kv", opName: "Get", duration: time.Second})
+	suite.Assert().Empty(tracer.kvGroup.ops)
+
+	suite.Require().Nil(tracer.SetThreshold(ServiceTypeKeyValue, time.Millisecond))
+	suite.Require().Nil(tracer.SetSampleSize(ServiceTypeQuery, 1))
+	suite.Assert().True(errors.Is(tracer.SetThreshold(ServiceType(100), time.Second), ErrInvalidArgument))
+
+	tracer.recordOp(&thresholdLogSpan{serviceName: "kv", opName: "Get", duration: time.Second, documentKey: "key"})
+	tracer.recordOp(&thresholdLogSpan{serviceName: "query", opName: "Query", duration: 2 * time.Second})
+	tracer.recordOp(&thresholdLogSpan{serviceName: "query", opName: "Query", duration: 3 * time.Second})
+
+	tracer.logRecordedRecords()
+
+	suite.Require().Len(callbackReports, 2)
+	suite.Assert().Equal("kv", callbackReports[0].Service)
+	suite.Require().Len(callbackReports[0].Top, 1)
+	suite.Assert().Equal(ThresholdLogItem{
+		OperationName: "Get",
+		TotalTime:     time.Second,
+		DocumentKey:   "key",
+	}, callbackReports[0].Top[0])
+	suite.Assert().Equal("query", callbackReports[1].Service)
+	suite.Require().Len(callbackReports[1].Top, 1)
+	suite.Assert().Equal(3*time.Second, callbackReports[1].Top[0].TotalTime)
+
+	select {
+	case reports := <-ch:
+		suite.Assert().Equal(callbackReports, reports)
+	default:
+		suite.T().Fatalf("Expected reports to be sent to the channel")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	suite.Require().Len(lines, 2)
+	suite.Assert().Equal(`{"service":"kv","count":1,"top":[{"operation_name":"Get","total_us":1000000,"document_key":"key"}]}`, lines[0])
+
+	// Nothing was recorded so the sinks are not invoked again.
+	callbackReports = nil
+	tracer.logRecordedRecords()
+	suite.Assert().Nil(callbackReports)
+}
+
+func (suite *UnitTestSuite) TestThresholdLogSpanDispatchAddresses() {
+	var reports []ThresholdLogReport
+	tracer := NewThresholdLoggingTracer(&ThresholdLoggingOptions{
+		KVThreshold: time.Nanosecond,
+		Sinks: []ThresholdLogSink{
+			ThresholdLogSinkFunc(func(r []ThresholdLogReport) {
+				reports = r
+			}),
+		},
+	})
+
+	span := tracer.StartSpan("Get", nil).SetTag("couchbase.service", "kv")
+	dispatch := tracer.StartSpan("dispatch", span.Context()).
+		SetTag("peer.address", "10.112.191.101:11210").
+		SetTag("local.address", "10.112.191.9:53412")
+	time.Sleep(time.Millisecond)
+	dispatch.Finish()
+	span.Finish()
+
+	tracer.logRecordedRecords()
+
+	suite.Require().Len(reports, 1)
+	suite.Require().Len(reports[0].Top, 1)
+	suite.Assert().Equal("10.112.191.101:11210", reports[0].Top[0].LastRemoteAddress)
+	suite.Assert().Equal("10.112.191.9:53412", reports[0].Top[0].LastLocalAddress)
+}
@@ -4,7 +4,7 @@ import (
 	"github.com/couchbase/gocbcore/v9"
 )
 
-func tracerAddRef(tracer requestTracer) {
+func tracerAddRef(tracer RequestTracer) {
 	if tracer == nil {
 		return
 	}
@@ -15,7 +15,7 @@ func tracerAddRef(tracer requestTracer) {
 	}
 }
 
-func tracerDecRef(tracer requestTracer) {
+func tracerDecRef(tracer RequestTracer) {
 	if tracer == nil {
 		return
 	}
@@ -26,24 +26,29 @@ func tracerDecRef(tracer requestTracer) {
 	}
 }
 
-// requestTracer describes the tracing abstraction in the SDK.
-type requestTracer interface {
-	StartSpan(operationName string, parentContext requestSpanContext) requestSpan
+// RequestTracer describes the tracing abstraction in the SDK.  Applications can provide their own
+// implementation through ClusterOptions.Tracer.
+// UNCOMMITTED: This API may change in the future.
+type RequestTracer interface {
+	StartSpan(operationName string, parentContext RequestSpanContext) RequestSpan
 }
 
-// requestSpan is the interface for spans that are created by a requestTracer.
-type requestSpan interface {
+// RequestSpan is the interface for spans that are created by a RequestTracer.
+// UNCOMMITTED: This API may change in the future.
+type RequestSpan interface {
 	Finish()
-	Context() requestSpanContext
-	SetTag(key string, value interface{}) requestSpan
+	Context() RequestSpanContext
+	SetTag(key string, value interface{}) RequestSpan
 }
 
-// requestSpanContext is the interface for for external span contexts that can be passed in into the SDK option blocks.
-type requestSpanContext interface {
+// RequestSpanContext is the interface for external span contexts that can be passed in into the SDK option blocks.
+// The SDK will create the span for an operation as a child of the ParentSpan specified in its options.
+// UNCOMMITTED: This API may change in the future.
+type RequestSpanContext interface {
 }
 
 type requestTracerWrapper struct {
-	tracer requestTracer
+	tracer RequestTracer
 }
 
 func (tracer *requestTracerWrapper) StartSpan(operationName string, parentContext gocbcore.RequestSpanContext) gocbcore.RequestSpan {
@@ -53,7 +58,7 @@ func (tracer *requestTracerWrapper) StartSpan(operationName string, parentContex
 }
 
 type requestSpanWrapper struct {
-	span requestSpan
+	span RequestSpan
 }
 
 func (span requestSpanWrapper) Finish() {
@@ -81,17 +86,17 @@ var (
 type noopTracer struct { // nolint: unused
 }
 
-func (tracer *noopTracer) StartSpan(operationName string, parentContext requestSpanContext) requestSpan {
+func (tracer *noopTracer) StartSpan(operationName string, parentContext RequestSpanContext) RequestSpan {
 	return defaultNoopSpan
 }
 
 func (span noopSpan) Finish() {
 }
 
-func (span noopSpan) Context() requestSpanContext {
+func (span noopSpan) Context() RequestSpanContext {
 	return defaultNoopSpanContext
 }
 
-func (span noopSpan) SetTag(key string, value interface{}) requestSpan {
+func (span noopSpan) SetTag(key string, value interface{}) RequestSpan {
 	return defaultNoopSpan
 }
@@ -69,7 +69,7 @@ type ViewOptions struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 
-	parentSpan requestSpanContext
+	ParentSpan RequestSpanContext
 }
 
 func (opts *ViewOptions) toURLValues() (*url.Values, error) {